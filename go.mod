@@ -3,8 +3,11 @@ module github.com/jashort/jrnlg
 go 1.25.5
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/alecthomas/kong v1.14.0
 	github.com/olebedev/when v1.1.0
+	golang.org/x/crypto v0.47.0
+	golang.org/x/net v0.49.0
 	golang.org/x/term v0.39.0
 )
 
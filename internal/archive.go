@@ -0,0 +1,425 @@
+package internal
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Year archives pack a year's directory into <year>.tar.gz in the storage root.
+// Archived entries are read from the archive at the paths they had on disk, so listing,
+// searching and the index work as before, but they can't be changed until the year is
+// unarchived.
+
+// ArchiveExt is the extension of year archives
+const ArchiveExt = ".tar.gz"
+
+// yearArchiveName matches year archives in the storage root
+var yearArchiveName = regexp.MustCompile(`^(\d{4})\.tar\.gz$`)
+
+// yearDirName matches the year directories of all layouts but flat
+var yearDirName = regexp.MustCompile(`^\d{4}$`)
+
+// ErrArchived is returned when changing files in an archived year
+var ErrArchived = errors.New("entries in archived years are read-only")
+
+// yearArchive is the content of a year archive, read into memory on first use
+type yearArchive struct {
+	files map[string][]byte // Stored content by path in the storage tree
+	paths []string          // Sorted
+}
+
+// archivePath returns the path of the archive for a year
+func (fs *FileSystemStorage) archivePath(year string) string {
+	return filepath.Join(fs.basePath, year+ArchiveExt)
+}
+
+// ArchivedYears returns the archived years, oldest first
+func (fs *FileSystemStorage) ArchivedYears() ([]int, error) {
+	dirEntries, err := os.ReadDir(fs.basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var years []int
+	for _, dirEntry := range dirEntries {
+		match := yearArchiveName.FindStringSubmatch(dirEntry.Name())
+		if match == nil || !dirEntry.Type().IsRegular() {
+			continue
+		}
+		year, _ := strconv.Atoi(match[1])
+		years = append(years, year)
+	}
+	return years, nil
+}
+
+// isYearArchive reports whether path is a year archive in the storage root
+func (fs *FileSystemStorage) isYearArchive(path string) bool {
+	return filepath.Dir(path) == fs.basePath && yearArchiveName.MatchString(filepath.Base(path))
+}
+
+// archivedYearOf returns the year of a path in an archived year's directory
+func (fs *FileSystemStorage) archivedYearOf(path string) (string, bool) {
+	rel, err := filepath.Rel(fs.basePath, path)
+	if err != nil {
+		return "", false
+	}
+	year, _, nested := strings.Cut(filepath.ToSlash(rel), "/")
+	if !nested || !yearDirName.MatchString(year) {
+		return "", false
+	}
+	if _, err := os.Stat(fs.archivePath(year)); err != nil {
+		return "", false
+	}
+	return year, true
+}
+
+// checkWritable returns ErrArchived if a path is in an archived year
+func (fs *FileSystemStorage) checkWritable(paths ...string) error {
+	for _, path := range paths {
+		if year, ok := fs.archivedYearOf(path); ok {
+			return fmt.Errorf("%s: %w (run 'jrnlg unarchive %s' first)", fs.relativePath(path), ErrArchived, year)
+		}
+	}
+	return nil
+}
+
+// checkNoArchives refuses operations that rewrite every entry while years are archived
+func (fs *FileSystemStorage) checkNoArchives(operation string) error {
+	years, err := fs.ArchivedYears()
+	if err != nil {
+		return err
+	}
+	if len(years) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(years))
+	for i, year := range years {
+		names[i] = strconv.Itoa(year)
+	}
+	return fmt.Errorf("unarchive %s before %s", strings.Join(names, ", "), operation)
+}
+
+// readStoredFile reads a file in the storage tree as stored, from a year archive if the
+// file isn't on disk
+func (fs *FileSystemStorage) readStoredFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err == nil || !os.IsNotExist(err) {
+		return content, err
+	}
+
+	year, ok := fs.archivedYearOf(path)
+	if !ok {
+		return nil, err
+	}
+	archive, loadErr := fs.loadArchive(year)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	if content, found := archive.files[path]; found {
+		return content, nil
+	}
+	return nil, err
+}
+
+// loadArchive returns the content of a year archive, reading it on first use
+func (fs *FileSystemStorage) loadArchive(year string) (*yearArchive, error) {
+	fs.archivesMu.Lock()
+	defer fs.archivesMu.Unlock()
+
+	if archive, ok := fs.archives[year]; ok {
+		return archive, nil
+	}
+	archive, err := fs.readArchive(year)
+	if err != nil {
+		return nil, err
+	}
+	if fs.archives == nil {
+		fs.archives = make(map[string]*yearArchive)
+	}
+	fs.archives[year] = archive
+	return archive, nil
+}
+
+// readArchive reads a year archive from disk
+func (fs *FileSystemStorage) readArchive(year string) (*yearArchive, error) {
+	file, err := os.Open(fs.archivePath(year))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(file.Name()), err)
+	}
+	tr := tar.NewReader(gz)
+
+	archive := &yearArchive{files: make(map[string][]byte)}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file.Name()), err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !safeBackupPath(header.Name) || !strings.HasPrefix(header.Name, year+"/") {
+			return nil, fmt.Errorf("%s: unexpected file %s", filepath.Base(file.Name()), header.Name)
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file.Name()), err)
+		}
+		path := filepath.Join(fs.basePath, filepath.FromSlash(header.Name))
+		archive.files[path] = content
+		archive.paths = append(archive.paths, path)
+	}
+
+	sort.Strings(archive.paths)
+	return archive, nil
+}
+
+// archivedEntryFiles returns the entry files in archived years from first to last
+func (fs *FileSystemStorage) archivedEntryFiles(first, last int) ([]string, error) {
+	years, err := fs.ArchivedYears()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, year := range years {
+		if year < first || year > last {
+			continue
+		}
+		archive, err := fs.loadArchive(fmt.Sprintf("%04d", year))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range archive.paths {
+			if fs.isEntryFile(path) {
+				files = append(files, path)
+			}
+		}
+	}
+	return files, nil
+}
+
+// archivedFilesIn returns the archived files directly in dir
+func (fs *FileSystemStorage) archivedFilesIn(dir string) []string {
+	year, ok := fs.archivedYearOf(filepath.Join(dir, "_"))
+	if !ok {
+		return nil
+	}
+	archive, err := fs.loadArchive(year)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, path := range archive.paths {
+		if filepath.Dir(path) == dir {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// isEntryFile reports whether a path in the storage tree is read as an entry: a markdown
+// file outside hidden directories that isn't a temp file or sync conflict copy
+func (fs *FileSystemStorage) isEntryFile(path string) bool {
+	rel, err := filepath.Rel(fs.basePath, path)
+	if err != nil {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return isMarkdownFile(path) && !isConflictCopy(filepath.Base(path))
+}
+
+// Archive packs the directory of a year into a compressed archive in the storage root.
+// The archive is written and checked before the directory is removed. Returns the
+// number of entries archived.
+func (fs *FileSystemStorage) Archive(year int) (int, error) {
+	if fs.layout == LayoutFlat {
+		return 0, fmt.Errorf("the flat layout has no year directories to archive (see 'jrnlg migrate-layout')")
+	}
+	if fs.migratingTo != "" {
+		return 0, fmt.Errorf("a layout migration is in progress; finish it with 'jrnlg migrate-layout %s' first", fs.migratingTo)
+	}
+
+	name := fmt.Sprintf("%04d", year)
+	dir := filepath.Join(fs.basePath, name)
+	if _, err := os.Stat(fs.archivePath(name)); err == nil {
+		return 0, fmt.Errorf("%s is already archived", name)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return 0, fmt.Errorf("no entries from %s", name)
+	}
+
+	// 1. Collect the year's files
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		switch {
+		case strings.HasPrefix(d.Name(), tempFilePrefix):
+			return fmt.Errorf("%s was left by an interrupted write; run 'jrnlg doctor' first", fs.relativePath(path))
+		case isConflictCopy(d.Name()):
+			return fmt.Errorf("%s is a sync conflict copy; resolve it with 'jrnlg conflicts' first", fs.relativePath(path))
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// 2. Write the archive
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	hashes := make(map[string]string, len(paths))
+	count := 0
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		if err := writeTarFile(tw, filepath.ToSlash(fs.relativePath(path)), content, info.ModTime()); err != nil {
+			return 0, err
+		}
+		hashes[path] = contentHash(content)
+		if fs.isEntryFile(path) {
+			count++
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+	if err := fs.writeAtomic(fs.archivePath(name), buf.Bytes()); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	// 3. Check the archive before removing anything
+	archive, err := fs.readArchive(name)
+	if err == nil && len(archive.files) != len(hashes) {
+		err = fmt.Errorf("archive has %d files, expected %d", len(archive.files), len(hashes))
+	}
+	for path, hash := range hashes {
+		if err == nil && contentHash(archive.files[path]) != hash {
+			err = fmt.Errorf("%s differs in the archive", fs.relativePath(path))
+		}
+	}
+	if err != nil {
+		return 0, errors.Join(fmt.Errorf("archive check failed: %w", err), fs.removeFile(fs.archivePath(name)))
+	}
+
+	// 4. Remove the year's directory
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			return count, fmt.Errorf("archived %s but failed to remove %s: %w", name, fs.relativePath(path), err)
+		}
+		fs.recordChange(path)
+	}
+	removeEmptyDirs(dir)
+
+	fs.resetArchives()
+	return count, nil
+}
+
+// Unarchive expands a year archive back into the storage tree and removes the archive.
+// Returns the number of entries restored.
+func (fs *FileSystemStorage) Unarchive(year int) (int, error) {
+	name := fmt.Sprintf("%04d", year)
+	if _, err := os.Stat(fs.archivePath(name)); err != nil {
+		return 0, fmt.Errorf("%s is not archived", name)
+	}
+
+	archive, err := fs.readArchive(name)
+	if err != nil {
+		return 0, err
+	}
+
+	// Check every file before writing any, so nothing on disk is overwritten
+	for _, path := range archive.paths {
+		existing, err := os.ReadFile(path)
+		if err == nil && !bytes.Equal(existing, archive.files[path]) {
+			return 0, fmt.Errorf("%s exists and differs from the archived copy", fs.relativePath(path))
+		}
+	}
+
+	count := 0
+	for _, path := range archive.paths {
+		if err := os.MkdirAll(filepath.Dir(path), DirPermissions); err != nil {
+			return count, err
+		}
+		if err := replaceFile(path, archive.files[path]); err != nil {
+			return count, fmt.Errorf("failed to restore %s: %w", fs.relativePath(path), err)
+		}
+		fs.recordChange(path)
+		if fs.isEntryFile(path) {
+			count++
+		}
+	}
+
+	if err := fs.removeFile(fs.archivePath(name)); err != nil {
+		return count, fmt.Errorf("failed to remove archive: %w", err)
+	}
+
+	fs.resetArchives()
+	return count, nil
+}
+
+// resetArchives drops cached archive contents and the index after archiving or unarchiving
+func (fs *FileSystemStorage) resetArchives() {
+	fs.archivesMu.Lock()
+	fs.archives = nil
+	fs.archivesMu.Unlock()
+	fs.InvalidateIndex()
+}
+
+// removeEmptyDirs removes dir and its subdirectories, deepest first, where they are empty
+func removeEmptyDirs(dir string) {
+	var dirs []string
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(dirs[i]) // Fails for directories that aren't empty
+	}
+}
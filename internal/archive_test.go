@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newArchiveTestStorage returns a journal with two entries in 2016 and one in 2017
+func newArchiveTestStorage(t *testing.T) (*FileSystemStorage, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+
+	for _, entry := range []*JournalEntry{
+		{Timestamp: time.Date(2016, 3, 1, 9, 0, 0, 0, time.UTC), Body: "Spring #garden with @alice."},
+		{Timestamp: time.Date(2016, 7, 4, 9, 0, 0, 0, time.UTC), Body: "Summer #garden."},
+		{Timestamp: time.Date(2017, 1, 1, 9, 0, 0, 0, time.UTC), Body: "New year."},
+	} {
+		if err := storage.SaveEntry(entry); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+	return storage, tmpDir
+}
+
+func TestArchive_EntriesReadTransparently(t *testing.T) {
+	storage, tmpDir := newArchiveTestStorage(t)
+
+	count, err := storage.Archive(2016)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Archive() = %d, want 2", count)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "2016")); !os.IsNotExist(err) {
+		t.Error("year directory still exists after archiving")
+	}
+	if years, _ := storage.ArchivedYears(); len(years) != 1 || years[0] != 2016 {
+		t.Errorf("ArchivedYears() = %v, want [2016]", years)
+	}
+
+	// Reopen, so nothing is served from memory
+	storage = NewFileSystemStorage(tmpDir, nil)
+
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("ListEntries() = %d entries, %v; want 3", len(entries), err)
+	}
+	start := time.Date(2016, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC)
+	entries, err = storage.ListEntries(EntryFilter{StartDate: &start, EndDate: &end})
+	if err != nil || len(entries) != 1 || entries[0].Body != "Summer #garden." {
+		t.Errorf("ListEntries(2016 H2) = %v, %v", entries, err)
+	}
+
+	if found, err := storage.SearchByTags([]string{"garden"}, EntryFilter{}); err != nil || len(found) != 2 {
+		t.Errorf("SearchByTags() = %d entries, %v; want 2", len(found), err)
+	}
+	if entry, err := storage.GetEntry(time.Date(2016, 3, 1, 9, 0, 0, 0, time.UTC)); err != nil || entry.Mentions[0] != "alice" {
+		t.Errorf("GetEntry() = %v, %v", entry, err)
+	}
+
+	// A new entry with an archived timestamp still gets a collision suffix
+	path := storage.availableEntryPath(&JournalEntry{Timestamp: time.Date(2016, 3, 1, 9, 0, 0, 0, time.UTC)})
+	if filepath.Base(path) != "2016-03-01-09-00-00-01.md" {
+		t.Errorf("availableEntryPath() = %s", path)
+	}
+}
+
+func TestArchive_ReadOnly(t *testing.T) {
+	storage, _ := newArchiveTestStorage(t)
+	if _, err := storage.Archive(2016); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	path, err := storage.GetEntryPath(time.Date(2016, 3, 1, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetEntryPath() error = %v", err)
+	}
+
+	if err := storage.UpdateEntry(path, &JournalEntry{Timestamp: time.Date(2016, 3, 1, 9, 0, 0, 0, time.UTC), Body: "Edited."}); !errors.Is(err, ErrArchived) {
+		t.Errorf("UpdateEntry() error = %v, want ErrArchived", err)
+	}
+	if err := storage.DeleteEntry(path); !errors.Is(err, ErrArchived) {
+		t.Errorf("DeleteEntry() error = %v, want ErrArchived", err)
+	}
+	if _, err := storage.GetEntryVersion(path); !errors.Is(err, ErrArchived) {
+		t.Errorf("GetEntryVersion() error = %v, want ErrArchived", err)
+	}
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2016, 5, 1, 9, 0, 0, 0, time.UTC), Body: "Late."}); !errors.Is(err, ErrArchived) {
+		t.Errorf("SaveEntry() error = %v, want ErrArchived", err)
+	}
+	if _, err := storage.MigrateLayout(LayoutFlat, false); err == nil || !strings.Contains(err.Error(), "unarchive 2016") {
+		t.Errorf("MigrateLayout() error = %v, want unarchive hint", err)
+	}
+
+	// Entries in other years can still be changed
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2017, 5, 1, 9, 0, 0, 0, time.UTC), Body: "Fine."}); err != nil {
+		t.Errorf("SaveEntry(2017) error = %v", err)
+	}
+}
+
+func TestUnarchive_RestoresYear(t *testing.T) {
+	storage, tmpDir := newArchiveTestStorage(t)
+	before, _ := storage.findAllFiles()
+
+	if _, err := storage.Archive(2016); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	count, err := storage.Unarchive(2016)
+	if err != nil {
+		t.Fatalf("Unarchive() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Unarchive() = %d, want 2", count)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "2016"+ArchiveExt)); !os.IsNotExist(err) {
+		t.Error("archive still exists after unarchiving")
+	}
+
+	after, _ := storage.findAllFiles()
+	if strings.Join(before, ",") != strings.Join(after, ",") {
+		t.Errorf("files after unarchive = %v, want %v", after, before)
+	}
+
+	path, _ := storage.GetEntryPath(time.Date(2016, 3, 1, 9, 0, 0, 0, time.UTC))
+	if err := storage.UpdateEntry(path, &JournalEntry{Timestamp: time.Date(2016, 3, 1, 9, 0, 0, 0, time.UTC), Body: "Edited."}); err != nil {
+		t.Errorf("UpdateEntry() after unarchive error = %v", err)
+	}
+
+	if _, err := storage.Unarchive(2016); err == nil {
+		t.Error("Unarchive() of a year that isn't archived: expected error")
+	}
+}
+
+func TestArchive_Refused(t *testing.T) {
+	storage, tmpDir := newArchiveTestStorage(t)
+
+	if _, err := storage.Archive(2010); err == nil {
+		t.Error("Archive() of a year without entries: expected error")
+	}
+
+	writeTestFile(t, tmpDir, "2016/03/2016-03-01-09-00-00.sync-conflict-20160302-101500-ABCDEF1.md", "## Tuesday 2016-03-01 9:00 AM UTC\n\nOther.\n")
+	if _, err := storage.Archive(2016); err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Errorf("Archive() with a conflict copy error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "2016"+ArchiveExt)); !os.IsNotExist(err) {
+		t.Error("archive written despite the error")
+	}
+
+	config := DefaultConfig()
+	config.Layout = LayoutFlat
+	flat := NewFileSystemStorage(t.TempDir(), config)
+	if _, err := flat.Archive(2016); err == nil {
+		t.Error("Archive() in the flat layout: expected error")
+	}
+}
+
+func TestArchive_EncryptedJournal(t *testing.T) {
+	storage, tmpDir := newEncryptedStorage(t, "correct horse")
+	if _, err := storage.Archive(2026); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	reopened := NewFileSystemStorage(tmpDir, nil)
+	reopened.SetPassphraseFunc(func() (string, error) { return "correct horse", nil })
+	entries, err := reopened.ListEntries(EntryFilter{})
+	if err != nil || len(entries) != 1 || !strings.Contains(entries[0].Body, "#reviews") {
+		t.Errorf("ListEntries() = %v, %v", entries, err)
+	}
+	if _, err := reopened.Decrypt(); err == nil {
+		t.Error("Decrypt() with an archived year: expected error")
+	}
+}
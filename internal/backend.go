@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Backend selects where entries are stored
+type Backend string
+
+const (
+	// BackendFiles stores one markdown file per entry in the storage directory (default)
+	BackendFiles Backend = "files"
+	// BackendJrnl stores all entries in a single jrnl plain-text journal file
+	BackendJrnl Backend = "jrnl"
+	// BackendWebDAV stores one markdown file per entry on a WebDAV server
+	BackendWebDAV Backend = "webdav"
+)
+
+// Backends lists all supported backends
+var Backends = []Backend{BackendFiles, BackendJrnl, BackendWebDAV}
+
+// ParseBackend validates a backend name
+func ParseBackend(s string) (Backend, error) {
+	names := make([]string, len(Backends))
+	for i, backend := range Backends {
+		if string(backend) == s {
+			return backend, nil
+		}
+		names[i] = string(backend)
+	}
+	return "", fmt.Errorf("invalid backend %q: must be one of %s", s, strings.Join(names, ", "))
+}
+
+// NewStorage opens the storage backend selected in config
+func NewStorage(config *Config) (Storage, error) {
+	switch config.Backend {
+	case BackendJrnl:
+		return OpenJrnlStorage(config.JrnlFile)
+	case BackendWebDAV:
+		if config.WebDAVURL == "" {
+			return nil, fmt.Errorf("the webdav backend needs JRNLG_WEBDAV_URL")
+		}
+		return OpenWebDAVStorage(config.WebDAVURL, config)
+	case BackendFiles, "":
+		return NewFileSystemStorage(config.StoragePath, config), nil
+	}
+	return nil, fmt.Errorf("invalid backend %q", config.Backend)
+}
+
+// Storage is a journal storage backend. Besides its timestamp, each entry has a path that
+// identifies it within the backend (a file path for filesystem storage); paths are only
+// meaningful to the backend that returned them.
+type Storage interface {
+	// SaveEntry stores a new entry, adding a collision suffix to its path if needed
+	SaveEntry(entry *JournalEntry) error
+	// GetEntry returns the first entry with the given timestamp
+	GetEntry(timestamp time.Time) (*JournalEntry, error)
+	// GetEntryPath returns the path of the first entry with the given timestamp
+	GetEntryPath(timestamp time.Time) (string, error)
+	// GetEntryByPath returns the entry stored at path
+	GetEntryByPath(path string) (*JournalEntry, error)
+	// ListEntries returns the entries matching filter, oldest first
+	ListEntries(filter EntryFilter) ([]*JournalEntry, error)
+
+	// UpdateEntry replaces the entry stored at path
+	UpdateEntry(path string, entry *JournalEntry) error
+	// GetEntryVersion identifies the stored state of an entry, for UpdateEntryIfUnchanged
+	GetEntryVersion(path string) (EntryVersion, error)
+	// UpdateEntryIfUnchanged replaces an entry only if it still matches expected,
+	// returning ErrEntryModified otherwise
+	UpdateEntryIfUnchanged(path string, entry *JournalEntry, expected EntryVersion) error
+	// DeleteEntry removes the entry stored at path
+	DeleteEntry(path string) error
+	// DeleteEntries removes the entries matching filter, returning their paths
+	DeleteEntries(filter EntryFilter) ([]string, error)
+
+	// SearchByTags returns the entries with all the given tags
+	SearchByTags(tags []string, filter EntryFilter) ([]*JournalEntry, error)
+	// SearchByMentions returns the entries with all the given mentions
+	SearchByMentions(mentions []string, filter EntryFilter) ([]*JournalEntry, error)
+	// SearchByKeyword returns the entries whose body contains keyword (case-insensitive)
+	SearchByKeyword(keyword string, filter EntryFilter) ([]*JournalEntry, error)
+	// GetIndex returns a search index covering at least the entries matching filter
+	GetIndex(filter EntryFilter) (*Index, error)
+
+	// GetTagStatistics returns tag usage counts across all entries
+	GetTagStatistics() (map[string]int, error)
+	// GetMentionStatistics returns mention usage counts across all entries
+	GetMentionStatistics() (map[string]int, error)
+	// GetEntriesWithTag returns the paths of all entries with the tag
+	GetEntriesWithTag(tag string) ([]string, error)
+	// GetEntriesWithMention returns the paths of all entries with the mention
+	GetEntriesWithMention(mention string) ([]string, error)
+	// ReplaceTagInEntries renames a tag in all entries, returning the paths updated
+	ReplaceTagInEntries(oldTag, newTag string, dryRun bool) ([]string, error)
+	// ReplaceMentionInEntries renames a mention in all entries, returning the paths updated
+	ReplaceMentionInEntries(oldMention, newMention string, dryRun bool) ([]string, error)
+}
+
+// ChangeTracker is implemented by backends that store entries as files, so the files an
+// operation changed can be committed to version control
+type ChangeTracker interface {
+	TakeChanges() []string
+}
+
+// DraftProvider is implemented by backends that keep editor drafts with the journal
+// (for example, encrypted like its entries)
+type DraftProvider interface {
+	Drafts() *DraftStore
+}
+
+var (
+	_ Storage       = (*FileSystemStorage)(nil)
+	_ ChangeTracker = (*FileSystemStorage)(nil)
+	_ DraftProvider = (*FileSystemStorage)(nil)
+	_ Storage       = (*MemoryStorage)(nil)
+	_ Storage       = (*JrnlStorage)(nil)
+	_ Storage       = (*WebDAVStorage)(nil)
+)
@@ -0,0 +1,497 @@
+package internal
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backup archive layout: the manifest comes first, followed by the storage tree
+const (
+	backupManifestName = "manifest.json"
+	backupFilesDir     = "journal/"
+	backupVersion      = 1
+)
+
+// BackupManifest describes the contents of a backup archive
+type BackupManifest struct {
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	Layout    Layout       `json:"layout"`
+	Encrypted bool         `json:"encrypted,omitempty"`
+	Files     []BackupFile `json:"files"`
+}
+
+// BackupFile is a file in a backup, with its checksum
+type BackupFile struct {
+	Path   string `json:"path"` // Relative to the storage directory, with forward slashes
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Entries returns the number of entry files in the backup
+func (m *BackupManifest) Entries() int {
+	count := 0
+	for _, file := range m.Files {
+		if isBackupEntry(file.Path) {
+			count++
+		}
+	}
+	return count
+}
+
+// isBackupEntry reports whether a backed up path is an entry file
+func isBackupEntry(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	_, _, ok := parseEntryFileName(path.Base(rel))
+	return ok
+}
+
+// Backup writes a gzip-compressed tar archive of the whole storage tree (entries, drafts
+// and state files) to w, starting with a manifest of checksums. The git repository and
+// temp files of interrupted writes are left out. Files are stored as they are on disk,
+// so backups of an encrypted journal stay encrypted.
+func (fs *FileSystemStorage) Backup(w io.Writer) (*BackupManifest, error) {
+	manifest := &BackupManifest{
+		Version:   backupVersion,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Layout:    fs.layout,
+		Encrypted: fs.IsEncrypted(),
+		Files:     []BackupFile{},
+	}
+
+	// 1. Checksum every file first, so the manifest can lead the archive
+	var paths []string
+	err := filepath.WalkDir(fs.basePath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" && path != fs.basePath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, BackupFile{
+			Path:   filepath.ToSlash(fs.relativePath(path)),
+			Size:   int64(len(content)),
+			SHA256: contentHash(content),
+		})
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan storage: %w", err)
+	}
+
+	// 2. Write the archive
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tw, backupManifestName, data, manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	for i, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if contentHash(content) != manifest.Files[i].SHA256 {
+			return nil, fmt.Errorf("%s changed during the backup; try again", path)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeTarFile(tw, backupFilesDir+manifest.Files[i].Path, content, info.ModTime()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeTarFile adds a regular file to a tar archive
+func writeTarFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    int64(FilePermissions),
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// BackupArchive is a backup read into memory and checked against its manifest
+type BackupArchive struct {
+	Manifest BackupManifest
+	files    map[string][]byte
+}
+
+// ReadBackup reads a backup archive and validates every file against the manifest:
+// all listed files must be present with matching checksums, and nothing else may be
+// included. Nothing is restored from an archive that fails validation.
+func ReadBackup(r io.Reader) (*BackupArchive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	archive := &BackupArchive{files: make(map[string][]byte)}
+	haveManifest := false
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("damaged backup archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("invalid backup archive: %s is not a regular file", header.Name)
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("damaged backup archive: %w", err)
+		}
+
+		if header.Name == backupManifestName {
+			if err := json.Unmarshal(content, &archive.Manifest); err != nil {
+				return nil, fmt.Errorf("invalid backup manifest: %w", err)
+			}
+			haveManifest = true
+			continue
+		}
+
+		rel, ok := strings.CutPrefix(header.Name, backupFilesDir)
+		if !ok || !safeBackupPath(rel) {
+			return nil, fmt.Errorf("invalid backup archive: unexpected file %s", header.Name)
+		}
+		archive.files[rel] = content
+	}
+
+	if !haveManifest {
+		return nil, fmt.Errorf("invalid backup archive: no manifest")
+	}
+	if archive.Manifest.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", archive.Manifest.Version)
+	}
+
+	// Every file must match the manifest, and the manifest must cover every file
+	listed := make(map[string]bool, len(archive.Manifest.Files))
+	for _, file := range archive.Manifest.Files {
+		content, ok := archive.files[file.Path]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("backup is incomplete: %s is missing", file.Path)
+		case int64(len(content)) != file.Size || contentHash(content) != file.SHA256:
+			return nil, fmt.Errorf("backup is damaged: checksum mismatch for %s", file.Path)
+		}
+		listed[file.Path] = true
+	}
+	for rel := range archive.files {
+		if !listed[rel] {
+			return nil, fmt.Errorf("backup is damaged: %s is not in the manifest", rel)
+		}
+	}
+
+	return archive, nil
+}
+
+// safeBackupPath rejects paths that would escape the storage directory
+func safeBackupPath(rel string) bool {
+	if rel == "" || path.IsAbs(rel) || strings.Contains(rel, "\\") {
+		return false
+	}
+	clean := path.Clean(rel)
+	return clean == rel && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// RestoreAction is what a restore does (or would do) with one file from a backup
+type RestoreAction string
+
+const (
+	RestoreNew       RestoreAction = "restore"   // Written; nothing was there
+	RestoreCollision RestoreAction = "collision" // Entry differs from an existing one with the same timestamp; written with a collision suffix
+	RestoreSame      RestoreAction = "unchanged" // Already in the journal
+	RestoreKept      RestoreAction = "kept"      // A different file exists at the path; the journal's version was kept
+)
+
+// RestoreItem describes a file restored (or to be restored) from a backup
+type RestoreItem struct {
+	Path   string // In the backup, relative to the storage directory
+	Target string // Where it was (or would be) written
+	Action RestoreAction
+}
+
+// RestoreResult summarizes a restore
+type RestoreResult struct {
+	Items []RestoreItem
+	Into  string // "empty" for a full restore, "existing" for a merge
+}
+
+// Count returns how many files got the given action
+func (r *RestoreResult) Count(action RestoreAction) int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Restore copies the files of a validated backup into the journal. An empty journal gets
+// an exact copy of the backed up tree. Into an existing journal, entries are merged:
+// entries already present are skipped, new ones are placed according to the journal's
+// layout, and an entry that differs from one with the same timestamp is kept as well,
+// with a collision suffix. Other files are only written where nothing exists yet.
+// Existing files are never overwritten. With dryRun, nothing is written.
+func (fs *FileSystemStorage) Restore(archive *BackupArchive, dryRun bool) (*RestoreResult, error) {
+	empty, err := fs.isEmptyTree()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(archive.files))
+	for rel := range archive.files {
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+
+	result := &RestoreResult{Into: "existing"}
+	if empty {
+		result.Into = "empty"
+		for _, rel := range files {
+			result.Items = append(result.Items, RestoreItem{Path: rel, Target: filepath.Join(fs.basePath, filepath.FromSlash(rel)), Action: RestoreNew})
+		}
+	} else {
+		if err := fs.checkBackupEncryption(archive); err != nil {
+			return nil, err
+		}
+		reserved := make(map[string]bool)
+		for _, rel := range files {
+			item, err := fs.planRestore(archive, rel, reserved)
+			if err != nil {
+				return nil, err
+			}
+			result.Items = append(result.Items, item)
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	for _, item := range result.Items {
+		if item.Action != RestoreNew && item.Action != RestoreCollision {
+			continue
+		}
+		if err := fs.ensureDirectories(item.Target); err != nil {
+			return result, err
+		}
+		if err := fs.writeAtomic(item.Target, archive.files[item.Path]); err != nil {
+			return result, fmt.Errorf("failed to restore %s: %w", item.Path, err)
+		}
+	}
+
+	// A full restore brings the journal's layout and key along
+	if state, err := readLayoutState(fs.basePath); err == nil && state != nil {
+		fs.layout = state.Layout.orDefault()
+		fs.migratingTo = state.MigratingTo
+	}
+	fs.reloadEncryptionState()
+	fs.InvalidateIndex()
+
+	return result, nil
+}
+
+// planRestore decides how to merge one backed up file into an existing journal
+func (fs *FileSystemStorage) planRestore(archive *BackupArchive, rel string, reserved map[string]bool) (RestoreItem, error) {
+	content := archive.files[rel]
+	item := RestoreItem{Path: rel, Target: filepath.Join(fs.basePath, filepath.FromSlash(rel))}
+
+	if isBackupEntry(rel) {
+		text, err := fs.decode(content)
+		if err != nil {
+			return item, fmt.Errorf("%s: %w", rel, err)
+		}
+		if entry, err := ParseEntry(string(text)); err == nil {
+			// Already in the journal (possibly at another path)?
+			for _, existing := range fs.findEntryFiles(entry.Timestamp) {
+				if existingText, err := fs.readEntryFile(existing); err == nil && bytes.Equal(existingText, text) {
+					item.Target, item.Action = existing, RestoreSame
+					return item, nil
+				}
+			}
+
+			target := fs.freeEntryPath(fs.layout, entry, "", reserved)
+			if target == "" {
+				return item, fmt.Errorf("%s: too many entries with the same timestamp", rel)
+			}
+			reserved[target] = true
+
+			_, collision, _ := parseEntryFileName(filepath.Base(target))
+			item.Target, item.Action = target, RestoreNew
+			if collision > 0 && len(fs.findEntryFiles(entry.Timestamp)) > 0 {
+				item.Action = RestoreCollision
+			}
+			return item, nil
+		}
+	}
+
+	// Anything else: only fill gaps
+	existing, err := os.ReadFile(item.Target)
+	switch {
+	case os.IsNotExist(err):
+		item.Action = RestoreNew
+	case err != nil:
+		return item, err
+	case bytes.Equal(existing, content):
+		item.Action = RestoreSame
+	default:
+		item.Action = RestoreKept
+	}
+	return item, nil
+}
+
+// checkBackupEncryption makes sure entries from the backup can be read in this journal
+func (fs *FileSystemStorage) checkBackupEncryption(archive *BackupArchive) error {
+	var backupState *encryptionState
+	if data, ok := archive.files[EncryptionStateFile]; ok {
+		backupState = &encryptionState{}
+		if err := json.Unmarshal(data, backupState); err != nil {
+			return fmt.Errorf("invalid %s in backup: %w", EncryptionStateFile, err)
+		}
+	}
+
+	if fs.encryptionErr != nil {
+		return fs.encryptionErr
+	}
+	switch {
+	case backupState == nil && fs.encryption == nil:
+		return nil
+	case backupState != nil && fs.encryption != nil && backupState.sameKey(fs.encryption):
+		return nil
+	}
+	return fmt.Errorf("the backup and this journal are not encrypted with the same key; restore into an empty directory instead")
+}
+
+// isEmptyTree reports whether the storage directory has no files at all (a git
+// repository doesn't count)
+func (fs *FileSystemStorage) isEmptyTree() (bool, error) {
+	empty := true
+	err := filepath.WalkDir(fs.basePath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == fs.basePath && os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" && path != fs.basePath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		empty = false
+		return filepath.SkipAll
+	})
+	return empty, err
+}
+
+// backupName matches timestamped backup names created for rotation: <prefix>-YYYYMMDD-HHMMSS.tar.gz
+var backupName = regexp.MustCompile(`^(.*)-(\d{8}-\d{6})\.tar\.gz$`)
+
+// backupTimestampFormat is the timestamp in rotated backup names (UTC)
+const backupTimestampFormat = "20060102-150405"
+
+// TimestampedBackupPath returns the path for a rotated backup: the timestamp is inserted
+// before the extension of target (or a jrnlg-<timestamp>.tar.gz is put in a target directory)
+func TimestampedBackupPath(target string, now time.Time) string {
+	stamp := now.UTC().Format(backupTimestampFormat)
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return filepath.Join(target, "jrnlg-"+stamp+".tar.gz")
+	}
+
+	base := strings.TrimSuffix(strings.TrimSuffix(target, ".tar.gz"), ".tgz")
+	return base + "-" + stamp + ".tar.gz"
+}
+
+// RotateBackups deletes all but the newest keep backups in the same series as path
+// (same directory and name prefix). Returns the deleted backups.
+func RotateBackups(path string, keep int) ([]string, error) {
+	match := backupName.FindStringSubmatch(filepath.Base(path))
+	if match == nil || keep < 1 {
+		return nil, nil
+	}
+	prefix := match[1]
+	dir := filepath.Dir(path)
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var series []string
+	for _, dirEntry := range dirEntries {
+		if m := backupName.FindStringSubmatch(dirEntry.Name()); m != nil && m[1] == prefix && !dirEntry.IsDir() {
+			series = append(series, dirEntry.Name())
+		}
+	}
+	sort.Strings(series) // Timestamps sort chronologically
+
+	var deleted []string
+	for len(series) > keep {
+		old := filepath.Join(dir, series[0])
+		if err := os.Remove(old); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, old)
+		series = series[1:]
+	}
+	return deleted, nil
+}
@@ -0,0 +1,236 @@
+package internal
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// backupOf saves the given entries in a new journal and returns its backup
+func backupOf(t *testing.T, layout Layout, bodies ...string) []byte {
+	t.Helper()
+	config := DefaultConfig()
+	config.Layout = layout
+	storage := NewFileSystemStorage(t.TempDir(), config)
+
+	for i, body := range bodies {
+		if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, 9, 9, i, 0, 0, time.UTC), Body: body}); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+	draft := &Draft{}
+	if err := storage.Drafts().Create("unfinished", draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	manifest, err := storage.Backup(&buf)
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if manifest.Entries() != len(bodies) {
+		t.Errorf("manifest has %d entries, want %d", manifest.Entries(), len(bodies))
+	}
+	return buf.Bytes()
+}
+
+func readBackup(t *testing.T, data []byte) *BackupArchive {
+	t.Helper()
+	archive, err := ReadBackup(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadBackup() error = %v", err)
+	}
+	return archive
+}
+
+func TestRestore_IntoEmptyJournal(t *testing.T) {
+	archive := readBackup(t, backupOf(t, LayoutFlat, "One.", "Two."))
+
+	tmpDir := filepath.Join(t.TempDir(), "restored")
+	storage := NewFileSystemStorage(tmpDir, nil)
+	result, err := storage.Restore(archive, false)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if result.Into != "empty" || result.Count(RestoreNew) != len(archive.Manifest.Files) {
+		t.Errorf("Restore() = %+v", result)
+	}
+
+	// The layout comes along with the files
+	if storage.Layout() != LayoutFlat {
+		t.Errorf("Layout() = %s, want flat", storage.Layout())
+	}
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("ListEntries() returned %d entries, want 2", len(entries))
+	}
+	drafts, err := storage.Drafts().List()
+	if err != nil || len(drafts) != 1 {
+		t.Errorf("Drafts().List() = %d drafts, %v; want 1", len(drafts), err)
+	}
+}
+
+func TestRestore_MergesIntoExistingJournal(t *testing.T) {
+	archive := readBackup(t, backupOf(t, LayoutYearMonth, "One.", "Two.", "Three."))
+
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+	// Same as the backup, edited since, and not in the backup
+	for i, body := range []string{"One.", "Two, edited."} {
+		if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, 9, 9, i, 0, 0, time.UTC), Body: body}); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+
+	plan, err := storage.Restore(archive, true)
+	if err != nil {
+		t.Fatalf("Restore(dry run) error = %v", err)
+	}
+	if files, _ := storage.findAllFiles(); len(files) != 2 {
+		t.Fatalf("dry run wrote files: %v", files)
+	}
+
+	result, err := storage.Restore(archive, false)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if len(plan.Items) != len(result.Items) {
+		t.Errorf("dry run planned %d items, restore did %d", len(plan.Items), len(result.Items))
+	}
+	if result.Into != "existing" {
+		t.Errorf("Into = %q, want existing", result.Into)
+	}
+
+	got := make(map[RestoreAction]int)
+	for _, item := range result.Items {
+		if isBackupEntry(item.Path) {
+			got[item.Action]++
+		} else if item.Path == LayoutStateFile && item.Action == RestoreNew {
+			// Other files only fill gaps, such as the backup's drafts
+			t.Errorf("layout state restored over the journal's own")
+		}
+	}
+	want := map[RestoreAction]int{RestoreSame: 1, RestoreCollision: 1, RestoreNew: 1}
+	for action, count := range want {
+		if got[action] != count {
+			t.Errorf("entries %s = %d, want %d", action, got[action], count)
+		}
+	}
+
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	var bodies []string
+	for _, entry := range entries {
+		bodies = append(bodies, entry.Body)
+	}
+	if got := strings.Join(bodies, "|"); got != "One.|Two, edited.|Two.|Three." && got != "One.|Two.|Two, edited.|Three." {
+		t.Errorf("entries after restore = %s", got)
+	}
+}
+
+func TestReadBackup_RejectsDamagedArchives(t *testing.T) {
+	data := backupOf(t, LayoutYearMonth, "One.")
+
+	// Rewrite the archive with one entry changed and one unlisted file added
+	rewrite := func(change func(name string, content []byte) []byte, extra string) []byte {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("gzip.NewReader() error = %v", err)
+		}
+		tr := tar.NewReader(gz)
+		var out bytes.Buffer
+		gw := gzip.NewWriter(&out)
+		tw := tar.NewWriter(gw)
+		for {
+			header, err := tr.Next()
+			if err != nil {
+				break
+			}
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			content = change(header.Name, content)
+			if err := writeTarFile(tw, header.Name, content, header.ModTime); err != nil {
+				t.Fatalf("writeTarFile() error = %v", err)
+			}
+		}
+		if extra != "" {
+			if err := writeTarFile(tw, extra, []byte("x"), time.Now()); err != nil {
+				t.Fatalf("writeTarFile() error = %v", err)
+			}
+		}
+		_ = tw.Close()
+		_ = gw.Close()
+		return out.Bytes()
+	}
+
+	tampered := rewrite(func(name string, content []byte) []byte {
+		if strings.HasSuffix(name, ".md") && strings.Contains(name, "2026") {
+			return bytes.Replace(content, []byte("One."), []byte("Uno."), 1)
+		}
+		return content
+	}, "")
+	if _, err := ReadBackup(bytes.NewReader(tampered)); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("ReadBackup(tampered) error = %v, want checksum mismatch", err)
+	}
+
+	same := func(name string, content []byte) []byte { return content }
+	if _, err := ReadBackup(bytes.NewReader(rewrite(same, backupFilesDir+"extra.md"))); err == nil {
+		t.Error("ReadBackup() with an unlisted file: expected error")
+	}
+	if _, err := ReadBackup(bytes.NewReader(rewrite(same, backupFilesDir+"../escape.md"))); err == nil {
+		t.Error("ReadBackup() with a path outside the journal: expected error")
+	}
+	if _, err := ReadBackup(strings.NewReader("not an archive")); err == nil {
+		t.Error("ReadBackup() of garbage: expected error")
+	}
+}
+
+func TestRotateBackups(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC)
+
+	var paths []string
+	for i := 0; i < 4; i++ {
+		path := TimestampedBackupPath(filepath.Join(dir, "journal.tar.gz"), now.Add(time.Duration(i)*time.Hour))
+		if err := os.WriteFile(path, []byte("backup"), FilePermissions); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		paths = append(paths, path)
+	}
+	if filepath.Base(paths[0]) != "journal-20260209-090000.tar.gz" {
+		t.Errorf("TimestampedBackupPath() = %s", paths[0])
+	}
+	other := filepath.Join(dir, "other-20200101-000000.tar.gz")
+	if err := os.WriteFile(other, []byte("backup"), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	deleted, err := RotateBackups(paths[3], 2)
+	if err != nil {
+		t.Fatalf("RotateBackups() error = %v", err)
+	}
+	if len(deleted) != 2 || deleted[0] != paths[0] || deleted[1] != paths[1] {
+		t.Errorf("RotateBackups() deleted %v, want the two oldest", deleted)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("backup of another series was removed")
+	}
+
+	// A directory target gets a default name
+	if got := filepath.Base(TimestampedBackupPath(dir, now)); got != "jrnlg-20260209-090000.tar.gz" {
+		t.Errorf("TimestampedBackupPath(dir) = %s", got)
+	}
+}
@@ -0,0 +1,473 @@
+package internal
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ErrNoChain is returned when verifying a journal whose entries are not signed
+var ErrNoChain = errors.New("journal entries are not signed")
+
+// ChainOp is the kind of change a chain link records
+type ChainOp string
+
+const (
+	ChainAdd    ChainOp = "add"    // Entry created
+	ChainAmend  ChainOp = "amend"  // Entry edited; Replaces holds the previous content hash
+	ChainDelete ChainOp = "delete" // Entry deleted; Hash holds the deleted content hash
+)
+
+// ChainLink is one signed record in the hash chain manifest. Each link includes the hash
+// of the link before it, so links can't be changed, removed or reordered unnoticed.
+type ChainLink struct {
+	Seq      int       `json:"seq"`
+	Op       ChainOp   `json:"op"`
+	Entry    string    `json:"entry"` // Entry timestamp (UTC, as in filenames)
+	Path     string    `json:"path"`  // Entry file, relative to the storage directory
+	Hash     string    `json:"hash"`  // SHA-256 of the entry text (decrypted)
+	Replaces string    `json:"replaces,omitempty"`
+	Prev     string    `json:"prev"` // Hash of the previous link ("" for the first)
+	Time     time.Time `json:"time"`
+	Sig      string    `json:"sig,omitempty"` // ed25519 signature of the link hash
+}
+
+// hash returns the hash of the link without its signature
+func (l ChainLink) hash() string {
+	l.Sig = ""
+	data, _ := json.Marshal(l)
+	return contentHash(data)
+}
+
+// chainHeader is the first line of the manifest
+type chainHeader struct {
+	PublicKey string `json:"public_key"`
+}
+
+// ChainReport is the result of verifying the hash chain against the entry files
+type ChainReport struct {
+	Links       int
+	Verified    int           // Entry files matching their signed content
+	Modified    []ChainChange // Entry files whose content differs from what was signed
+	Inserted    []string      // Entry files that were never signed
+	Removed     []ChainLink   // Signed entries that no longer exist
+	Broken      string        // Why the chain itself is invalid ("" if intact)
+	Fingerprint string        // Fingerprint of the signing key
+}
+
+// ChainChange is an entry file whose content no longer matches its signed version
+type ChainChange struct {
+	Path   string
+	Signed ChainLink // The link that signed the expected content
+}
+
+// OK reports whether the chain is intact and matches every entry file
+func (r *ChainReport) OK() bool {
+	return r.Broken == "" && len(r.Modified) == 0 && len(r.Inserted) == 0 && len(r.Removed) == 0
+}
+
+// SignResult summarizes a run of SignChain
+type SignResult struct {
+	KeyCreated bool // A new signing key was generated
+	Started    bool // The chain was started by this run
+	Added      int
+	Amended    int
+	Deleted    int
+}
+
+// chainPath returns the path of the hash chain manifest
+func (fs *FileSystemStorage) chainPath() string {
+	return filepath.Join(fs.basePath, ChainFile)
+}
+
+// IsSigned reports whether the journal keeps a signed hash chain of its entries
+func (fs *FileSystemStorage) IsSigned() bool {
+	_, err := os.Stat(fs.chainPath())
+	return err == nil
+}
+
+// readChain loads the manifest header and links
+func (fs *FileSystemStorage) readChain() (ed25519.PublicKey, []ChainLink, error) {
+	file, err := os.Open(fs.chainPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrNoChain
+		}
+		return nil, nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		return nil, nil, fmt.Errorf("invalid %s: missing header", ChainFile)
+	}
+	var header chainHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s header: %w", ChainFile, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(header.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, nil, fmt.Errorf("invalid %s header: bad public key", ChainFile)
+	}
+
+	var links []ChainLink
+	for line := 2; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var link ChainLink
+		if err := json.Unmarshal(scanner.Bytes(), &link); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s line %d: %w", ChainFile, line, err)
+		}
+		links = append(links, link)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return key, links, nil
+}
+
+// chainWriter appends signed links to the manifest
+type chainWriter struct {
+	fs       *FileSystemStorage
+	key      ed25519.PrivateKey
+	lastSeq  int
+	lastHash string
+}
+
+// openChain prepares to record changes in the hash chain
+// Returns nil if the journal isn't signed, and an error if the signing key can't be used
+func (fs *FileSystemStorage) openChain() (*chainWriter, error) {
+	publicKey, links, err := fs.readChain()
+	if errors.Is(err, ErrNoChain) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := loadSigningKey(fs.config.SigningKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("entries are signed but the signing key can't be loaded: %w", err)
+	}
+	if !publicKey.Equal(key.Public()) {
+		return nil, fmt.Errorf("signing key %s does not match the key that signed this journal", fs.config.SigningKeyPath)
+	}
+
+	writer := &chainWriter{fs: fs, key: key}
+	if len(links) > 0 {
+		last := links[len(links)-1]
+		writer.lastSeq, writer.lastHash = last.Seq, last.hash()
+	}
+	return writer, nil
+}
+
+// append signs and records a link; a nil writer (unsigned journal) records nothing
+func (w *chainWriter) append(op ChainOp, path, entry, hash, replaces string) error {
+	if w == nil {
+		return nil
+	}
+
+	link := ChainLink{
+		Seq:      w.lastSeq + 1,
+		Op:       op,
+		Entry:    entry,
+		Path:     w.fs.relativePath(path),
+		Hash:     hash,
+		Replaces: replaces,
+		Prev:     w.lastHash,
+		Time:     time.Now().UTC().Truncate(time.Second),
+	}
+	linkHash := link.hash()
+	link.Sig = base64.StdEncoding.EncodeToString(ed25519.Sign(w.key, []byte(linkHash)))
+
+	data, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	if err := appendLine(w.fs.chainPath(), data); err != nil {
+		return fmt.Errorf("failed to sign entry: %w", err)
+	}
+	w.fs.recordChange(w.fs.chainPath())
+
+	w.lastSeq, w.lastHash = link.Seq, linkHash
+	return nil
+}
+
+// appendLine appends a line to a file, creating it if needed
+func appendLine(path string, line []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, FilePermissions)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	return file.Close()
+}
+
+// signWrite records an added or amended entry after its file was written
+func (fs *FileSystemStorage) signWrite(chain *chainWriter, path string, entry *JournalEntry, text []byte, previous string) error {
+	op := ChainAdd
+	if previous != "" {
+		op = ChainAmend
+	}
+	if err := chain.append(op, path, entryChainID(entry.Timestamp), contentHash(text), previous); err != nil {
+		return fmt.Errorf("entry saved but not signed (run 'jrnlg sign'): %w", err)
+	}
+	return nil
+}
+
+// entryChainID identifies an entry in chain links: its UTC timestamp as used in filenames
+func entryChainID(timestamp time.Time) string {
+	return timestamp.UTC().Format(FileNameTimestampLayout)
+}
+
+// textHash returns the hash of an entry file's text, or "" if it can't be read
+func (fs *FileSystemStorage) textHash(path string) string {
+	text, err := fs.readEntryFile(path)
+	if err != nil {
+		return ""
+	}
+	return contentHash(text)
+}
+
+// VerifyChain checks the signatures and links of the hash chain, then compares the
+// signed state with the entry files. Entries moved to another path (e.g. by a layout
+// migration) still verify, since entries are matched by content.
+func (fs *FileSystemStorage) VerifyChain() (*ChainReport, error) {
+	if err := fs.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	publicKey, links, err := fs.readChain()
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := sha256.Sum256(publicKey)
+	report := &ChainReport{
+		Links:       len(links),
+		Fingerprint: "SHA256:" + hex.EncodeToString(fingerprint[:8]),
+	}
+
+	// 1. Check the chain itself; stop trusting it at the first bad link
+	expected := make(map[string]int)         // Signed content hashes still expected
+	signedBy := make(map[string][]ChainLink) // Links that signed each content hash
+	prev := ""
+	for i, link := range links {
+		sig, _ := base64.StdEncoding.DecodeString(link.Sig)
+		switch {
+		case link.Seq != i+1:
+			report.Broken = fmt.Sprintf("link %d: out of sequence (found %d); links were removed or reordered", i+1, link.Seq)
+		case link.Prev != prev:
+			report.Broken = fmt.Sprintf("link %d: does not follow link %d; links were removed or altered", link.Seq, i)
+		case !ed25519.Verify(publicKey, []byte(link.hash()), sig):
+			report.Broken = fmt.Sprintf("link %d: invalid signature; the link was altered", link.Seq)
+		}
+		if report.Broken != "" {
+			report.Links = i
+			break
+		}
+		prev = link.hash()
+
+		switch link.Op {
+		case ChainAdd:
+			expected[link.Hash]++
+			signedBy[link.Hash] = append(signedBy[link.Hash], link)
+		case ChainAmend:
+			if expected[link.Replaces] > 0 {
+				expected[link.Replaces]--
+			}
+			expected[link.Hash]++
+			signedBy[link.Hash] = append(signedBy[link.Hash], link)
+		case ChainDelete:
+			if expected[link.Hash] > 0 {
+				expected[link.Hash]--
+			}
+		}
+	}
+
+	// 2. Match entry files to signed content
+	files, err := fs.findAllFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find files: %w", err)
+	}
+	sort.Strings(files)
+
+	var unmatched []string
+	for _, path := range files {
+		hash := fs.textHash(path)
+		if hash != "" && expected[hash] > 0 {
+			expected[hash]--
+			report.Verified++
+			continue
+		}
+		unmatched = append(unmatched, path)
+	}
+
+	// 3. Signed content not found: pair with unmatched files of the same entry
+	var missing []ChainLink
+	for hash, count := range expected {
+		for i := 0; i < count; i++ {
+			missing = append(missing, latestLink(signedBy[hash]))
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Seq < missing[j].Seq })
+
+	for _, path := range unmatched {
+		ts, _, _ := parseEntryFileName(filepath.Base(path))
+		rel := fs.relativePath(path)
+
+		match := -1
+		for i, link := range missing {
+			if link.Path == rel || (match == -1 && ts != "" && link.Entry == ts) {
+				match = i
+			}
+		}
+		if match == -1 {
+			report.Inserted = append(report.Inserted, path)
+			continue
+		}
+		report.Modified = append(report.Modified, ChainChange{Path: path, Signed: missing[match]})
+		missing = append(missing[:match], missing[match+1:]...)
+	}
+	report.Removed = missing
+
+	return report, nil
+}
+
+// latestLink returns the most recent of the links
+func latestLink(links []ChainLink) ChainLink {
+	return links[len(links)-1]
+}
+
+// SignChain signs the current state of the journal: it starts the hash chain (creating a
+// signing key if there is none) and records every entry added, changed or deleted since
+// it was last signed, e.g. by a sync from another machine.
+func (fs *FileSystemStorage) SignChain() (*SignResult, error) {
+	result := &SignResult{}
+
+	key, err := loadSigningKey(fs.config.SigningKeyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		key, err = createSigningKey(fs.config.SigningKeyPath)
+		result.KeyCreated = true
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !fs.IsSigned() {
+		header, err := json.Marshal(chainHeader{PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))})
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(fs.basePath, DirPermissions); err != nil {
+			return nil, err
+		}
+		if err := appendLine(fs.chainPath(), header); err != nil {
+			return nil, fmt.Errorf("failed to start signing: %w", err)
+		}
+		fs.recordChange(fs.chainPath())
+		result.Started = true
+	}
+
+	report, err := fs.VerifyChain()
+	if err != nil {
+		return nil, err
+	}
+	if report.Broken != "" {
+		return nil, fmt.Errorf("the hash chain is broken (%s); it can't be extended", report.Broken)
+	}
+
+	chain, err := fs.openChain()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range report.Modified {
+		if err := chain.append(ChainAmend, change.Path, change.Signed.Entry, fs.textHash(change.Path), change.Signed.Hash); err != nil {
+			return result, err
+		}
+		result.Amended++
+	}
+	for _, path := range report.Inserted {
+		entry, err := fs.parseFile(path)
+		if err != nil {
+			continue // Not an entry (see 'jrnlg doctor')
+		}
+		if err := chain.append(ChainAdd, path, entryChainID(entry.Timestamp), fs.textHash(path), ""); err != nil {
+			return result, err
+		}
+		result.Added++
+	}
+	for _, link := range report.Removed {
+		if err := chain.append(ChainDelete, filepath.Join(fs.basePath, link.Path), link.Entry, link.Hash, ""); err != nil {
+			return result, err
+		}
+		result.Deleted++
+	}
+
+	return result, nil
+}
+
+// loadSigningKey reads an ed25519 private key from a PEM file
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s: not a PEM private key", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an ed25519 key", path)
+	}
+	return key, nil
+}
+
+// createSigningKey generates an ed25519 key and saves it, readable only by the user
+func createSigningKey(path string) (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to save signing key: %w", err)
+	}
+	return key, nil
+}
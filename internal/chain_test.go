@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSignedStorage creates a storage with two entries and signs them
+func newSignedStorage(t *testing.T) (*FileSystemStorage, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	config := DefaultConfig()
+	config.SigningKeyPath = filepath.Join(t.TempDir(), "keys", "signing.key")
+	storage := NewFileSystemStorage(tmpDir, config)
+
+	for i, body := range []string{"First #log entry.", "Second #log entry."} {
+		entry := &JournalEntry{Timestamp: time.Date(2026, 2, 9+i, 9, 0, 0, 0, time.UTC), Body: body}
+		if err := storage.SaveEntry(entry); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+
+	result, err := storage.SignChain()
+	if err != nil {
+		t.Fatalf("SignChain() error = %v", err)
+	}
+	if !result.KeyCreated || !result.Started || result.Added != 2 {
+		t.Fatalf("SignChain() = %+v, want new key, new chain and 2 added", result)
+	}
+	return storage, tmpDir
+}
+
+func verify(t *testing.T, storage *FileSystemStorage) *ChainReport {
+	t.Helper()
+	report, err := storage.VerifyChain()
+	if err != nil {
+		t.Fatalf("VerifyChain() error = %v", err)
+	}
+	return report
+}
+
+func TestSignChain_RecordsChanges(t *testing.T) {
+	storage, _ := newSignedStorage(t)
+
+	// Adds, edits and deletes through the storage API extend the chain
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, 11, 9, 0, 0, 0, time.UTC), Body: "Third."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	path, err := storage.GetEntryPath(time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetEntryPath() error = %v", err)
+	}
+	if err := storage.UpdateEntry(path, &JournalEntry{Timestamp: time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC), Body: "First #log entry, amended."}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	second, _ := storage.GetEntryPath(time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC))
+	if err := storage.DeleteEntry(second); err != nil {
+		t.Fatalf("DeleteEntry() error = %v", err)
+	}
+
+	report := verify(t, storage)
+	if !report.OK() {
+		t.Fatalf("VerifyChain() = %+v, want OK", report)
+	}
+	if report.Links != 5 || report.Verified != 2 {
+		t.Errorf("Links = %d, Verified = %d; want 5 and 2", report.Links, report.Verified)
+	}
+
+	// Moving entries to another layout doesn't break verification
+	if _, err := storage.MigrateLayout(LayoutFlat, false); err != nil {
+		t.Fatalf("MigrateLayout() error = %v", err)
+	}
+	if report := verify(t, storage); !report.OK() {
+		t.Errorf("VerifyChain() after migration = %+v, want OK", report)
+	}
+}
+
+func TestVerifyChain_DetectsTampering(t *testing.T) {
+	storage, tmpDir := newSignedStorage(t)
+
+	first := filepath.Join(tmpDir, "2026", "02", "2026-02-09-09-00-00.md")
+	second := filepath.Join(tmpDir, "2026", "02", "2026-02-10-09-00-00.md")
+	writeTestFile(t, tmpDir, filepath.Join("2026", "02", "2026-02-09-09-00-00.md"),
+		"## Monday 2026-02-09 9:00 AM UTC\n\nFirst #log entry, quietly changed.\n")
+	if err := os.Remove(second); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	inserted := writeTestFile(t, tmpDir, filepath.Join("2026", "01", "2026-01-05-09-00-00.md"),
+		"## Monday 2026-01-05 9:00 AM UTC\n\nBackdated.\n")
+
+	report := verify(t, storage)
+	if report.OK() {
+		t.Fatal("VerifyChain() = OK after tampering")
+	}
+	if len(report.Modified) != 1 || report.Modified[0].Path != first {
+		t.Errorf("Modified = %+v, want %s", report.Modified, first)
+	}
+	if len(report.Inserted) != 1 || report.Inserted[0] != inserted {
+		t.Errorf("Inserted = %v, want %s", report.Inserted, inserted)
+	}
+	if len(report.Removed) != 1 || report.Removed[0].Entry != "2026-02-10-09-00-00" {
+		t.Errorf("Removed = %+v, want the second entry", report.Removed)
+	}
+
+	// Signing accepts the current state
+	result, err := storage.SignChain()
+	if err != nil {
+		t.Fatalf("SignChain() error = %v", err)
+	}
+	if result.KeyCreated || result.Started || result.Added != 1 || result.Amended != 1 || result.Deleted != 1 {
+		t.Errorf("SignChain() = %+v", result)
+	}
+	if report := verify(t, storage); !report.OK() {
+		t.Errorf("VerifyChain() after signing = %+v, want OK", report)
+	}
+}
+
+func TestVerifyChain_DetectsAlteredLinks(t *testing.T) {
+	storage, tmpDir := newSignedStorage(t)
+
+	manifest := filepath.Join(tmpDir, ChainFile)
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	// Rewriting a link's content hash invalidates its signature
+	lines := strings.Split(string(data), "\n")
+	lines[1] = strings.Replace(lines[1], `"hash":"`, `"hash":"0`, 1)
+	if err := os.WriteFile(manifest, []byte(strings.Join(lines, "\n")), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if report := verify(t, storage); !strings.Contains(report.Broken, "link 1") {
+		t.Errorf("Broken = %q, want link 1", report.Broken)
+	}
+
+	// Removing a link breaks the chain
+	lines = strings.Split(string(data), "\n")
+	lines = append(lines[:1], lines[2:]...)
+	if err := os.WriteFile(manifest, []byte(strings.Join(lines, "\n")), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if report := verify(t, storage); report.Broken == "" {
+		t.Error("Broken = \"\" after removing a link")
+	}
+	if _, err := storage.SignChain(); err == nil {
+		t.Error("SignChain() on a broken chain: expected error")
+	}
+}
+
+func TestSignChain_RequiresKeyForChanges(t *testing.T) {
+	storage, tmpDir := newSignedStorage(t)
+
+	config := DefaultConfig()
+	config.SigningKeyPath = filepath.Join(t.TempDir(), "missing.key")
+	withoutKey := NewFileSystemStorage(tmpDir, config)
+
+	entry := &JournalEntry{Timestamp: time.Date(2026, 2, 12, 9, 0, 0, 0, time.UTC), Body: "Unsigned."}
+	if err := withoutKey.SaveEntry(entry); err == nil {
+		t.Fatal("SaveEntry() without signing key: expected error")
+	}
+	if report := verify(t, storage); !report.OK() {
+		t.Errorf("VerifyChain() = %+v, want OK (nothing written)", report)
+	}
+
+	if _, err := NewFileSystemStorage(t.TempDir(), nil).VerifyChain(); !errors.Is(err, ErrNoChain) {
+		t.Errorf("VerifyChain() of unsigned journal error = %v, want ErrNoChain", err)
+	}
+}
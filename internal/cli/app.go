@@ -1,19 +1,81 @@
 package cli
 
 import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+
 	"github.com/jashort/jrnlg/internal"
+	"github.com/jashort/jrnlg/internal/cli/color"
 )
 
 // App coordinates CLI operations
 type App struct {
-	storage *internal.FileSystemStorage
+	storage internal.Storage
 	config  *internal.Config
+	color   color.Mode
 }
 
 // NewApp creates a new CLI application
-func NewApp(storage *internal.FileSystemStorage, config *internal.Config) *App {
-	return &App{
+func NewApp(storage internal.Storage, config *internal.Config) *App {
+	app := &App{
 		storage: storage,
 		config:  config,
 	}
+	if fs, ok := storage.(*internal.FileSystemStorage); ok {
+		fs.SetPassphraseFunc(app.readPassphrase)
+	}
+	return app
+}
+
+// SetColor sets the color mode of the output: the --color flag, else the configured one
+func (a *App) SetColor(flag string) error {
+	mode, err := color.ParseMode(orDefault(flag, a.config.Color))
+	if err != nil {
+		return err
+	}
+	a.color = mode
+	color.SetDefault(mode)
+	return nil
+}
+
+// colorizer returns a Colorizer in the color mode of the output
+func (a *App) colorizer() *color.Colorizer {
+	return color.New(a.color)
+}
+
+// CheckStorage rejects commands that work on the storage directory itself (encryption,
+// signing, syncing, backups and the like, tagged storage:"directory") before they run,
+// when the journal is kept by another backend
+func CheckStorage(kctx *kong.Context, config *internal.Config, storage internal.Storage) error {
+	if _, ok := storage.(*internal.FileSystemStorage); ok {
+		return nil
+	}
+	for node := kctx.Selected(); node != nil; node = node.Parent {
+		if node.Tag != nil && node.Tag.Get("storage") == "directory" {
+			return errDirectoryCommand(node.Name, config.Backend)
+		}
+	}
+	return nil
+}
+
+// errDirectoryCommand is the error of a command that needs a journal stored in a directory
+func errDirectoryCommand(command string, backend internal.Backend) error {
+	return fmt.Errorf("%s is not supported by the %s backend: it needs a journal stored in a directory", command, backend)
+}
+
+// fileStorage returns the journal's filesystem storage, for the commands CheckStorage
+// lets run only on journals stored in a directory
+func (a *App) fileStorage() (*internal.FileSystemStorage, error) {
+	fs, ok := a.storage.(*internal.FileSystemStorage)
+	if !ok {
+		return nil, errDirectoryCommand("this command", a.config.Backend)
+	}
+	return fs, nil
+}
+
+// isEncrypted reports whether the journal is encrypted at rest
+func (a *App) isEncrypted() bool {
+	fs, ok := a.storage.(*internal.FileSystemStorage)
+	return ok && fs.IsEncrypted()
 }
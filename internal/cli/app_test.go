@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/jashort/jrnlg/internal"
+	"github.com/jashort/jrnlg/internal/cli/color"
+)
+
+func TestCheckStorage(t *testing.T) {
+	config := &internal.Config{Backend: internal.BackendJrnl}
+	memory := internal.NewMemoryStorage()
+	files := internal.NewFileSystemStorage(t.TempDir(), internal.DefaultConfig())
+
+	tests := []struct {
+		command string
+		storage internal.Storage
+		wantErr bool
+	}{
+		{"doctor", memory, true},
+		{"sync-dir /tmp/other", memory, true},
+		{"backup /tmp/backups", memory, true},
+		{"doctor", files, false},
+		{"search", memory, false},
+		{"drafts list", memory, false},
+	}
+	for _, tt := range tests {
+		var cli CLI
+		parser, err := kong.New(&cli, kong.Vars{"version": "test"})
+		if err != nil {
+			t.Fatalf("kong.New() error = %v", err)
+		}
+		kctx, err := parser.Parse(strings.Fields(tt.command))
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.command, err)
+		}
+		err = CheckStorage(kctx, config, tt.storage)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckStorage(%q) error = %v, want error = %v", tt.command, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "not supported by the jrnl backend") {
+			t.Errorf("CheckStorage(%q) error = %v", tt.command, err)
+		}
+	}
+}
+
+func TestSetColor(t *testing.T) {
+	tests := []struct {
+		flag, configured string
+		want             color.Mode
+		wantErr          bool
+	}{
+		{"", "auto", color.Auto, false},
+		{"", "never", color.Never, false},
+		{"always", "never", color.Always, false},
+		{"bogus", "auto", color.Auto, true},
+	}
+	for _, tt := range tests {
+		config := internal.DefaultConfig()
+		config.Color = tt.configured
+		app := NewApp(internal.NewMemoryStorage(), config)
+		err := app.SetColor(tt.flag)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SetColor(%q) with %q configured: error = %v, want error = %v", tt.flag, tt.configured, err, tt.wantErr)
+		}
+		if err == nil && app.color != tt.want {
+			t.Errorf("SetColor(%q) with %q configured: mode = %v, want %v", tt.flag, tt.configured, app.color, tt.want)
+		}
+	}
+	color.SetDefault(color.Auto)
+
+	// An invalid flag is rejected when parsing, before any command runs
+	var cli CLI
+	parser, err := kong.New(&cli, kong.Vars{"version": "test"})
+	if err != nil {
+		t.Fatalf("kong.New() error = %v", err)
+	}
+	if _, err := parser.Parse([]string{"--color", "bogus", "doctor"}); err == nil {
+		t.Error("Parse(--color bogus doctor) succeeded, want an error")
+	}
+}
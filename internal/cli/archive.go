@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+// executeArchive packs a year's entries into a compressed archive, or lists archived
+// years when no year is given
+func (a *App) executeArchive(year int) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	if year == 0 {
+		years, err := storage.ArchivedYears()
+		if err != nil {
+			return err
+		}
+		if len(years) == 0 {
+			fmt.Println("No archived years")
+			return nil
+		}
+		for _, year := range years {
+			fmt.Println(year)
+		}
+		return nil
+	}
+
+	if year >= time.Now().Year() {
+		return fmt.Errorf("only past years can be archived")
+	}
+
+	count, err := storage.Archive(year)
+	a.commitChanges(fmt.Sprintf("archive %d", year))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Archived %d %s from %d\n", count, plural("entry", count), year)
+	fmt.Printf("Entries from %d are read-only; run 'jrnlg unarchive %d' to edit them\n", year, year)
+	return nil
+}
+
+// executeUnarchive expands an archived year so its entries can be edited again
+func (a *App) executeUnarchive(year int) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	count, err := storage.Unarchive(year)
+	a.commitChanges(fmt.Sprintf("unarchive %d", year))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Unarchived %d %s from %d\n", count, plural("entry", count), year)
+	return nil
+}
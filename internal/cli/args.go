@@ -18,4 +18,5 @@ type SearchArgs struct {
 	Format    string // "full", "summary", "json"
 	Reverse   bool
 	ColorMode color.Mode // Color mode: auto, always, never
+	Journals  []string   // Named journals to search together (empty = the selected journal)
 }
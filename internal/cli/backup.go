@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// executeBackup writes a backup archive of the storage tree
+// With keep > 0, the archive name gets a timestamp and older backups beyond keep are deleted
+func (a *App) executeBackup(target string, keep int) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	if keep < 0 {
+		return fmt.Errorf("--keep must be at least 1")
+	}
+	if keep > 0 {
+		target = internal.TimestampedBackupPath(target, time.Now())
+	} else if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return fmt.Errorf("%s is a directory; give an archive name or use --keep for timestamped backups", target)
+	}
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("%s already exists", target)
+	}
+	if abs, err := filepath.Abs(target); err == nil {
+		if storage, err := filepath.Abs(a.config.StoragePath); err == nil {
+			if rel, err := filepath.Rel(storage, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return fmt.Errorf("backups must be saved outside the storage directory")
+			}
+		}
+	}
+
+	// Write to a temp file first, so a failed backup never leaves a partial archive
+	tmpFile, err := os.CreateTemp(filepath.Dir(target), ".jrnlg-backup-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	manifest, err := storage.Backup(tmpFile)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+
+	fmt.Printf("✓ Backed up %d %s (%d %s) to %s\n",
+		manifest.Entries(), plural("entry", manifest.Entries()),
+		len(manifest.Files), plural("file", len(manifest.Files)), target)
+
+	if keep > 0 {
+		deleted, err := internal.RotateBackups(target, keep)
+		for _, path := range deleted {
+			fmt.Printf("Removed old backup %s\n", path)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to remove old backups: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// executeRestore validates a backup archive and restores it into the journal
+func (a *App) executeRestore(archivePath string, dryRun bool) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	archive, err := internal.ReadBackup(file)
+	_ = file.Close()
+	if err != nil {
+		return err
+	}
+
+	manifest := archive.Manifest
+	fmt.Printf("Backup from %s: %d %s, %d %s (checksums verified)\n",
+		manifest.CreatedAt.Local().Format("2006-01-02 3:04 PM"),
+		manifest.Entries(), plural("entry", manifest.Entries()),
+		len(manifest.Files), plural("file", len(manifest.Files)))
+
+	result, err := storage.Restore(archive, dryRun)
+	if !dryRun {
+		a.commitChanges("restore backup " + filepath.Base(archivePath))
+	}
+	if err != nil {
+		if errors.Is(err, internal.ErrWrongPassphrase) {
+			return err
+		}
+		return fmt.Errorf("restore failed: %w", err)
+	}
+
+	if dryRun || result.Into == "existing" {
+		for _, item := range result.Items {
+			switch item.Action {
+			case internal.RestoreNew:
+				fmt.Printf("  restore    %s\n", a.displayPath(item.Target))
+			case internal.RestoreCollision:
+				fmt.Printf("  collision  %s (differs from the journal's entry)\n", a.displayPath(item.Target))
+			case internal.RestoreKept:
+				fmt.Printf("  keep       %s (journal's version kept)\n", item.Path)
+			}
+		}
+	}
+
+	verb := "Restored"
+	if dryRun {
+		verb = "Would restore"
+	}
+	restored := result.Count(internal.RestoreNew) + result.Count(internal.RestoreCollision)
+	fmt.Printf("%s %d %s into the %s journal (%d already present, %d kept, %d as collisions)\n",
+		verb, restored, plural("file", restored), result.Into,
+		result.Count(internal.RestoreSame), result.Count(internal.RestoreKept), result.Count(internal.RestoreCollision))
+
+	if !dryRun && restored > 0 && result.Into == "existing" && storage.IsSigned() {
+		fmt.Println("Run 'jrnlg sign' to sign the restored entries.")
+	}
+	return nil
+}
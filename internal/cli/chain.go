@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jashort/jrnlg/internal"
+	"github.com/jashort/jrnlg/internal/cli/color"
+)
+
+// executeSign starts signing entries or signs the changes made since the last signature
+func (a *App) executeSign() error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	result, err := storage.SignChain()
+	a.commitChanges("sign entries")
+	if err != nil {
+		return err
+	}
+
+	if result.KeyCreated {
+		fmt.Printf("Created signing key %s\n", a.config.SigningKeyPath)
+		fmt.Println("Keep it safe: it is needed to add, edit or delete entries from now on.")
+	}
+	if result.Started {
+		fmt.Println("Started the hash chain; entries are now signed when they change.")
+	}
+
+	total := result.Added + result.Amended + result.Deleted
+	if total == 0 {
+		fmt.Println("All entries are signed.")
+		return nil
+	}
+	fmt.Printf("✓ Signed %d new, %d changed and %d deleted %s\n",
+		result.Added, result.Amended, result.Deleted, plural("entry", total))
+	return nil
+}
+
+// executeVerify checks the signed hash chain against the entry files
+func (a *App) executeVerify() error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	report, err := storage.VerifyChain()
+	if errors.Is(err, internal.ErrNoChain) {
+		return fmt.Errorf("%w; run 'jrnlg sign' to start signing them", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	colorizer := a.colorizer()
+	fmt.Printf("Hash chain of %d %s, signing key %s\n\n",
+		report.Links, plural("link", report.Links), colorizer.Dim(report.Fingerprint))
+
+	if report.Broken != "" {
+		fmt.Printf("%s %s\n", color.Red("✗ Chain broken:"), report.Broken)
+	}
+	for _, change := range report.Modified {
+		fmt.Printf("%s %s (differs from link %d, %s)\n", color.Red("✗ Modified:"), a.displayPath(change.Path),
+			change.Signed.Seq, change.Signed.Time.Local().Format("2006-01-02 3:04 PM"))
+	}
+	for _, path := range report.Inserted {
+		fmt.Printf("%s %s (never signed)\n", color.Red("✗ Inserted:"), a.displayPath(path))
+	}
+	for _, link := range report.Removed {
+		fmt.Printf("%s %s (signed in link %d)\n", color.Red("✗ Removed:"), link.Path, link.Seq)
+	}
+
+	problems := len(report.Modified) + len(report.Inserted) + len(report.Removed)
+	if report.Broken != "" {
+		problems++
+	}
+	if problems > 0 {
+		fmt.Println()
+		return fmt.Errorf("verification failed: %d %s (%d %s verified)",
+			problems, plural("problem", problems), report.Verified, plural("entry", report.Verified))
+	}
+
+	fmt.Printf("%s %d %s verified, chain intact\n",
+		color.Green("✓"), report.Verified, plural("entry", report.Verified))
+	return nil
+}
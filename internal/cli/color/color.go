@@ -119,6 +119,11 @@ func ParseMode(s string) (Mode, error) {
 // Default colorizer for convenience functions
 var defaultColorizer = New(Auto)
 
+// SetDefault sets the mode of the convenience functions (Cyan, Green, Yellow, Red)
+func SetDefault(mode Mode) {
+	defaultColorizer = New(mode)
+}
+
 // Cyan returns text in cyan (for headers)
 func Cyan(s string) string {
 	if !defaultColorizer.enabled {
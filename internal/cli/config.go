@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// listConfig prints every setting with its value and where the value comes from
+func (a *App) listConfig() error {
+	settings := append([]*internal.Setting{}, internal.Settings...)
+	names := make([]string, 0, len(a.config.Templates))
+	for name := range a.config.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		setting, err := internal.LookupSetting(internal.TemplatesKey + "." + name)
+		if err != nil {
+			return err
+		}
+		settings = append(settings, setting)
+	}
+
+	width := 0
+	for _, setting := range settings {
+		width = max(width, len(setting.Key))
+	}
+	for _, setting := range settings {
+		fmt.Printf("%-*s = %s  (%s)\n", width, setting.Key,
+			internal.FormatConfigValue(setting.Value(a.config)), a.config.Source(setting.Key))
+	}
+	return nil
+}
+
+// getConfig prints the value of a setting
+// Strings are printed as they are, for use in scripts; other values as TOML
+func (a *App) getConfig(key string) error {
+	setting, err := internal.LookupSetting(key)
+	if err != nil {
+		return err
+	}
+
+	switch value := setting.Value(a.config).(type) {
+	case string:
+		fmt.Println(value)
+	default:
+		fmt.Println(internal.FormatConfigValue(value))
+	}
+	return nil
+}
+
+// setConfig validates a value and writes it to the config file
+func (a *App) setConfig(key, text string) error {
+	setting, err := internal.LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	value, err := setting.Set(internal.DefaultConfig(), text)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	path := internal.ConfigPath()
+	if err := internal.SetConfigFileValue(path, key, value); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Set %s in %s\n", key, path)
+	if source := a.config.Source(key); source != "default" && source != "config file" {
+		_, _ = fmt.Fprintf(os.Stderr, "Note: %s is set and takes precedence over the config file\n", source)
+	}
+	return nil
+}
+
+// showConfigPath prints the path of the config file
+func (a *App) showConfigPath() error {
+	fmt.Println(internal.ConfigPath())
+	return nil
+}
+
+// orDefault returns value, or the configured default if no value was given
+func orDefault(value, configured string) string {
+	if value == "" {
+		return configured
+	}
+	return value
+}
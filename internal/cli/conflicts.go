@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jashort/jrnlg/internal"
+	"github.com/jashort/jrnlg/internal/cli/color"
+)
+
+// Sync conflict resolution choices
+const (
+	keepEntry = "e"
+	keepCopy  = "c"
+	keepBoth  = "b"
+	mergeBoth = "m"
+	skipCopy  = "s"
+)
+
+// executeConflicts shows conflict copies left by file sync tools and resolves them one by one
+func (a *App) executeConflicts(listOnly bool) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	conflicts, err := storage.FindConflictCopies()
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Println("No sync conflicts found.")
+		return nil
+	}
+
+	if listOnly {
+		for _, conflict := range conflicts {
+			primary := "entry no longer exists"
+			if conflict.Primary != "" {
+				primary = "conflicts with " + a.displayPath(conflict.Primary)
+			}
+			fmt.Printf("%s  (%s)\n", a.displayPath(conflict.Path), primary)
+		}
+		return nil
+	}
+
+	resolved := 0
+	for i, conflict := range conflicts {
+		fmt.Printf("\nConflict %d of %d: %s\n", i+1, len(conflicts), a.displayPath(conflict.Path))
+
+		done, err := a.resolveConflictCopy(storage, conflict)
+		if err != nil {
+			a.commitChanges(fmt.Sprintf("resolve %d sync %s", resolved, plural("conflict", resolved)))
+			return err
+		}
+		if done {
+			resolved++
+		}
+	}
+
+	a.commitChanges(fmt.Sprintf("resolve %d sync %s", resolved, plural("conflict", resolved)))
+	fmt.Printf("\n✓ Resolved %d of %d %s\n", resolved, len(conflicts), plural("conflict", len(conflicts)))
+	return nil
+}
+
+// resolveConflictCopy shows how a conflict copy differs from its entry and applies the
+// user's choice. Returns false if the conflict was skipped.
+func (a *App) resolveConflictCopy(storage *internal.FileSystemStorage, conflict internal.ConflictCopy) (bool, error) {
+	copyContent, err := storage.ReadEntryText(conflict.Path)
+	if err != nil {
+		return false, err
+	}
+
+	// The entry is gone: the copy is the only version left
+	if conflict.Primary == "" {
+		fmt.Printf("The entry it conflicts with no longer exists.\n\n%s\n", strings.TrimRight(string(copyContent), "\n"))
+		fmt.Printf("\n  [c] Keep the copy as an entry\n  [e] Delete the copy\n  [s] Skip\n\n")
+
+		choice, err := promptChoice(keepCopy, keepEntry, skipCopy)
+		if err != nil || choice == skipCopy {
+			return false, err
+		}
+		if choice == keepEntry {
+			return true, storage.KeepConflictPrimary(conflict)
+		}
+		path, err := storage.KeepConflictBoth(conflict)
+		if err == nil {
+			fmt.Printf("Saved as %s\n", a.displayPath(path))
+		}
+		return err == nil, err
+	}
+
+	primaryContent, err := storage.ReadEntryText(conflict.Primary)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Printf("Entry: %s\n\n", a.displayPath(conflict.Primary))
+	printDiff(a.colorizer(), string(primaryContent), string(copyContent))
+	fmt.Printf("\n  [e] Keep the entry (delete the copy)\n")
+	fmt.Printf("  [c] Keep the copy (replace the entry)\n")
+	fmt.Printf("  [b] Keep both (save the copy as a separate entry)\n")
+	fmt.Printf("  [m] Merge both in the editor\n")
+	fmt.Printf("  [s] Skip\n\n")
+
+	for {
+		choice, err := promptChoice(keepEntry, keepCopy, keepBoth, mergeBoth, skipCopy)
+		if err != nil {
+			return false, err
+		}
+
+		switch choice {
+		case keepEntry:
+			err = storage.KeepConflictPrimary(conflict)
+		case keepCopy:
+			_, err = storage.KeepConflictCopy(conflict)
+		case keepBoth:
+			var path string
+			if path, err = storage.KeepConflictBoth(conflict); err == nil {
+				fmt.Printf("Saved the copy as %s\n", a.displayPath(path))
+			}
+		case mergeBoth:
+			err = a.mergeConflictCopy(storage, conflict, string(primaryContent), string(copyContent))
+		default: // skipCopy
+			return false, nil
+		}
+
+		if err == nil {
+			return true, nil
+		}
+		// Let the user pick again (e.g. after leaving conflict markers in a merge)
+		_, _ = fmt.Fprintf(os.Stderr, "Could not resolve: %v\n", err)
+	}
+}
+
+// mergeConflictCopy opens both versions in the editor, with their differences between
+// conflict markers, and saves the result as the entry
+func (a *App) mergeConflictCopy(storage *internal.FileSystemStorage, conflict internal.ConflictCopy, primary, conflictCopy string) error {
+	merged, err := OpenEditor(internal.MarkConflicts(primary, conflictCopy), a.config)
+	if err != nil {
+		return fmt.Errorf("failed to open editor: %w", err)
+	}
+	return storage.ResolveConflictWith(conflict, merged)
+}
+
+// printDiff shows a line diff between an entry (-) and its conflict copy (+)
+func printDiff(colorizer *color.Colorizer, primary, conflictCopy string) {
+	fmt.Println(colorizer.Dim("--- entry"))
+	fmt.Println(colorizer.Dim("+++ conflict copy"))
+
+	for _, line := range internal.DiffLines(primary, conflictCopy) {
+		switch line.Op {
+		case internal.DiffDelete:
+			fmt.Println(color.Red("- " + line.Text))
+		case internal.DiffInsert:
+			fmt.Println(color.Green("+ " + line.Text))
+		default:
+			fmt.Println("  " + line.Text)
+		}
+	}
+}
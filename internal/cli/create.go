@@ -9,43 +9,77 @@ import (
 	"github.com/jashort/jrnlg/internal"
 )
 
-// CreateEntry opens an editor for the user to create a new journal entry
-func (a *App) CreateEntry() error {
+// CreateEntry opens an editor for the user to create a new journal entry, starting from
+// the named template in the config file ("" for the "default" template, if there is one)
+// The text is kept as a draft until it is saved, so it survives invalid input and editor crashes
+func (a *App) CreateEntry(templateName string) error {
 	// 1. Generate pre-populated template with current timestamp
+	body, err := a.entryTemplate(templateName)
+	if err != nil {
+		return err
+	}
 	timestamp := time.Now()
 	header := internal.FormatTimestamp(timestamp)
-	template := fmt.Sprintf("## %s\n\n", header)
+	template := fmt.Sprintf("## %s\n\n%s", header, body)
 
-	// 2. Open editor with configured arguments
-	content, err := OpenEditor(template, a.config.EditorArgs)
-	if err != nil {
-		return fmt.Errorf("cannot open editor: %w", err)
+	// 2. Create a draft holding the template
+	draft := &internal.Draft{}
+	if err := a.drafts().Create(template, draft); err != nil {
+		return fmt.Errorf("cannot create draft: %w", err)
 	}
 
-	// 3. Handle empty entry (silent discard)
-	if isEmptyEntry(content) {
-		return nil // Exit silently
+	// 3. Edit and save
+	return a.saveNewEntryDraft(draft)
+}
+
+// entryTemplate returns the body text of a template from the config file
+func (a *App) entryTemplate(name string) (string, error) {
+	if name == "" {
+		name = "default"
+		if _, ok := a.config.Templates[name]; !ok {
+			return "", nil
+		}
 	}
 
-	// 4. Parse entry
-	entry, err := internal.ParseEntry(content)
+	body, ok := a.config.Templates[name]
+	if !ok {
+		return "", fmt.Errorf("no template %q (add one with 'jrnlg config set templates.%s ...')", name, name)
+	}
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	return body, nil
+}
+
+// saveNewEntryDraft opens a new-entry draft in the editor and saves the result as an entry
+func (a *App) saveNewEntryDraft(draft *internal.Draft) error {
+	// 1. Edit until the draft is a valid entry
+	_, entry, err := a.editDraft(draft, nil)
 	if err != nil {
-		return fmt.Errorf("invalid entry format: %w", err)
+		return err
+	}
+
+	// 2. Handle empty entry (silent discard)
+	if entry == nil {
+		a.discardDraft(draft.ID)
+		return nil
 	}
 
-	// 5. Check for timestamp collision and warn user
+	// 3. Check for timestamp collision and warn user
 	if a.hasCollision(entry.Timestamp) {
 		_, _ = fmt.Fprintf(os.Stderr, "Warning: Another entry exists with timestamp %s\n",
 			entry.Timestamp.Format("2006-01-02 3:04 PM"))
 		_, _ = fmt.Fprintf(os.Stderr, "Saved with collision suffix\n")
 	}
 
-	// 6. Save entry
+	// 4. Save entry (the draft is kept if this fails)
 	if err := a.storage.SaveEntry(entry); err != nil {
-		return fmt.Errorf("failed to save entry: %w", err)
+		return fmt.Errorf("failed to save entry: %w (draft kept: %s)", err, draft.ID)
 	}
+	a.discardDraft(draft.ID)
+	a.commitChanges("add entry " + entryLabel(entry.Timestamp))
 
-	// 7. Confirmation
+	// 5. Confirmation
 	fmt.Printf("Entry saved successfully.\n")
 	fmt.Printf("Timestamp: %s\n", entry.Timestamp.Format("2006-01-02 3:04 PM"))
 
@@ -76,6 +110,7 @@ func (a *App) CreateEntryWithMessage(message string) error {
 	if err := a.storage.SaveEntry(entry); err != nil {
 		return fmt.Errorf("failed to save entry: %w", err)
 	}
+	a.commitChanges("add entry " + entryLabel(entry.Timestamp))
 
 	// 5. Confirmation with timestamp, tags, and mentions
 	fmt.Printf("Entry saved successfully.\n")
@@ -108,14 +143,15 @@ func (a *App) CreateEntryWithMessage(message string) error {
 	return nil
 }
 
-// isEmptyEntry checks if the entry has no body text
+// isEmptyEntry checks if the entry has no body text: it doesn't parse, and it is only
+// blank lines and a header line, which may be broken (e.g. the user cleared the buffer
+// but a line of it)
 func isEmptyEntry(content string) bool {
-	entry, err := internal.ParseEntry(content)
-	if err != nil {
-		// If it doesn't parse, it's not empty (it's invalid)
+	if _, err := internal.ParseEntry(content); err == nil {
 		return false
 	}
-	return strings.TrimSpace(entry.Body) == ""
+	header, rest, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	return (header == "" || strings.HasPrefix(header, "##")) && strings.TrimSpace(rest) == ""
 }
 
 // hasCollision checks if an entry with this timestamp already exists
@@ -57,6 +57,7 @@ func (a *App) executeDelete(selector string, fromDate, toDate *time.Time, force
 	// Delete entries
 	var deletedPaths []string
 	var deleteErrors []string
+	var lastDeleted *internal.JournalEntry
 
 	for _, entry := range entries {
 		filePath, err := a.storage.GetEntryPath(entry.Timestamp)
@@ -72,9 +73,18 @@ func (a *App) executeDelete(selector string, fromDate, toDate *time.Time, force
 		}
 
 		deletedPaths = append(deletedPaths, filePath)
+		lastDeleted = entry
+	}
+
+	// Commit what was deleted, if anything
+	if len(deletedPaths) == 1 {
+		a.commitChanges("delete entry " + entryLabel(lastDeleted.Timestamp))
+	} else if len(deletedPaths) > 1 {
+		a.commitChanges(fmt.Sprintf("delete %d entries", len(deletedPaths)))
 	}
 
 	// Report results
+
 	if len(deletedPaths) > 0 {
 		fmt.Printf("Successfully deleted %d entr", len(deletedPaths))
 		if len(deletedPaths) == 1 {
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jashort/jrnlg/internal"
+	"github.com/jashort/jrnlg/internal/cli/color"
+)
+
+// issueHeadings are the section titles for each kind of integrity issue, in display order
+var issueHeadings = []struct {
+	kind    internal.IssueKind
+	heading string
+}{
+	{internal.IssueUnparsable, "Unparsable files"},
+	{internal.IssueWrongDirectory, "Entries in the wrong directory"},
+	{internal.IssueTimestampMismatch, "Filenames that don't match the header timestamp"},
+	{internal.IssueDuplicate, "Duplicated entries"},
+	{internal.IssueSyncConflict, "Sync conflict copies"},
+	{internal.IssueStrayTempFile, "Stray temp files"},
+	{internal.IssueClutter, "Other files"},
+}
+
+// executeDoctor checks the storage tree and optionally repairs what can be fixed safely
+func (a *App) executeDoctor(fix bool) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	report, err := storage.CheckIntegrity()
+	if err != nil {
+		return err
+	}
+
+	colorizer := a.colorizer()
+	fmt.Printf("Checked %d %s in %s\n", report.FilesScanned, plural("file", report.FilesScanned), a.config.StoragePath)
+
+	if len(report.Issues) == 0 {
+		fmt.Println(color.Green("✓ No problems found"))
+		return nil
+	}
+
+	// Group by kind
+	for _, section := range issueHeadings {
+		var issues []internal.Issue
+		for _, issue := range report.Issues {
+			if issue.Kind == section.kind {
+				issues = append(issues, issue)
+			}
+		}
+		if len(issues) == 0 {
+			continue
+		}
+
+		fmt.Printf("\n%s (%d):\n", section.heading, len(issues))
+		for _, issue := range issues {
+			marker := " "
+			if issue.Fixable {
+				marker = "*"
+			}
+			fmt.Printf(" %s %s\n     %s\n", marker, a.displayPath(issue.Path), colorizer.Dim(issue.Detail))
+		}
+	}
+
+	fixable := report.Fixable()
+	fmt.Printf("\nFound %d %s; %d can be fixed automatically (marked *).\n",
+		len(report.Issues), plural("problem", len(report.Issues)), len(fixable))
+
+	if !fix {
+		if len(fixable) > 0 {
+			fmt.Println("Run 'jrnlg doctor --fix' to repair them.")
+		}
+		return nil
+	}
+
+	fixed, err := storage.RepairIssues(fixable)
+	a.commitChanges(fmt.Sprintf("doctor: fix %d %s", len(fixed), plural("problem", len(fixed))))
+	for _, issue := range fixed {
+		if issue.Target == "" {
+			fmt.Printf("Removed %s\n", a.displayPath(issue.Path))
+		} else {
+			fmt.Printf("Moved %s -> %s\n", a.displayPath(issue.Path), a.displayPath(issue.Target))
+		}
+	}
+	fmt.Printf("✓ Fixed %d %s\n", len(fixed), plural("problem", len(fixed)))
+
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "\nErrors encountered:\n%v\n", err)
+		return fmt.Errorf("failed to fix %d of %d problems", len(fixable)-len(fixed), len(fixable))
+	}
+
+	return nil
+}
+
+// displayPath shows a storage path relative to the storage root
+func (a *App) displayPath(path string) string {
+	if rel, err := filepath.Rel(a.config.StoragePath, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// drafts returns the draft store for the current journal
+// Backends that don't keep drafts themselves get drafts in the configured storage path
+func (a *App) drafts() *internal.DraftStore {
+	if provider, ok := a.storage.(internal.DraftProvider); ok {
+		return provider.Drafts()
+	}
+	return internal.NewDraftStore(internal.DraftsPath(a.config.StoragePath))
+}
+
+// editDraft opens a draft in the editor until its content is a valid entry.
+// When the content can't be parsed or fails validate, the editor is reopened with the
+// error inserted as a comment at the top. If the user closes the editor without changing
+// anything, editing stops and the draft is kept for 'jrnlg drafts resume'.
+// Returns the content and parsed entry, or a nil entry if the user emptied the draft.
+func (a *App) editDraft(draft *internal.Draft, validate func(*internal.JournalEntry) error) (string, *internal.JournalEntry, error) {
+	store := a.drafts()
+	previous := ""
+
+	for {
+		// 1. Open editor on the draft file (left behind if the editor crashes)
+		if err := a.editDraftFile(store, draft.ID); err != nil {
+			return "", nil, fmt.Errorf("cannot open editor: %w (draft kept: %s)", err, draft.ID)
+		}
+
+		// 2. Read edited content without our notice comment
+		raw, err := store.ReadContent(draft.ID)
+		if err != nil {
+			return "", nil, err
+		}
+		content := internal.StripDraftNotice(raw)
+
+		// 3. Nothing written
+		if isEmptyEntry(content) {
+			return content, nil, nil
+		}
+
+		// 4. Parse and validate
+		entry, err := internal.ParseEntry(content)
+		if err == nil && validate != nil {
+			err = validate(entry)
+		}
+		if err == nil {
+			return content, entry, nil
+		}
+
+		// 5. Record the error; give up if the user didn't change anything
+		draft.Error = err.Error()
+		if updateErr := store.Update(draft); updateErr != nil {
+			return "", nil, updateErr
+		}
+
+		if content == previous {
+			return "", nil, fmt.Errorf("invalid entry format: %w\n\nDraft kept. Run 'jrnlg drafts resume %s' to fix it", err, draft.ID)
+		}
+		previous = content
+
+		// 6. Reopen with the error at the top
+		if err := store.WriteContent(draft.ID, internal.AddDraftNotice(content, err)); err != nil {
+			return "", nil, err
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid entry: %v\nReopening editor...\n", err)
+	}
+}
+
+// editDraftFile opens a draft in the editor. Drafts of an encrypted journal are encrypted,
+// so they are edited in a decrypted temp copy and encrypted again afterwards.
+func (a *App) editDraftFile(store *internal.DraftStore, id string) error {
+	if !a.isEncrypted() {
+		return EditFile(store.Path(id), a.config)
+	}
+
+	content, err := store.ReadContent(id)
+	if err != nil {
+		return err
+	}
+	edited, err := OpenEditor(content, a.config)
+	if err != nil {
+		return err
+	}
+	return store.WriteContent(id, edited)
+}
+
+// discardDraft removes a draft after it has been saved, warning on failure
+func (a *App) discardDraft(id string) {
+	if err := a.drafts().Discard(id); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to remove draft %s: %v\n", id, err)
+	}
+}
+
+// listDrafts displays all drafts left behind by failed or interrupted editor sessions
+func (a *App) listDrafts() error {
+	drafts, err := a.drafts().List()
+	if err != nil {
+		return err
+	}
+
+	if len(drafts) == 0 {
+		fmt.Println("No drafts found.")
+		return nil
+	}
+
+	for _, draft := range drafts {
+		kind := "new entry"
+		if draft.IsEdit() {
+			kind = "edit of " + draft.Target
+		}
+
+		preview := ""
+		if content, err := a.drafts().ReadContent(draft.ID); err == nil {
+			preview = getFirstLine(bodyWithoutHeader(internal.StripDraftNotice(content)))
+		}
+
+		fmt.Printf("%s  %s  (%s)\n", draft.ID, draft.UpdatedAt.Format("2006-01-02 3:04 PM"), kind)
+		if preview != "" {
+			fmt.Printf("   %s\n", TruncateBody(preview, 70))
+		}
+		if draft.Error != "" {
+			fmt.Printf("   Error: %s\n", TruncateBody(draft.Error, 70))
+		}
+	}
+
+	return nil
+}
+
+// resumeDraft reopens a draft in the editor and saves it
+// An empty id resumes the most recent draft
+func (a *App) resumeDraft(id string) error {
+	draft, err := a.findDraft(id)
+	if err != nil {
+		return err
+	}
+
+	if !draft.IsEdit() {
+		return a.saveNewEntryDraft(draft)
+	}
+
+	// The entry being edited may have been deleted since; save the draft as a new entry instead
+	if _, err := a.storage.GetEntryVersion(draft.Target); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: %s no longer exists; the draft will be saved as a new entry\n", draft.Target)
+		draft.Target = ""
+		return a.saveNewEntryDraft(draft)
+	}
+
+	original, err := internal.ParseEntry(draft.Base)
+	if err != nil {
+		return fmt.Errorf("invalid draft %s: %w", draft.ID, err)
+	}
+
+	return a.saveEditDraft(draft, original.Timestamp)
+}
+
+// discardDrafts deletes one draft by ID, or all drafts
+func (a *App) discardDrafts(id string, all, force bool) error {
+	if !all {
+		if id == "" {
+			return fmt.Errorf("specify a draft ID or --all")
+		}
+		if err := a.drafts().Discard(id); err != nil {
+			return err
+		}
+		fmt.Printf("Discarded draft %s.\n", id)
+		return nil
+	}
+
+	drafts, err := a.drafts().List()
+	if err != nil {
+		return err
+	}
+	if len(drafts) == 0 {
+		fmt.Println("No drafts found.")
+		return nil
+	}
+
+	if !force {
+		fmt.Printf("Discard %d %s? (y/N): ", len(drafts), plural("draft", len(drafts)))
+		if !promptYes() {
+			fmt.Println("Canceled")
+			return nil
+		}
+	}
+
+	for _, draft := range drafts {
+		if err := a.drafts().Discard(draft.ID); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Discarded %d %s.\n", len(drafts), plural("draft", len(drafts)))
+	return nil
+}
+
+// findDraft returns the draft with the given ID, or the most recent draft if id is empty
+func (a *App) findDraft(id string) (*internal.Draft, error) {
+	if id != "" {
+		return a.drafts().Get(id)
+	}
+
+	drafts, err := a.drafts().List()
+	if err != nil {
+		return nil, err
+	}
+	if len(drafts) == 0 {
+		return nil, fmt.Errorf("no drafts found")
+	}
+
+	return drafts[len(drafts)-1], nil
+}
+
+// bodyWithoutHeader drops everything up to and including the '##' header line
+func bodyWithoutHeader(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "##") {
+			return strings.Join(lines[i+1:], "\n")
+		}
+	}
+	return content
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// newDraftTestApp creates an app with temporary storage and a no-op editor
+func newDraftTestApp(t *testing.T) (*App, *internal.FileSystemStorage) {
+	t.Helper()
+	tempDir := t.TempDir()
+	config := &internal.Config{
+		StoragePath:     tempDir,
+		MaxParseWorkers: 4,
+		ParallelParse:   true,
+	}
+	storage := internal.NewFileSystemStorage(tempDir, config)
+
+	// "true" exits immediately, leaving the draft unchanged
+	t.Setenv("VISUAL", "true")
+
+	return NewApp(storage, config), storage
+}
+
+func TestEditDraft_ValidDraftIsReturned(t *testing.T) {
+	app, _ := newDraftTestApp(t)
+
+	draft := &internal.Draft{}
+	if err := app.drafts().Create("## Monday 2026-02-09 2:30 PM UTC\n\nValid #entry", draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	_, entry, err := app.editDraft(draft, nil)
+	if err != nil {
+		t.Fatalf("editDraft() error = %v", err)
+	}
+	if entry == nil || entry.Body != "Valid #entry" {
+		t.Errorf("editDraft() entry = %+v, want parsed entry", entry)
+	}
+}
+
+func TestEditDraft_InvalidDraftIsKept(t *testing.T) {
+	app, _ := newDraftTestApp(t)
+
+	draft := &internal.Draft{}
+	if err := app.drafts().Create("## not a timestamp\n\nSome text worth keeping", draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	_, _, err := app.editDraft(draft, nil)
+	if err == nil {
+		t.Fatal("editDraft() should fail when the draft stays invalid")
+	}
+	if !strings.Contains(err.Error(), draft.ID) {
+		t.Errorf("error should mention the draft ID, got: %v", err)
+	}
+
+	// Text and error notice are preserved in the draft
+	content, err := app.drafts().ReadContent(draft.ID)
+	if err != nil {
+		t.Fatalf("ReadContent() error = %v", err)
+	}
+	if !strings.Contains(content, "Some text worth keeping") {
+		t.Error("Draft text was lost")
+	}
+	if !strings.HasPrefix(content, "<!-- jrnlg:") {
+		t.Error("Draft should have the error notice at the top")
+	}
+
+	saved, err := app.drafts().Get(draft.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if saved.Error == "" {
+		t.Error("Draft metadata should record the error")
+	}
+}
+
+func TestSaveNewEntryDraft(t *testing.T) {
+	app, storage := newDraftTestApp(t)
+
+	draft := &internal.Draft{}
+	if err := app.drafts().Create("## Monday 2026-02-09 2:30 PM UTC\n\nResumed draft", draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	oldStdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	err := app.resumeDraft(draft.ID)
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("resumeDraft() error = %v", err)
+	}
+
+	entries, err := storage.ListEntries(internal.EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Body != "Resumed draft" {
+		t.Errorf("Expected the draft to be saved as an entry, got %v", entries)
+	}
+
+	if _, err := app.drafts().Get(draft.ID); err == nil {
+		t.Error("Draft should be removed after saving")
+	}
+}
+
+func TestSaveNewEntryDraft_EmptyTemplateIsDiscarded(t *testing.T) {
+	app, storage := newDraftTestApp(t)
+
+	draft := &internal.Draft{}
+	if err := app.drafts().Create("## Monday 2026-02-09 2:30 PM UTC\n\n", draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := app.saveNewEntryDraft(draft); err != nil {
+		t.Fatalf("saveNewEntryDraft() error = %v", err)
+	}
+
+	entries, _ := storage.ListEntries(internal.EntryFilter{})
+	if len(entries) != 0 {
+		t.Errorf("Expected no entries, got %d", len(entries))
+	}
+	if _, err := app.drafts().Get(draft.ID); err == nil {
+		t.Error("Empty draft should be discarded")
+	}
+}
+
+func TestIsEmptyEntry(t *testing.T) {
+	tests := map[string]bool{
+		"":                                     true,
+		"\n  \n":                               true,
+		"## Monday 2026-02-09 2:30 PM UTC\n\n": true,
+		"## not a timestamp\n":                 true,
+		"## Monday 2026-02-09 2:30 PM UTC\n\nText":               false,
+		"## Monday 2026-02-09 2:30 PM UTC\n\n## Ideas\n### Todo": false,
+		"## not a timestamp\n\n## Ideas":                         false,
+		"Text without a header":                                  false,
+	}
+	for content, want := range tests {
+		if got := isEmptyEntry(content); got != want {
+			t.Errorf("isEmptyEntry(%q) = %v, want %v", content, got, want)
+		}
+	}
+}
+
+// newEditConflict saves an entry, starts an edit of it as a draft and changes the entry
+// on disk; returns the draft and the edited content
+func newEditConflict(t *testing.T, app *App, storage *internal.FileSystemStorage) (*internal.Draft, string) {
+	t.Helper()
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+	if err := storage.SaveEntry(&internal.JournalEntry{Timestamp: ts, Body: "First line"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	path, err := storage.GetEntryPath(ts)
+	if err != nil {
+		t.Fatalf("GetEntryPath() error = %v", err)
+	}
+	version, err := storage.GetEntryVersion(path)
+	if err != nil {
+		t.Fatalf("GetEntryVersion() error = %v", err)
+	}
+
+	base := internal.SerializeEntry(&internal.JournalEntry{Timestamp: ts, Body: "First line"})
+	draft := &internal.Draft{Target: path, Base: base, BaseHash: version.Hash}
+	mine := internal.SerializeEntry(&internal.JournalEntry{Timestamp: ts, Body: "My line"})
+	if err := app.drafts().Create(mine, draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := storage.UpdateEntry(path, &internal.JournalEntry{Timestamp: ts, Body: "Their line"}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	return draft, mine
+}
+
+func TestMergeEditConflict_UnresolvedMergeIsKept(t *testing.T) {
+	app, storage := newDraftTestApp(t)
+	draft, mine := newEditConflict(t, app, storage)
+	mineEntry, _ := internal.ParseEntry(mine)
+
+	theirsEntry, _ := storage.GetEntryByPath(draft.Target)
+	version, _ := storage.GetEntryVersion(draft.Target)
+	err := app.mergeEditConflict(draft, mine, internal.SerializeEntry(theirsEntry), mineEntry.Timestamp, version)
+	if err == nil || !strings.Contains(err.Error(), draft.ID) {
+		t.Fatalf("mergeEditConflict() error = %v, want draft kept", err)
+	}
+
+	// The draft holds the merge, based on the version on disk
+	content, _ := app.drafts().ReadContent(draft.ID)
+	if !strings.Contains(content, "My line") || !strings.Contains(content, "Their line") {
+		t.Errorf("draft content = %q, want both versions", content)
+	}
+	saved, err := app.drafts().Get(draft.ID)
+	if err != nil || saved.BaseHash != version.Hash || !strings.Contains(saved.Base, "Their line") {
+		t.Errorf("draft = %+v, %v; want it based on the version on disk", saved, err)
+	}
+}
+
+func TestResolveEditConflict_KeepTheirsKeepsDraft(t *testing.T) {
+	app, storage := newDraftTestApp(t)
+	draft, mine := newEditConflict(t, app, storage)
+	mineEntry, _ := internal.ParseEntry(mine)
+
+	r, w, _ := os.Pipe()
+	_, _ = w.WriteString(conflictKeepTheirs + "\n")
+	_ = w.Close()
+	oldStdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = oldStdin }()
+
+	output := captureStdout(t, func() error {
+		if err := app.resolveEditConflict(draft, mine, mineEntry); err != errDraftKept {
+			t.Errorf("resolveEditConflict() error = %v, want errDraftKept", err)
+		}
+		return nil
+	})
+	if !strings.Contains(output, "drafts resume "+draft.ID) {
+		t.Errorf("output =\n%s", output)
+	}
+	if content, err := app.drafts().ReadContent(draft.ID); err != nil || !strings.Contains(content, "My line") {
+		t.Errorf("draft content = %q, %v; want my edit", content, err)
+	}
+	if entry, _ := storage.GetEntryByPath(draft.Target); entry.Body != "Their line" {
+		t.Errorf("entry body = %q, want the version on disk", entry.Body)
+	}
+}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -17,49 +19,205 @@ func (a *App) executeEdit(selector string) error {
 		return err
 	}
 
-	// Store original timestamp for validation
-	originalTimestamp := entry.Timestamp
+	// Record the file state so concurrent changes can be detected on save
+	version, err := a.storage.GetEntryVersion(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read entry: %w", err)
+	}
 
-	// Serialize entry for editing
+	// Serialize entry for editing and keep it as a draft until saved
 	content := internal.SerializeEntry(entry)
+	draft := &internal.Draft{
+		Target:   filePath,
+		Base:     content,
+		BaseHash: version.Hash,
+	}
+	if err := a.drafts().Create(content, draft); err != nil {
+		return fmt.Errorf("cannot create draft: %w", err)
+	}
+
+	return a.saveEditDraft(draft, entry.Timestamp)
+}
 
-	// Open editor
-	editedContent, err := OpenEditor(content, a.config.EditorArgs)
+// saveEditDraft opens an edit draft in the editor and writes the result back to its entry
+func (a *App) saveEditDraft(draft *internal.Draft, originalTimestamp time.Time) error {
+	// Edit until the draft is valid and keeps the original timestamp
+	editedContent, editedEntry, err := a.editDraft(draft, func(edited *internal.JournalEntry) error {
+		return validateTimestampUnchanged(originalTimestamp, edited.Timestamp)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to open editor: %w", err)
+		return err
 	}
 
-	// Check if entry was modified
-	if strings.TrimSpace(editedContent) == strings.TrimSpace(content) {
+	// Check if entry was modified (an emptied draft abandons the edit)
+	if editedEntry == nil || strings.TrimSpace(editedContent) == strings.TrimSpace(draft.Base) {
+		a.discardDraft(draft.ID)
 		fmt.Println("No changes made.")
 		return nil
 	}
 
-	// Parse edited content
-	editedEntry, err := internal.ParseEntry(editedContent)
+	// Update entry, unless it was changed on disk while the editor was open
+	version := internal.EntryVersion{Hash: draft.BaseHash}
+	err = a.storage.UpdateEntryIfUnchanged(draft.Target, editedEntry, version)
+	switch {
+	case errors.Is(err, internal.ErrEntryModified):
+		err = a.resolveEditConflict(draft, editedContent, editedEntry)
+	case err != nil:
+		err = fmt.Errorf("failed to update entry: %w (draft kept: %s)", err, draft.ID)
+	default:
+		fmt.Printf("Entry updated successfully.\n")
+		fmt.Printf("Timestamp: %s\n", editedEntry.Timestamp.Format("2006-01-02 3:04 PM"))
+	}
+
+	if errors.Is(err, errDraftKept) {
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("invalid entry format after edit: %w", err)
+		return err
 	}
 
-	// Validate timestamp wasn't changed
-	if err := validateTimestampUnchanged(originalTimestamp, editedEntry.Timestamp); err != nil {
+	a.discardDraft(draft.ID)
+	a.commitChanges("edit entry " + entryLabel(originalTimestamp))
+	return nil
+}
+
+// errDraftKept ends an edit without saving it and without error; the draft is kept for
+// 'jrnlg drafts resume'
+var errDraftKept = errors.New("draft kept")
+
+// Conflict resolution choices offered when an entry changed during editing
+const (
+	conflictMerge      = "m"
+	conflictKeepMine   = "k"
+	conflictKeepTheirs = "t"
+	conflictSaveNew    = "n"
+)
+
+// resolveEditConflict handles an entry that was modified on disk while it was being edited.
+// draft.Base is the content the edit started from, mine is the user's edited content.
+// Until the entry is saved the draft keeps the latest text, so nothing is lost if
+// resolving fails or is abandoned.
+func (a *App) resolveEditConflict(draft *internal.Draft, mine string, mineEntry *internal.JournalEntry) error {
+	theirsEntry, err := a.storage.GetEntryByPath(draft.Target)
+	if err != nil {
+		// The other version is gone or unreadable; don't lose the user's edit
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: entry changed on disk and can no longer be read: %v\n", err)
+		return a.saveEditAsNewEntry(mineEntry)
+	}
+
+	theirsVersion, err := a.storage.GetEntryVersion(draft.Target)
+	if err != nil {
+		return fmt.Errorf("failed to read entry: %w (draft kept: %s)", err, draft.ID)
+	}
+	theirs := internal.SerializeEntry(theirsEntry)
+
+	fmt.Printf("\n⚠ The entry was modified by another process while you were editing it (changed %s).\n\n",
+		theirsVersion.ModTime.Format("2006-01-02 3:04:05 PM"))
+	fmt.Printf("  [m] Merge both changes (opens editor if they conflict)\n")
+	fmt.Printf("  [k] Keep mine (overwrite the other changes)\n")
+	fmt.Printf("  [t] Keep theirs (my changes stay in a draft)\n")
+	fmt.Printf("  [n] Save mine as a new entry\n\n")
+
+	choice, err := promptChoice(conflictMerge, conflictKeepMine, conflictKeepTheirs, conflictSaveNew)
+	if err != nil {
+		return fmt.Errorf("%w (draft kept: %s)", err, draft.ID)
+	}
+
+	switch choice {
+	case conflictMerge:
+		return a.mergeEditConflict(draft, mine, theirs, mineEntry.Timestamp, theirsVersion)
+	case conflictKeepMine:
+		if err := a.rebaseDraft(draft, theirs, theirsVersion, mine); err != nil {
+			return err
+		}
+		return a.updateAfterConflict(draft, mine, mineEntry)
+	case conflictKeepTheirs:
+		fmt.Printf("Kept the version on disk. Your changes are in draft %s:\n", draft.ID)
+		fmt.Printf("run 'jrnlg drafts resume %s' to edit them again or 'jrnlg drafts discard %s' to drop them.\n",
+			draft.ID, draft.ID)
+		return errDraftKept
+	default: // conflictSaveNew
+		return a.saveEditAsNewEntry(mineEntry)
+	}
+}
+
+// mergeEditConflict three-way merges the user's edit with the version on disk.
+// The merge becomes the draft, an edit of the version on disk; the editor is opened on it
+// when both versions changed the same lines or the result isn't a valid entry, until it is.
+func (a *App) mergeEditConflict(draft *internal.Draft, mine, theirs string, originalTimestamp time.Time, theirsVersion internal.EntryVersion) error {
+	merged, conflicts := internal.MergeText(draft.Base, mine, theirs)
+
+	validate := func(entry *internal.JournalEntry) error {
+		if internal.HasConflictMarkers(entry.Body) {
+			return fmt.Errorf("unresolved conflict markers remain")
+		}
+		return validateTimestampUnchanged(originalTimestamp, entry.Timestamp)
+	}
+	mergedEntry, err := internal.ParseEntry(merged)
+	if err == nil {
+		err = validate(mergedEntry)
+	}
+
+	content := merged
+	if err != nil && !conflicts {
+		content = internal.AddDraftNotice(merged, err)
+	}
+	if err := a.rebaseDraft(draft, theirs, theirsVersion, content); err != nil {
 		return err
 	}
 
-	// Check if body is empty
-	if strings.TrimSpace(editedEntry.Body) == "" {
-		return fmt.Errorf("entry body cannot be empty")
+	if err != nil {
+		if conflicts {
+			fmt.Println("Both versions changed the same lines. Opening editor to resolve the conflict...")
+		} else {
+			_, _ = fmt.Fprintf(os.Stderr, "Invalid entry after merge: %v\nOpening editor...\n", err)
+		}
+		merged, mergedEntry, err = a.editDraft(draft, validate)
+		if err != nil {
+			return err
+		}
+		if mergedEntry == nil {
+			fmt.Printf("Merge abandoned. The version on disk was kept; your changes are in draft %s.\n", draft.ID)
+			return errDraftKept
+		}
 	}
 
-	// Update entry
-	if err := a.storage.UpdateEntry(filePath, editedEntry); err != nil {
-		return fmt.Errorf("failed to update entry: %w", err)
+	return a.updateAfterConflict(draft, merged, mergedEntry)
+}
+
+// rebaseDraft makes the draft an edit of the version on disk with the given content
+func (a *App) rebaseDraft(draft *internal.Draft, base string, version internal.EntryVersion, content string) error {
+	draft.Base, draft.BaseHash = base, version.Hash
+	if err := a.drafts().WriteContent(draft.ID, content); err != nil {
+		return fmt.Errorf("cannot update draft %s: %w", draft.ID, err)
+	}
+	return a.drafts().Update(draft)
+}
+
+// updateAfterConflict writes the resolved entry, re-entering conflict resolution
+// if the file changed yet again in the meantime
+func (a *App) updateAfterConflict(draft *internal.Draft, content string, entry *internal.JournalEntry) error {
+	expected := internal.EntryVersion{Hash: draft.BaseHash}
+	if err := a.storage.UpdateEntryIfUnchanged(draft.Target, entry, expected); err != nil {
+		if errors.Is(err, internal.ErrEntryModified) {
+			return a.resolveEditConflict(draft, content, entry)
+		}
+		return fmt.Errorf("failed to update entry: %w (draft kept: %s)", err, draft.ID)
 	}
 
-	// Success
 	fmt.Printf("Entry updated successfully.\n")
-	fmt.Printf("Timestamp: %s\n", editedEntry.Timestamp.Format("2006-01-02 3:04 PM"))
+	fmt.Printf("Timestamp: %s\n", entry.Timestamp.Format("2006-01-02 3:04 PM"))
+	return nil
+}
+
+// saveEditAsNewEntry stores the edited entry as a separate entry (collision suffix)
+func (a *App) saveEditAsNewEntry(entry *internal.JournalEntry) error {
+	if err := a.storage.SaveEntry(entry); err != nil {
+		return fmt.Errorf("failed to save entry: %w", err)
+	}
 
+	fmt.Printf("Your changes were saved as a new entry.\n")
+	fmt.Printf("Timestamp: %s\n", entry.Timestamp.Format("2006-01-02 3:04 PM"))
 	return nil
 }
 
@@ -4,27 +4,31 @@ import (
 	"errors"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 
 	"github.com/jashort/jrnlg/internal"
 )
 
 // OpenEditor opens an editor with the given initial content and returns the edited content
-// editorArgs are additional arguments to pass to the editor (e.g., ["+startinsert", "+call cursor(3,1)"])
-func OpenEditor(initialContent string, editorArgs []string) (string, error) {
-	// 1. Get editor command
-	editor := getEditorCommand()
+// The editor and its arguments (e.g., ["+startinsert", "+call cursor(3,1)"]) come from config
+// The content may be a decrypted entry, so the temp file lives in a private directory
+// (in memory where possible) that is overwritten and removed afterwards.
+func OpenEditor(initialContent string, config *internal.Config) (string, error) {
+	// 1. Create temp file in a private directory (the editor may add swap or backup files)
+	tmpDir, err := os.MkdirTemp(privateTempRoot(), "jrnlg-*")
+	if err != nil {
+		return "", err
+	}
+	defer secureRemoveAll(tmpDir)
 
-	// 2. Create temp file
-	tmpFile, err := os.CreateTemp("", "jrnlg-*"+internal.MarkdownExt)
+	tmpFile, err := os.CreateTemp(tmpDir, "jrnlg-*"+internal.MarkdownExt)
 	if err != nil {
 		return "", err
 	}
 	tmpPath := tmpFile.Name()
-	defer func(name string) {
-		_ = os.Remove(name)
-	}(tmpPath)
 
-	// 3. Write initial content
+	// 2. Write initial content
 	if _, err := tmpFile.WriteString(initialContent); err != nil {
 		err := errors.Join(err, tmpFile.Close())
 		return "", err
@@ -33,19 +37,12 @@ func OpenEditor(initialContent string, editorArgs []string) (string, error) {
 		return "", err
 	}
 
-	// 4. Launch editor with additional arguments
-	// Build args: editorArgs + tmpPath
-	args := append(editorArgs, tmpPath)
-	cmd := exec.Command(editor, args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
+	// 3. Launch editor and wait for it to exit
+	if err := EditFile(tmpPath, config); err != nil {
 		return "", err
 	}
 
-	// 5. Read edited content
+	// 4. Read edited content
 	content, err := os.ReadFile(tmpPath)
 	if err != nil {
 		return "", err
@@ -54,9 +51,58 @@ func OpenEditor(initialContent string, editorArgs []string) (string, error) {
 	return string(content), nil
 }
 
+// privateTempRoot returns where to put temp files holding entry text: a RAM-backed
+// directory if the system has one, otherwise the default temp directory
+func privateTempRoot() string {
+	if runtime.GOOS == "linux" {
+		if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+			return "/dev/shm"
+		}
+	}
+	return os.TempDir()
+}
+
+// secureRemoveAll overwrites every file in dir with zeros before removing the directory,
+// so decrypted text doesn't linger on disk
+func secureRemoveAll(dir string) {
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return nil
+		}
+		_, _ = file.Write(make([]byte, info.Size()))
+		_ = file.Sync()
+		_ = file.Close()
+		return nil
+	})
+	_ = os.RemoveAll(dir)
+}
+
+// EditFile opens an existing file in the editor and waits for the editor to exit
+// The configured editor arguments are passed to the editor before the file path
+func EditFile(path string, config *internal.Config) error {
+	editor := getEditorCommand(config.Editor)
+
+	// Build args: editorArgs + path
+	args := append(append([]string{}, config.EditorArgs...), path)
+	cmd := exec.Command(editor, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
 // getEditorCommand returns the editor to use
-// Priority: VISUAL > EDITOR > vim (with fallbacks to vi, nano)
-func getEditorCommand() string {
+// Priority: VISUAL > EDITOR > config file > vim (with fallbacks to vi, nano)
+func getEditorCommand(configured string) string {
 	// Try VISUAL environment variable first
 	if visual := os.Getenv("VISUAL"); visual != "" {
 		return visual
@@ -67,6 +113,10 @@ func getEditorCommand() string {
 		return editor
 	}
 
+	if configured != "" {
+		return configured
+	}
+
 	// Try fallbacks in order: vim → vi → nano
 	for _, cmd := range []string{"vim", "vi", "nano"} {
 		if _, err := exec.LookPath(cmd); err == nil {
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// readPassphrase returns the passphrase of an encrypted journal
+// Sources, in order: JRNLG_PASSPHRASE, the JRNLG_PASSPHRASE_COMMAND output, a terminal prompt
+func (a *App) readPassphrase() (string, error) {
+	if passphrase, ok, err := a.configuredPassphrase(); ok || err != nil {
+		return passphrase, err
+	}
+	return promptPassphrase("Passphrase: ")
+}
+
+// newPassphrase asks for the passphrase of a journal being encrypted, with confirmation
+func (a *App) newPassphrase() (string, error) {
+	if passphrase, ok, err := a.configuredPassphrase(); ok || err != nil {
+		return passphrase, err
+	}
+
+	passphrase, err := promptPassphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	confirm, err := promptPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != passphrase {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// configuredPassphrase returns the passphrase from the environment or the passphrase
+// command, if either is set
+func (a *App) configuredPassphrase() (string, bool, error) {
+	if passphrase := os.Getenv("JRNLG_PASSPHRASE"); passphrase != "" {
+		return passphrase, true, nil
+	}
+
+	if a.config.PassphraseCommand == "" {
+		return "", false, nil
+	}
+	cmd := exec.Command("sh", "-c", a.config.PassphraseCommand)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", true, fmt.Errorf("passphrase command failed: %w", err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), true, nil
+}
+
+// promptPassphrase reads a passphrase from the terminal without echoing it
+func promptPassphrase(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("%w: set JRNLG_PASSPHRASE or JRNLG_PASSPHRASE_COMMAND, or run in a terminal", internal.ErrPassphraseRequired)
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
+
+// executeEncrypt encrypts the journal in place, or finishes an interrupted encryption
+func (a *App) executeEncrypt() error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	var passphrase string
+	resuming := storage.IsEncrypted()
+	if resuming {
+		passphrase, err = a.readPassphrase()
+	} else {
+		passphrase, err = a.newPassphrase()
+	}
+	if err != nil {
+		return err
+	}
+
+	count, err := storage.Encrypt(passphrase)
+	a.commitChanges("encrypt journal")
+	if err != nil {
+		if count > 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "Encrypted %d %s before the error.\n", count, plural("entry", count))
+		}
+		return fmt.Errorf("%w\n\nRun 'jrnlg encrypt' again to resume", err)
+	}
+
+	if resuming && count == 0 {
+		fmt.Println("Journal is already encrypted.")
+		return nil
+	}
+
+	fmt.Printf("✓ Encrypted %d %s\n", count, plural("entry", count))
+	fmt.Println("Keep your passphrase safe: encrypted entries cannot be recovered without it.")
+	if internal.NewGitRepo(a.config.StoragePath).IsRepo() {
+		_, _ = fmt.Fprintln(os.Stderr, "Warning: earlier versions of entries in the git history are not encrypted.")
+	}
+	return nil
+}
+
+// executeDecrypt stores an encrypted journal as plain text again
+func (a *App) executeDecrypt(force bool) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	if !storage.IsEncrypted() {
+		return internal.ErrNotEncrypted
+	}
+
+	if !force {
+		fmt.Print("Decrypt the journal and store all entries as plain text? (y/N): ")
+		if !promptYes() {
+			fmt.Println("Canceled")
+			return nil
+		}
+	}
+
+	count, err := storage.Decrypt()
+	a.commitChanges("decrypt journal")
+	if err != nil {
+		if errors.Is(err, internal.ErrWrongPassphrase) {
+			return err
+		}
+		return fmt.Errorf("%w\n\nRun 'jrnlg decrypt' again to resume", err)
+	}
+
+	fmt.Printf("✓ Decrypted %d %s\n", count, plural("entry", count))
+	return nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+func TestEncryptedJournal_EditDraft(t *testing.T) {
+	t.Setenv("JRNLG_PASSPHRASE", "correct horse")
+	app, storage := newDraftTestApp(t)
+
+	if err := app.CreateEntryWithMessage("Before #encryption"); err != nil {
+		t.Fatalf("CreateEntryWithMessage() error = %v", err)
+	}
+	if err := app.executeEncrypt(); err != nil {
+		t.Fatalf("executeEncrypt() error = %v", err)
+	}
+
+	// Drafts are stored encrypted and edited in a decrypted temp copy
+	draft := &internal.Draft{}
+	if err := app.drafts().Create("## Monday 2026-02-09 2:30 PM UTC\n\nPrivate #notes", draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	raw, err := os.ReadFile(app.drafts().Path(draft.ID))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(raw), "Private") {
+		t.Errorf("draft stored as plain text:\n%s", raw)
+	}
+
+	_, entry, err := app.editDraft(draft, nil)
+	if err != nil {
+		t.Fatalf("editDraft() error = %v", err)
+	}
+	if entry == nil || entry.Body != "Private #notes" {
+		t.Errorf("editDraft() entry = %+v", entry)
+	}
+
+	entries, err := storage.ListEntries(internal.EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Body != "Before #encryption" {
+		t.Errorf("ListEntries() = %+v", entries)
+	}
+}
+
+func TestSecureRemoveAll(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "edit")
+	if err := os.MkdirAll(filepath.Join(dir, "swap"), 0700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	for _, name := range []string{"entry.md", filepath.Join("swap", ".entry.md.swp")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("secret"), 0600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	secureRemoveAll(dir)
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("directory still exists: %v", err)
+	}
+}
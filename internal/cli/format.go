@@ -12,18 +12,24 @@ import (
 
 // FormatEntries formats entries based on the specified format type
 func FormatEntries(entries []*internal.JournalEntry, format string, colorizer *color.Colorizer) string {
+	return FormatJournalEntries(entries, nil, format, colorizer)
+}
+
+// FormatJournalEntries formats entries from several journals, labeling each with the
+// journal at the same index in journals (no labels if journals is nil)
+func FormatJournalEntries(entries []*internal.JournalEntry, journals []string, format string, colorizer *color.Colorizer) string {
 	switch format {
 	case "summary":
-		return formatSummary(entries, colorizer)
+		return formatSummary(entries, journals, colorizer)
 	case "json":
-		return formatJSON(entries)
+		return formatJSON(entries, journals)
 	default: // "full"
-		return formatFull(entries, colorizer)
+		return formatFull(entries, journals, colorizer)
 	}
 }
 
 // formatFull displays complete entries with headers
-func formatFull(entries []*internal.JournalEntry, c *color.Colorizer) string {
+func formatFull(entries []*internal.JournalEntry, journals []string, c *color.Colorizer) string {
 	if len(entries) == 0 {
 		return "Found 0 entries.\n"
 	}
@@ -35,6 +41,9 @@ func formatFull(entries []*internal.JournalEntry, c *color.Colorizer) string {
 		// Write the entry header (timestamp with timezone)
 		sb.WriteString(c.Bold("## "))
 		sb.WriteString(c.Timestamp(internal.FormatTimestamp(entry.Timestamp)))
+		if journals != nil {
+			sb.WriteString(c.Dim(" [" + journals[i] + "]"))
+		}
 		sb.WriteString("\n\n")
 
 		// Write the body with colorized tags and mentions
@@ -55,7 +64,8 @@ func formatFull(entries []*internal.JournalEntry, c *color.Colorizer) string {
 
 // formatSummary displays one line per entry with timestamp and preview
 // Format: YYYY-MM-DD H:MM PM MST | First 80 chars of body...
-func formatSummary(entries []*internal.JournalEntry, c *color.Colorizer) string {
+// Entries from several journals have the journal before the preview
+func formatSummary(entries []*internal.JournalEntry, journals []string, c *color.Colorizer) string {
 	if len(entries) == 0 {
 		return "Found 0 entries.\n"
 	}
@@ -63,7 +73,7 @@ func formatSummary(entries []*internal.JournalEntry, c *color.Colorizer) string
 	var sb strings.Builder
 	sb.WriteString(c.Dim(fmt.Sprintf("Found %d entries:\n\n", len(entries))))
 
-	for _, entry := range entries {
+	for i, entry := range entries {
 		// Format timestamp with timezone abbreviation (MST format)
 		// Format: YYYY-MM-DD H:MM PM TZ
 		timestamp := c.Timestamp(entry.Timestamp.Format("2006-01-02 3:04 PM MST"))
@@ -77,7 +87,10 @@ func formatSummary(entries []*internal.JournalEntry, c *color.Colorizer) string
 		// Dim separator
 		separator := c.Dim(" | ")
 
-		// Write: timestamp | preview
+		// Write: timestamp | preview, or timestamp | journal | preview
+		if journals != nil {
+			separator += journals[i] + c.Dim(" | ")
+		}
 		sb.WriteString(fmt.Sprintf("%s%s%s\n", timestamp, separator, preview))
 	}
 
@@ -87,13 +100,14 @@ func formatSummary(entries []*internal.JournalEntry, c *color.Colorizer) string
 // jsonEntry is the JSON representation of a journal entry
 type jsonEntry struct {
 	Timestamp string   `json:"timestamp"`
+	Journal   string   `json:"journal,omitempty"`
 	Tags      []string `json:"tags"`
 	Mentions  []string `json:"mentions"`
 	Body      string   `json:"body"`
 }
 
 // formatJSON returns entries in JSON format
-func formatJSON(entries []*internal.JournalEntry) string {
+func formatJSON(entries []*internal.JournalEntry, journals []string) string {
 	// Convert to JSON-friendly format
 	jsonEntries := make([]jsonEntry, len(entries))
 	for i, entry := range entries {
@@ -103,6 +117,9 @@ func formatJSON(entries []*internal.JournalEntry) string {
 			Mentions:  entry.Mentions,
 			Body:      entry.Body,
 		}
+		if journals != nil {
+			jsonEntries[i].Journal = journals[i]
+		}
 	}
 
 	// Marshal with indentation for readability
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// commitChanges commits the files changed by the current command when git auto-commit
+// is enabled, creating the repository on first use. Failures are reported as warnings:
+// the entries themselves were saved.
+func (a *App) commitChanges(message string) {
+	tracker, ok := a.storage.(internal.ChangeTracker)
+	if !ok {
+		return
+	}
+	paths := tracker.TakeChanges()
+	if !a.config.GitAutoCommit || len(paths) == 0 {
+		return
+	}
+
+	repo := internal.NewGitRepo(a.config.StoragePath)
+	if !repo.IsRepo() {
+		if err := repo.Init(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to create git repository: %v\n", err)
+			return
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Created git repository in %s\n", a.config.StoragePath)
+	}
+
+	if _, err := repo.CommitFiles(message, paths); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: changes saved but not committed: %v\n", err)
+	}
+}
+
+// entryLabel identifies an entry in commit messages
+func entryLabel(timestamp time.Time) string {
+	return timestamp.Format(internal.FileTimestampFormat)
+}
+
+// showLog displays the change history of the journal
+func (a *App) showLog(limit int, files bool) error {
+	commits, err := internal.NewGitRepo(a.config.StoragePath).Log(limit)
+	if errors.Is(err, internal.ErrNotGitRepository) {
+		return fmt.Errorf("%w; set JRNLG_GIT_AUTOCOMMIT=true to version the journal", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(commits) == 0 {
+		fmt.Println("No history yet.")
+		return nil
+	}
+
+	colorizer := a.colorizer()
+	for _, commit := range commits {
+		fmt.Printf("%s  %s  %s\n",
+			colorizer.Dim(commit.Hash[:min(7, len(commit.Hash))]),
+			colorizer.Timestamp(commit.Date.Local().Format("2006-01-02 3:04 PM")),
+			commit.Subject,
+		)
+		if files {
+			for _, file := range commit.Files {
+				fmt.Printf("    %s\n", file)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+func TestCommitChanges_AutoCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	app, _ := newDraftTestApp(t)
+	app.config.GitAutoCommit = true
+	repo := internal.NewGitRepo(app.config.StoragePath)
+	if repo.IsRepo() {
+		t.Skip("temp directory is inside a git repository")
+	}
+
+	// The first commit creates the repository
+	if err := app.CreateEntryWithMessage("Deployed to #k8s"); err != nil {
+		t.Fatalf("CreateEntryWithMessage() error = %v", err)
+	}
+	if err := app.renameTags("k8s", "kubernetes", false, true); err != nil {
+		t.Fatalf("renameTags() error = %v", err)
+	}
+	entries, err := app.storage.ListEntries(internal.EntryFilter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("ListEntries() = %v, %v", entries, err)
+	}
+	if err := app.executeDelete("", nil, nil, true); err != nil {
+		t.Fatalf("executeDelete() error = %v", err)
+	}
+	// Nothing left to delete: no commit
+	if err := app.executeDelete("", nil, nil, true); err == nil {
+		t.Error("executeDelete() of an empty journal: expected error")
+	}
+
+	commits, err := repo.Log(0)
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("Log() = %d commits, want 3", len(commits))
+	}
+	if want := "delete entry " + entryLabel(entries[0].Timestamp); commits[0].Subject != want {
+		t.Errorf("delete commit subject = %q, want %q", commits[0].Subject, want)
+	}
+	if commits[1].Subject != "rename #k8s -> #kubernetes in 1 entry" {
+		t.Errorf("rename commit subject = %q", commits[1].Subject)
+	}
+	if len(commits[2].Files) != 2 { // The entry and the layout state file
+		t.Errorf("add commit files = %v", commits[2].Files)
+	}
+}
+
+func TestCommitChanges_Disabled(t *testing.T) {
+	app, storage := newDraftTestApp(t)
+
+	if err := app.CreateEntryWithMessage("Not versioned"); err != nil {
+		t.Fatalf("CreateEntryWithMessage() error = %v", err)
+	}
+
+	if internal.NewGitRepo(app.config.StoragePath).IsRepo() {
+		t.Error("no repository should be created when auto-commit is disabled")
+	}
+	// Changes are consumed either way
+	if changes := storage.TakeChanges(); !reflect.DeepEqual(changes, []string{}) {
+		t.Errorf("TakeChanges() = %v, want empty", changes)
+	}
+}
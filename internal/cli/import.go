@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// importParser reads the entries of an import source
+type importParser func(content []byte) ([]internal.ImportItem, error)
+
+// executeImport imports the entries of the file at path that aren't in the journal yet.
+// A path of "-" reads standard input.
+func (a *App) executeImport(path string, parse importParser, dryRun bool) error {
+	var content []byte
+	var err error
+	if path == "-" {
+		path = "stdin"
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	items, err := parse(content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return a.importItems(path, items, dryRun)
+}
+
+// executeImportMarkdown imports the dated notes of a directory
+func (a *App) executeImportMarkdown(dir string, options internal.MarkdownImportOptions, dryRun bool) error {
+	items, err := internal.ReadMarkdownNotes(dir, options)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no notes named like %s found in %s", strings.Join(options.Patterns, " or "), dir)
+	}
+	return a.importItems(dir, items, dryRun)
+}
+
+// executeImportGit logs the commits of a git repository that aren't in the journal yet
+func (a *App) executeImportGit(repo string, options internal.GitImportOptions, dryRun bool) error {
+	items, err := internal.ReadGitHistory(a.storage, repo, options)
+	if err != nil {
+		return err
+	}
+	return a.importItems(repo, items, dryRun)
+}
+
+// parseWindow parses the window of git work logs: "day", "week" or a duration like "4h"
+func parseWindow(s string) (time.Duration, error) {
+	switch s {
+	case "day":
+		return 24 * time.Hour, nil
+	case "week":
+		return internal.Week, nil
+	}
+	window, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q (use day, week or a duration like 4h)", s)
+	}
+	return window, nil
+}
+
+// importItems imports the entries read from source that aren't in the journal yet and
+// reports what was done with each
+func (a *App) importItems(source string, items []internal.ImportItem, dryRun bool) error {
+	result, err := internal.ImportEntries(a.storage, items, dryRun)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	imported := result.Count(internal.ImportNew)
+	if !dryRun {
+		a.commitChanges(fmt.Sprintf("import %d %s from %s", imported, plural("entry", imported), filepath.Base(source)))
+	}
+
+	for _, item := range result.Items {
+		switch {
+		case item.Action == internal.ImportFailed:
+			fmt.Printf("  failed     %s: %v\n", item.Source, item.Err)
+		case dryRun:
+			fmt.Printf("  %-10s %s  %s\n", item.Action, item.Entry.Timestamp.Format("2006-01-02 3:04 PM"),
+				TruncateBody(item.Entry.Body, 60))
+		}
+	}
+
+	// jrnlg has no attachments: list the files of the imported entries left behind
+	var attachments []string
+	for _, item := range result.Items {
+		if item.Action == internal.ImportNew {
+			attachments = append(attachments, item.Attachments...)
+		}
+	}
+	if len(attachments) > 0 {
+		fmt.Printf("Attachments not imported (%d %s):\n", len(attachments), plural("file", len(attachments)))
+		for _, attachment := range attachments {
+			fmt.Printf("  %s\n", attachment)
+		}
+	}
+
+	verb := "Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	duplicates, failed := result.Count(internal.ImportDuplicate), result.Count(internal.ImportFailed)
+	fmt.Printf("%s %d %s (%d already in the journal, %d failed)\n",
+		verb, imported, plural("entry", imported), duplicates, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d %s could not be imported", failed, plural("entry", failed))
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+func TestImportJSON_RoundTrip(t *testing.T) {
+	app := newJournalsApp(t)
+	export := captureStdout(t, func() error {
+		return app.executeSearch(SearchArgs{Tags: []string{"meeting"}, Format: "json"})
+	})
+	path := filepath.Join(t.TempDir(), "meetings.json")
+	if err := os.WriteFile(path, []byte(export), internal.FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	config, err := internal.LoadJournalConfig("personal")
+	if err != nil {
+		t.Fatalf("LoadJournalConfig() error = %v", err)
+	}
+	storage, err := internal.NewStorage(config)
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	personal := NewApp(storage, config)
+
+	output := captureStdout(t, func() error {
+		return personal.executeImport(path, internal.ParseJSONExport, false)
+	})
+	if !strings.Contains(output, "Imported 2 entries (0 already in the journal, 0 failed)") {
+		t.Errorf("import output =\n%s", output)
+	}
+	entries, err := storage.ListEntries(internal.EntryFilter{})
+	if err != nil || len(entries) != 3 || entries[0].Body != "Standup #meeting with @alice" {
+		t.Fatalf("imported entries = %v, %v", entries, err)
+	}
+
+	// The entries are found again by time and text
+	output = captureStdout(t, func() error {
+		return personal.executeImport(path, internal.ParseJSONExport, false)
+	})
+	if !strings.Contains(output, "Imported 0 entries (2 already in the journal, 0 failed)") {
+		t.Errorf("second import output =\n%s", output)
+	}
+}
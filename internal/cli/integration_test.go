@@ -318,3 +318,27 @@ Multiple paragraphs work too!`
 		t.Errorf("Expected mention 'mention', got %v", retrieved.Mentions)
 	}
 }
+
+// TestMemoryStorageIntegration runs commands against the in-memory backend
+func TestMemoryStorageIntegration(t *testing.T) {
+	config := &internal.Config{StoragePath: t.TempDir()}
+	storage := internal.NewMemoryStorage()
+	app := NewApp(storage, config)
+
+	if err := app.CreateEntryWithMessage("Deployed the #release with @bob"); err != nil {
+		t.Fatalf("CreateEntryWithMessage() error = %v", err)
+	}
+	if err := app.renameTags("release", "launch", false, true); err != nil {
+		t.Fatalf("renameTags() error = %v", err)
+	}
+
+	entries, err := storage.SearchByTags([]string{"launch"}, internal.EntryFilter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("SearchByTags() = %d entries, %v; want 1", len(entries), err)
+	}
+
+	// Commands that work on the storage directory need the filesystem backend
+	if err := app.executeDoctor(false); err == nil {
+		t.Error("executeDoctor() with memory storage: expected error")
+	}
+}
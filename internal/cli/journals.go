@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/jashort/jrnlg/internal"
+	"github.com/jashort/jrnlg/internal/cli/color"
+)
+
+// multiJournalCommands are the commands that can combine several journals
+var multiJournalCommands = []string{"search", "tags list", "mentions list", "stats"}
+
+// SelectedJournal returns the journal commands work on: the first one given with --journal
+func (c *CLI) SelectedJournal() string {
+	name, _, _ := strings.Cut(c.Journal, ",")
+	return strings.TrimSpace(name)
+}
+
+// journalNames returns the journals given with --journal
+func (c *CLI) journalNames() []string {
+	var names []string
+	for _, name := range strings.Split(c.Journal, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Validate checks the global flags before any command runs: --color must be a color
+// mode, and several journals are only given to commands that can combine them
+func (c *CLI) Validate(kctx *kong.Context) error {
+	if c.Color != "" {
+		if _, err := color.ParseMode(c.Color); err != nil {
+			return err
+		}
+	}
+	if len(c.journalNames()) < 2 {
+		return nil
+	}
+	command := kctx.Command()
+	for _, prefix := range multiJournalCommands {
+		if command == prefix || strings.HasPrefix(command, prefix+" ") {
+			return nil
+		}
+	}
+	return fmt.Errorf("several journals can only be given to %s", strings.Join(multiJournalCommands, ", "))
+}
+
+// journals returns the journals a command combines: every named journal with all, else
+// those given with --journal. It returns nil when the command works on the selected journal.
+func (ctx *Context) journals(all bool) ([]string, error) {
+	if !all {
+		if names := ctx.CLI.journalNames(); len(names) > 1 {
+			return names, nil
+		}
+		return nil, nil
+	}
+
+	if len(ctx.App.config.Journals) == 0 {
+		return nil, fmt.Errorf("no journals defined in %s", internal.ConfigPath())
+	}
+	return ctx.App.config.Journals, nil
+}
+
+// listJournals prints each named journal with its location, entry count and last entry date
+// A project journal in use is listed first
+func (a *App) listJournals() error {
+	if a.config.Project != "" {
+		printJournal("*", a.config.JournalLabel(), a.config, a.storage)
+	}
+	if len(a.config.Journals) == 0 {
+		if a.config.Project == "" {
+			fmt.Println("No journals defined.")
+			fmt.Println("Add one with: jrnlg config set journals.<name>.storage_path <directory>")
+		}
+		return nil
+	}
+
+	for _, name := range a.config.Journals {
+		marker, label := " ", name
+		if name == a.config.Journal {
+			marker = "*"
+		}
+		if name == a.config.DefaultJournal {
+			label += " (default)"
+		}
+
+		config, storage, err := a.openJournal(name)
+		if err != nil {
+			fmt.Printf("%s %s\n    Error: %v\n", marker, label, err)
+			continue
+		}
+		printJournal(marker, label, config, storage)
+	}
+	return nil
+}
+
+// printJournal prints a journal's location, entry count and last entry date
+func printJournal(marker, label string, config *internal.Config, storage internal.Storage) {
+	fmt.Printf("%s %s\n", marker, label)
+	fmt.Printf("    %s\n", config.StorageLocation())
+
+	summary, err := internal.SummarizeJournal(storage)
+	switch {
+	case err != nil:
+		fmt.Printf("    Error: %v\n", err)
+	case summary.Entries == 0:
+		fmt.Printf("    No entries\n")
+	default:
+		fmt.Printf("    %d %s, last entry %s\n", summary.Entries, plural("entry", summary.Entries),
+			summary.Latest.Local().Format("2006-01-02"))
+	}
+}
+
+// initProject creates a project journal in dir (the working directory if "")
+func (a *App) initProject(dir string) error {
+	if dir == "" {
+		dir = "."
+	}
+	path, err := internal.InitProjectJournal(dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Initialized project journal in %s\n", path)
+	fmt.Printf("Commands run in %s or below now use it, unless --journal is given.\n", filepath.Dir(path))
+	fmt.Printf("Commit %s to share the log, or add it to .gitignore to keep it private.\n", internal.ProjectDirName)
+	return nil
+}
+
+// openJournal returns the configuration and storage of a named journal
+// The selected journal's storage is already open
+func (a *App) openJournal(name string) (*internal.Config, internal.Storage, error) {
+	if name == a.config.Journal {
+		return a.config, a.storage, nil
+	}
+
+	config, err := internal.LoadJournalConfig(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	storage, err := internal.NewStorage(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fs, ok := storage.(*internal.FileSystemStorage); ok {
+		fs.SetPassphraseFunc(a.readPassphrase)
+	}
+	return config, storage, nil
+}
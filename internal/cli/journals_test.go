@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// newJournalsApp creates "work" and "personal" journals with a few entries each and
+// returns an app with "work" selected
+func newJournalsApp(t *testing.T) *App {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("JRNLG_JOURNAL", "")
+	t.Setenv("JRNLG_STORAGE_PATH", "")
+
+	entries := map[string][]*internal.JournalEntry{
+		"work": {
+			{Timestamp: time.Date(2026, 2, 9, 9, 0, 0, 0, time.UTC), Body: "Standup #meeting with @alice"},
+			{Timestamp: time.Date(2026, 2, 11, 9, 0, 0, 0, time.UTC), Body: "Planning #meeting"},
+		},
+		"personal": {
+			{Timestamp: time.Date(2026, 2, 10, 19, 0, 0, 0, time.UTC), Body: "Dinner with @alice #family"},
+		},
+	}
+	for name, journalEntries := range entries {
+		dir := t.TempDir()
+		if err := internal.SetConfigFileValue(internal.ConfigPath(), "journals."+name+".storage_path", dir); err != nil {
+			t.Fatalf("SetConfigFileValue() error = %v", err)
+		}
+		storage := internal.NewFileSystemStorage(dir, internal.DefaultConfig())
+		for _, entry := range journalEntries {
+			if err := storage.SaveEntry(entry); err != nil {
+				t.Fatalf("SaveEntry() error = %v", err)
+			}
+		}
+	}
+
+	config, err := internal.LoadJournalConfig("work")
+	if err != nil {
+		t.Fatalf("LoadJournalConfig() error = %v", err)
+	}
+	storage, err := internal.NewStorage(config)
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	return NewApp(storage, config)
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := fn()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	out, _ := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	return string(out)
+}
+
+func TestSearchJournals(t *testing.T) {
+	app := newJournalsApp(t)
+	journals := []string{"work", "personal"}
+
+	output := captureStdout(t, func() error {
+		return app.executeSearch(SearchArgs{Mentions: []string{"alice"}, Format: "json", Journals: journals})
+	})
+	var results []jsonEntry
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	if len(results) != 2 || results[0].Journal != "work" || results[1].Journal != "personal" {
+		t.Errorf("results = %+v, want work then personal entry", results)
+	}
+
+	// Limit and offset apply to the merged results
+	output = captureStdout(t, func() error {
+		return app.executeSearch(SearchArgs{Offset: 1, Limit: 1, Format: "summary", Journals: journals})
+	})
+	if !strings.Contains(output, "| personal | Dinner") || strings.Contains(output, "Standup") {
+		t.Errorf("summary output =\n%s", output)
+	}
+
+	output = captureStdout(t, func() error {
+		return app.executeSearch(SearchArgs{Format: "full", Journals: journals})
+	})
+	if strings.Count(output, "[work]") != 2 || strings.Count(output, "[personal]") != 1 {
+		t.Errorf("full output =\n%s", output)
+	}
+
+	// Without journals only the selected journal is searched, without labels
+	output = captureStdout(t, func() error {
+		return app.executeSearch(SearchArgs{Format: "summary"})
+	})
+	if !strings.Contains(output, "Found 2 entries") || strings.Contains(output, "| work |") {
+		t.Errorf("single journal output =\n%s", output)
+	}
+}
+
+func TestListTagsJournals(t *testing.T) {
+	app := newJournalsApp(t)
+
+	output := captureStdout(t, func() error {
+		return app.listMentions(false, []string{"work", "personal"})
+	})
+	if !strings.Contains(output, "@alice (2 entries)") {
+		t.Errorf("listMentions() output =\n%s", output)
+	}
+
+	output = captureStdout(t, func() error {
+		return app.listTags(true, []string{"work", "personal"})
+	})
+	if output != "#family (1 entry)\n" {
+		t.Errorf("listTags(orphaned) output =\n%s", output)
+	}
+}
+
+func TestStatsJournals(t *testing.T) {
+	app := newJournalsApp(t)
+
+	output := captureStdout(t, func() error {
+		return app.executeStats(&statsOptions{All: true, Format: "json", Journals: []string{"work", "personal"}})
+	})
+	var stats struct {
+		Summary struct {
+			TotalEntries int `json:"total_entries"`
+		}
+		Journals []string
+	}
+	if err := json.Unmarshal([]byte(output), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	if stats.Summary.TotalEntries != 3 || len(stats.Journals) != 2 {
+		t.Errorf("stats = %+v, want 3 entries from 2 journals", stats)
+	}
+}
+
+func TestCLIValidate_SeveralJournals(t *testing.T) {
+	tests := map[string]bool{
+		"search #meeting": true,
+		"tags":            true,
+		"mentions list":   true,
+		"stats --all":     true,
+		"add hello":       false,
+		"tags rename a b": false,
+	}
+	for command, ok := range tests {
+		var cli CLI
+		parser, err := kong.New(&cli, kong.Vars{"version": "test"})
+		if err != nil {
+			t.Fatalf("kong.New() error = %v", err)
+		}
+		_, err = parser.Parse(append([]string{"-j", "work,personal"}, strings.Fields(command)...))
+		if (err == nil) != ok {
+			t.Errorf("Parse(%q) error = %v, want ok = %v", command, err, ok)
+		}
+	}
+
+	var cli CLI
+	cli.Journal = " work , personal"
+	if cli.SelectedJournal() != "work" || len(cli.journalNames()) != 2 {
+		t.Errorf("SelectedJournal() = %q, journalNames() = %v", cli.SelectedJournal(), cli.journalNames())
+	}
+}
@@ -6,28 +6,50 @@ import (
 
 	"github.com/alecthomas/kong"
 
-	"github.com/jashort/jrnlg/internal/cli/color"
+	"github.com/jashort/jrnlg/internal"
 )
 
 // CLI defines the command-line interface structure
 type CLI struct {
 	// Global flags
-	Color   string           `enum:"auto,always,never" default:"auto" help:"Color mode"`
+	Color   string           `help:"Color mode: auto, always or never (default from config)"`
+	Journal string           `short:"j" help:"Named journal to use, or several separated by commas for search, tags, mentions and stats (default from config)"`
 	Version kong.VersionFlag `short:"v" help:"Show version"`
 
 	// Commands
-	Add      AddCmd      `cmd:"" aliases:"create" help:"Add new journal entry"`
-	Search   SearchCmd   `cmd:"" aliases:"list" help:"Search journal entries"`
-	Edit     EditCmd     `cmd:"" help:"Edit an entry"`
-	Delete   DeleteCmd   `cmd:"" aliases:"rm" help:"Delete entries"`
-	Tags     TagsCmd     `cmd:"" help:"Manage tags"`
-	Mentions MentionsCmd `cmd:"" help:"Manage mentions"`
-	Stats    StatsCmd    `cmd:"" help:"Show journal statistics"`
+	Add       AddCmd       `cmd:"" aliases:"create" help:"Add new journal entry"`
+	Search    SearchCmd    `cmd:"" aliases:"list" help:"Search journal entries"`
+	Edit      EditCmd      `cmd:"" help:"Edit an entry"`
+	Delete    DeleteCmd    `cmd:"" aliases:"rm" help:"Delete entries"`
+	Tags      TagsCmd      `cmd:"" help:"Manage tags"`
+	Mentions  MentionsCmd  `cmd:"" help:"Manage mentions"`
+	Stats     StatsCmd     `cmd:"" help:"Show journal statistics"`
+	Drafts    DraftsCmd    `cmd:"" help:"Manage unsaved editor drafts"`
+	Doctor    DoctorCmd    `cmd:"" storage:"directory" help:"Check journal storage for problems"`
+	Log       LogCmd       `cmd:"" help:"Show the journal's change history (git)"`
+	Sync      SyncCmd      `cmd:"" storage:"directory" help:"Sync the journal with a git remote"`
+	Conflicts ConflictsCmd `cmd:"" storage:"directory" help:"Resolve conflict copies left by file sync tools"`
+	Encrypt   EncryptCmd   `cmd:"" storage:"directory" help:"Encrypt the journal with a passphrase"`
+	Decrypt   DecryptCmd   `cmd:"" storage:"directory" help:"Store an encrypted journal as plain text again"`
+	Sign      SignCmd      `cmd:"" storage:"directory" help:"Sign entries in a tamper-evident hash chain"`
+	Verify    VerifyCmd    `cmd:"" storage:"directory" help:"Check entries against their signatures"`
+	Backup    BackupCmd    `cmd:"" storage:"directory" help:"Save a backup archive of the journal"`
+	Restore   RestoreCmd   `cmd:"" storage:"directory" help:"Restore entries from a backup archive"`
+	Archive   ArchiveCmd   `cmd:"" storage:"directory" help:"Pack a past year's entries into a read-only archive"`
+	Unarchive UnarchiveCmd `cmd:"" storage:"directory" help:"Expand an archived year so its entries can be edited"`
+	Config    ConfigCmd    `cmd:"" help:"Show and change settings"`
+	Journals  JournalsCmd  `cmd:"" help:"Manage named journals"`
+	Init      InitCmd      `cmd:"" help:"Create a project journal in the current directory"`
+	Import    ImportCmd    `cmd:"" help:"Import entries from other journal apps"`
+
+	MigrateLayout MigrateLayoutCmd `cmd:"" name:"migrate-layout" storage:"directory" help:"Move entry files to a different storage layout"`
+	SyncDir       SyncDirCmd       `cmd:"" name:"sync-dir" storage:"directory" help:"Sync the journal with a copy in another directory"`
 }
 
 // AddCmd creates a new journal entry
 type AddCmd struct {
-	Message []string `arg:"" optional:"" help:"Entry message (opens editor if not provided)"`
+	Message  []string `arg:"" optional:"" help:"Entry message (opens editor if not provided)"`
+	Template string   `short:"t" help:"Start the entry from a template in the config file"`
 }
 
 // SearchCmd searches journal entries
@@ -39,7 +61,9 @@ type SearchCmd struct {
 	Offset  int          `help:"Skip first N results"`
 	Reverse bool         `short:"r" help:"Show newest entries first"`
 	Summary bool         `help:"Show compact summary format"`
-	Format  string       `enum:"full,summary,json" default:"full" help:"Output format"`
+	Format  string       `help:"Output format: full, summary or json (default from config)"`
+
+	AllJournals bool `help:"Search every named journal"`
 }
 
 // EditCmd edits an entry
@@ -57,13 +81,14 @@ type DeleteCmd struct {
 
 // TagsCmd manages tags
 type TagsCmd struct {
-	List   TagsListCmd   `cmd:"" default:"1" help:"List all tags"`
+	List   TagsListCmd   `cmd:"" default:"withargs" help:"List all tags"`
 	Rename TagsRenameCmd `cmd:"" help:"Rename a tag"`
 }
 
 // TagsListCmd lists all tags
 type TagsListCmd struct {
-	Orphaned bool `help:"Show only tags used once"`
+	Orphaned    bool `help:"Show only tags used once"`
+	AllJournals bool `help:"Combine the tags of every named journal"`
 }
 
 // TagsRenameCmd renames a tag
@@ -76,13 +101,14 @@ type TagsRenameCmd struct {
 
 // MentionsCmd manages mentions
 type MentionsCmd struct {
-	List   MentionsListCmd   `cmd:"" default:"1" help:"List all mentions"`
+	List   MentionsListCmd   `cmd:"" default:"withargs" help:"List all mentions"`
 	Rename MentionsRenameCmd `cmd:"" help:"Rename a mention"`
 }
 
 // MentionsListCmd lists all mentions
 type MentionsListCmd struct {
-	Orphaned bool `help:"Show only mentions used once"`
+	Orphaned    bool `help:"Show only mentions used once"`
+	AllJournals bool `help:"Combine the mentions of every named journal"`
 }
 
 // MentionsRenameCmd renames a mention
@@ -102,6 +128,209 @@ type StatsCmd struct {
 	Mention  string       `help:"Filter by mention" xor:"filter"`
 	Format   string       `enum:"default,json,detailed" default:"default" help:"Output format"`
 	Detailed bool         `help:"Show detailed breakdown"`
+
+	AllJournals bool `help:"Combine the statistics of every named journal"`
+}
+
+// DraftsCmd manages drafts left by invalid entries or interrupted editor sessions
+type DraftsCmd struct {
+	List    DraftsListCmd    `cmd:"" default:"1" help:"List unsaved drafts"`
+	Resume  DraftsResumeCmd  `cmd:"" help:"Reopen a draft in the editor and save it"`
+	Discard DraftsDiscardCmd `cmd:"" help:"Delete drafts"`
+}
+
+// DraftsListCmd lists all drafts
+type DraftsListCmd struct{}
+
+// DraftsResumeCmd resumes editing a draft
+type DraftsResumeCmd struct {
+	ID string `arg:"" optional:"" help:"Draft ID (default: most recent draft)"`
+}
+
+// DraftsDiscardCmd deletes drafts
+type DraftsDiscardCmd struct {
+	ID    string `arg:"" optional:"" help:"Draft ID"`
+	All   bool   `help:"Discard all drafts"`
+	Force bool   `short:"f" help:"Skip confirmation"`
+}
+
+// DoctorCmd checks storage integrity
+type DoctorCmd struct {
+	Fix bool `help:"Repair problems that can be fixed safely"`
+}
+
+// MigrateLayoutCmd moves entry files to a different storage layout
+type MigrateLayoutCmd struct {
+	Layout string `arg:"" enum:"year-month,year-month-day,year-week,flat,slug" help:"Target layout (year-month, year-month-day, year-week, flat, slug)"`
+	DryRun bool   `help:"Preview changes without applying"`
+	Force  bool   `short:"f" help:"Skip confirmation"`
+}
+
+// LogCmd shows the journal's git history
+type LogCmd struct {
+	Limit int  `short:"n" default:"20" help:"Limit number of commits (0 for all)"`
+	Files bool `help:"List the files changed by each commit"`
+}
+
+// SyncCmd syncs the journal with a git remote
+type SyncCmd struct {
+	Remote string `default:"origin" help:"Git remote to pull from and push to"`
+}
+
+// SyncDirCmd syncs the journal with a copy in another directory
+type SyncDirCmd struct {
+	Path string `arg:"" type:"path" help:"Storage directory of the other copy (e.g. on a USB stick)"`
+}
+
+// ConflictsCmd resolves conflict copies left by file sync tools
+type ConflictsCmd struct {
+	List bool `help:"Only list conflict copies"`
+}
+
+// EncryptCmd encrypts the journal in place
+type EncryptCmd struct{}
+
+// DecryptCmd decrypts the journal in place
+type DecryptCmd struct {
+	Force bool `short:"f" help:"Skip confirmation prompt"`
+}
+
+// SignCmd starts or extends the signed hash chain
+type SignCmd struct{}
+
+// VerifyCmd verifies the signed hash chain
+type VerifyCmd struct{}
+
+// BackupCmd writes a backup archive
+type BackupCmd struct {
+	File string `arg:"" type:"path" help:"Archive to write (.tar.gz), or a directory with --keep"`
+	Keep int    `help:"Add a timestamp to the name and keep only the newest N backups"`
+}
+
+// RestoreCmd restores a backup archive
+type RestoreCmd struct {
+	Archive string `arg:"" type:"existingfile" help:"Backup archive to restore"`
+	DryRun  bool   `help:"Show what would be restored without changing anything"`
+}
+
+// ArchiveCmd archives a year, or lists archived years
+type ArchiveCmd struct {
+	Year int `arg:"" optional:"" help:"Year to archive (lists archived years if omitted)"`
+}
+
+// UnarchiveCmd expands an archived year
+type UnarchiveCmd struct {
+	Year int `arg:"" help:"Year to unarchive"`
+}
+
+// ConfigCmd shows and changes settings
+type ConfigCmd struct {
+	List ConfigListCmd `cmd:"" default:"1" help:"List all settings and where their values come from"`
+	Get  ConfigGetCmd  `cmd:"" help:"Show the value of a setting"`
+	Set  ConfigSetCmd  `cmd:"" help:"Change a setting in the config file"`
+	Path ConfigPathCmd `cmd:"" help:"Show the path of the config file"`
+}
+
+// ConfigListCmd lists all settings
+type ConfigListCmd struct{}
+
+// ConfigGetCmd shows a setting
+type ConfigGetCmd struct {
+	Key string `arg:"" help:"Setting (e.g. storage_path, templates.standup)"`
+}
+
+// ConfigSetCmd changes a setting in the config file
+type ConfigSetCmd struct {
+	Key   string `arg:"" help:"Setting (e.g. storage_path, templates.standup)"`
+	Value string `arg:"" help:"New value"`
+}
+
+// ConfigPathCmd shows the path of the config file
+type ConfigPathCmd struct{}
+
+// JournalsCmd manages named journals
+type JournalsCmd struct {
+	List JournalsListCmd `cmd:"" default:"1" help:"List named journals"`
+}
+
+// JournalsListCmd lists named journals
+type JournalsListCmd struct{}
+
+// InitCmd creates a project journal
+type InitCmd struct {
+	Dir string `arg:"" optional:"" help:"Directory to create the journal in (default: current directory)"`
+}
+
+// ImportCmd imports entries from other journal apps
+type ImportCmd struct {
+	Jrnl     ImportJrnlCmd     `cmd:"" help:"Import a jrnl journal or export (plain text or JSON)"`
+	Dayone   ImportDayOneCmd   `cmd:"" name:"dayone" help:"Import a Day One JSON export (zip archive or journal JSON file)"`
+	Markdown ImportMarkdownCmd `cmd:"" help:"Import a directory of dated Markdown notes (e.g. Obsidian daily notes)"`
+	Csv      ImportCSVCmd      `cmd:"" name:"csv" help:"Import CSV rows, with a header row naming the columns"`
+	Jsonl    ImportJSONLCmd    `cmd:"" name:"jsonl" help:"Import JSON Lines: a JSON object per line"`
+	JSON     ImportJSONCmd     `cmd:"" name:"json" help:"Import entries written by jrnlg search --format json"`
+	Git      ImportGitCmd      `cmd:"" help:"Write work log entries of the commits of a git repository"`
+}
+
+// ImportJrnlCmd imports a jrnl journal
+type ImportJrnlCmd struct {
+	File   string `arg:"" type:"existingfile" help:"jrnl journal file, or an export from jrnl --format json"`
+	DryRun bool   `help:"Show what would be imported without saving anything"`
+}
+
+// ImportDayOneCmd imports a Day One export
+type ImportDayOneCmd struct {
+	File   string `arg:"" type:"existingfile" help:"Day One export archive (.zip) or journal JSON file"`
+	DryRun bool   `help:"Show what would be imported without saving anything"`
+}
+
+// ImportMarkdownCmd imports a directory of dated Markdown notes
+type ImportMarkdownCmd struct {
+	Dir     string   `arg:"" type:"existingdir" help:"Directory of notes, searched with its subdirectories"`
+	Pattern []string `default:"2006-01-02" sep:"none" help:"Date of note file names without .md, as a Go time layout (e.g. 2006-01-02, \"Jan 2, 2006\"); can be repeated"`
+	Time    string   `default:"9:00" help:"Time of notes and sections that don't give one"`
+	Split   int      `placeholder:"LEVEL" help:"Split notes into entries at headings of this level (e.g. 2 for ##); headings can start with a time"`
+	DryRun  bool     `help:"Show what would be imported without saving anything"`
+}
+
+// ImportCSVCmd imports CSV rows
+type ImportCSVCmd struct {
+	File       string   `arg:"" type:"existingfile" help:"CSV file"`
+	Timestamp  string   `default:"timestamp" help:"Column of the entry time"`
+	Timezone   string   `help:"Column of the time zone (e.g. Europe/Paris); local time without it"`
+	Body       string   `default:"body" help:"Column of the entry text"`
+	Tags       string   `help:"Column of tags, separated by commas or spaces"`
+	TimeFormat string   `help:"Go time layout of the timestamps (default: RFC 3339, YYYY-MM-DD HH:MM[:SS], YYYY-MM-DD or Unix seconds)"`
+	Tag        []string `help:"Tag to add to every entry; can be repeated"`
+	DryRun     bool     `help:"Show what would be imported without saving anything"`
+}
+
+// ImportJSONLCmd imports JSON Lines
+type ImportJSONLCmd struct {
+	File       string   `arg:"" type:"existingfile" help:"JSON Lines file"`
+	Timestamp  string   `default:"timestamp" help:"Field of the entry time"`
+	Timezone   string   `help:"Field of the time zone (e.g. Europe/Paris); local time without it"`
+	Body       string   `default:"body" help:"Field of the entry text"`
+	Tags       string   `help:"Field of tags: a list, or text separated by commas or spaces"`
+	TimeFormat string   `help:"Go time layout of the timestamps (default: RFC 3339, YYYY-MM-DD HH:MM[:SS], YYYY-MM-DD or Unix seconds)"`
+	Tag        []string `help:"Tag to add to every entry; can be repeated"`
+	DryRun     bool     `help:"Show what would be imported without saving anything"`
+}
+
+// ImportJSONCmd imports jrnlg's own JSON output
+type ImportJSONCmd struct {
+	File   string `arg:"" type:"existingfile" help:"JSON file from jrnlg search --format json, or - for standard input"`
+	DryRun bool   `help:"Show what would be imported without saving anything"`
+}
+
+// ImportGitCmd writes work log entries of a git repository's history
+type ImportGitCmd struct {
+	Repo   string       `arg:"" type:"existingdir" help:"Git repository"`
+	Author string       `default:"me" help:"Author name or email, or part of one; me for your git user.email, or \"\" for everyone"`
+	Since  *NaturalDate `help:"Log commits from this date (e.g. yesterday, 2024-01-01)"`
+	Until  *NaturalDate `help:"Log commits up to this date"`
+	Window string       `default:"day" help:"Commits grouped into an entry: day, week or a part of a day like 4h"`
+	DryRun bool         `help:"Show what would be imported without saving anything"`
 }
 
 // Run implementations for each command
@@ -112,18 +341,21 @@ func (c *AddCmd) Run(ctx *Context) error {
 		msg := strings.Join(c.Message, " ")
 		// If the message is only whitespace, open editor instead
 		if strings.TrimSpace(msg) == "" {
-			return ctx.App.CreateEntry()
+			return ctx.App.CreateEntry(c.Template)
+		}
+		if c.Template != "" {
+			return fmt.Errorf("--template only applies when writing the entry in the editor")
 		}
 		return ctx.App.CreateEntryWithMessage(msg)
 	}
-	return ctx.App.CreateEntry()
+	return ctx.App.CreateEntry(c.Template)
 }
 
 func (c *SearchCmd) Run(ctx *Context) error {
 	// Convert Kong struct to SearchArgs
-	colorMode, err := color.ParseMode(ctx.CLI.Color)
-	if err != nil {
-		return err
+	format := orDefault(c.Format, ctx.App.config.Format)
+	if format != "full" && format != "summary" && format != "json" {
+		return fmt.Errorf("invalid format %q: must be one of full, summary, json", format)
 	}
 
 	args := SearchArgs{
@@ -134,9 +366,13 @@ func (c *SearchCmd) Run(ctx *Context) error {
 		ToDate:    c.To.Ptr(),
 		Limit:     c.Limit,
 		Offset:    c.Offset,
-		Format:    c.Format,
+		Format:    format,
 		Reverse:   c.Reverse,
-		ColorMode: colorMode,
+		ColorMode: ctx.App.color,
+	}
+	var err error
+	if args.Journals, err = ctx.journals(c.AllJournals); err != nil {
+		return err
 	}
 
 	if c.Summary {
@@ -166,7 +402,11 @@ func (c *DeleteCmd) Run(ctx *Context) error {
 }
 
 func (c *TagsListCmd) Run(ctx *Context) error {
-	return ctx.App.listTags(c.Orphaned)
+	journals, err := ctx.journals(c.AllJournals)
+	if err != nil {
+		return err
+	}
+	return ctx.App.listTags(c.Orphaned, journals)
 }
 
 func (c *TagsRenameCmd) Run(ctx *Context) error {
@@ -174,7 +414,11 @@ func (c *TagsRenameCmd) Run(ctx *Context) error {
 }
 
 func (c *MentionsListCmd) Run(ctx *Context) error {
-	return ctx.App.listMentions(c.Orphaned)
+	journals, err := ctx.journals(c.AllJournals)
+	if err != nil {
+		return err
+	}
+	return ctx.App.listMentions(c.Orphaned, journals)
 }
 
 func (c *MentionsRenameCmd) Run(ctx *Context) error {
@@ -196,6 +440,11 @@ func (c *StatsCmd) Run(ctx *Context) error {
 		return fmt.Errorf("cannot use --all with --to")
 	}
 
+	journals, err := ctx.journals(c.AllJournals)
+	if err != nil {
+		return err
+	}
+
 	opts := &statsOptions{
 		All:      c.All,
 		FromDate: c.From.Ptr(),
@@ -204,11 +453,157 @@ func (c *StatsCmd) Run(ctx *Context) error {
 		Mention:  strings.ToLower(strings.TrimPrefix(c.Mention, "@")),
 		Format:   format,
 		Detailed: c.Detailed,
+		Journals: journals,
 	}
 
 	return ctx.App.executeStats(opts)
 }
 
+func (c *DraftsListCmd) Run(ctx *Context) error {
+	return ctx.App.listDrafts()
+}
+
+func (c *DraftsResumeCmd) Run(ctx *Context) error {
+	return ctx.App.resumeDraft(c.ID)
+}
+
+func (c *DraftsDiscardCmd) Run(ctx *Context) error {
+	return ctx.App.discardDrafts(c.ID, c.All, c.Force)
+}
+
+func (c *DoctorCmd) Run(ctx *Context) error {
+	return ctx.App.executeDoctor(c.Fix)
+}
+
+func (c *MigrateLayoutCmd) Run(ctx *Context) error {
+	return ctx.App.executeMigrateLayout(c.Layout, c.DryRun, c.Force)
+}
+
+func (c *LogCmd) Run(ctx *Context) error {
+	return ctx.App.showLog(c.Limit, c.Files)
+}
+
+func (c *SyncCmd) Run(ctx *Context) error {
+	return ctx.App.executeSync(c.Remote)
+}
+
+func (c *ConflictsCmd) Run(ctx *Context) error {
+	return ctx.App.executeConflicts(c.List)
+}
+
+func (c *EncryptCmd) Run(ctx *Context) error {
+	return ctx.App.executeEncrypt()
+}
+
+func (c *DecryptCmd) Run(ctx *Context) error {
+	return ctx.App.executeDecrypt(c.Force)
+}
+
+func (c *SignCmd) Run(ctx *Context) error {
+	return ctx.App.executeSign()
+}
+
+func (c *VerifyCmd) Run(ctx *Context) error {
+	return ctx.App.executeVerify()
+}
+
+func (c *BackupCmd) Run(ctx *Context) error {
+	return ctx.App.executeBackup(c.File, c.Keep)
+}
+
+func (c *RestoreCmd) Run(ctx *Context) error {
+	return ctx.App.executeRestore(c.Archive, c.DryRun)
+}
+
+func (c *ArchiveCmd) Run(ctx *Context) error {
+	return ctx.App.executeArchive(c.Year)
+}
+
+func (c *UnarchiveCmd) Run(ctx *Context) error {
+	return ctx.App.executeUnarchive(c.Year)
+}
+
+func (c *ConfigListCmd) Run(ctx *Context) error {
+	return ctx.App.listConfig()
+}
+
+func (c *ConfigGetCmd) Run(ctx *Context) error {
+	return ctx.App.getConfig(c.Key)
+}
+
+func (c *ConfigSetCmd) Run(ctx *Context) error {
+	return ctx.App.setConfig(c.Key, c.Value)
+}
+
+func (c *ConfigPathCmd) Run(ctx *Context) error {
+	return ctx.App.showConfigPath()
+}
+
+func (c *JournalsListCmd) Run(ctx *Context) error {
+	return ctx.App.listJournals()
+}
+
+func (c *InitCmd) Run(ctx *Context) error {
+	return ctx.App.initProject(c.Dir)
+}
+
+func (c *ImportJrnlCmd) Run(ctx *Context) error {
+	return ctx.App.executeImport(c.File, internal.ParseJrnlExport, c.DryRun)
+}
+
+func (c *ImportDayOneCmd) Run(ctx *Context) error {
+	return ctx.App.executeImport(c.File, internal.ParseDayOneExport, c.DryRun)
+}
+
+func (c *ImportMarkdownCmd) Run(ctx *Context) error {
+	clock, err := internal.ParseClockTime(c.Time)
+	if err != nil {
+		return err
+	}
+	if c.Split < 0 || c.Split > 6 {
+		return fmt.Errorf("--split must be a heading level from 1 to 6")
+	}
+	options := internal.MarkdownImportOptions{Patterns: c.Pattern, Time: clock, SplitLevel: c.Split}
+	return ctx.App.executeImportMarkdown(c.Dir, options, c.DryRun)
+}
+
+func (c *ImportCSVCmd) Run(ctx *Context) error {
+	options := internal.RecordImportOptions{
+		TimestampField: c.Timestamp, TimezoneField: c.Timezone, BodyField: c.Body,
+		TagsField: c.Tags, TimeFormat: c.TimeFormat, Tags: c.Tag,
+	}
+	return ctx.App.executeImport(c.File, func(content []byte) ([]internal.ImportItem, error) {
+		return internal.ParseCSVRecords(content, options)
+	}, c.DryRun)
+}
+
+func (c *ImportJSONLCmd) Run(ctx *Context) error {
+	options := internal.RecordImportOptions{
+		TimestampField: c.Timestamp, TimezoneField: c.Timezone, BodyField: c.Body,
+		TagsField: c.Tags, TimeFormat: c.TimeFormat, Tags: c.Tag,
+	}
+	return ctx.App.executeImport(c.File, func(content []byte) ([]internal.ImportItem, error) {
+		return internal.ParseJSONLRecords(content, options)
+	}, c.DryRun)
+}
+
+func (c *ImportJSONCmd) Run(ctx *Context) error {
+	return ctx.App.executeImport(c.File, internal.ParseJSONExport, c.DryRun)
+}
+
+func (c *ImportGitCmd) Run(ctx *Context) error {
+	window, err := parseWindow(c.Window)
+	if err != nil {
+		return err
+	}
+	options := internal.GitImportOptions{Author: c.Author, Since: c.Since.Ptr(), Until: c.Until.Ptr(), Window: window}
+	return ctx.App.executeImportGit(c.Repo, options, c.DryRun)
+}
+
+func (c *SyncDirCmd) Run(ctx *Context) error {
+	return ctx.App.executeSyncDir(c.Path)
+}
+
 // Context provides access to CLI and App for command execution
 type Context struct {
 	CLI *CLI
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// executeMigrateLayout moves all entry files to a new storage layout
+func (a *App) executeMigrateLayout(layoutName string, dryRun, force bool) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	target, err := internal.ParseLayout(layoutName)
+	if err != nil {
+		return err
+	}
+
+	// Always plan first, so the user can confirm what will happen
+	plan, err := storage.MigrateLayout(target, true)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Moves) == 0 && plan.From == target {
+		fmt.Printf("Journal already uses the %s layout.\n", target)
+		if len(plan.Unparsable) == 0 {
+			return nil
+		}
+	}
+
+	if dryRun {
+		for _, move := range plan.Moves {
+			fmt.Printf("%s -> %s\n", a.displayPath(move.From), a.displayPath(move.To))
+		}
+		a.printMigrationSummary(plan, true)
+		return nil
+	}
+
+	if !force && len(plan.Moves) > 0 {
+		fmt.Printf("Move %d %s from the %s layout to the %s layout? (y/N): ",
+			len(plan.Moves), plural("file", len(plan.Moves)), plan.From, target)
+		if !promptYes() {
+			fmt.Println("Canceled")
+			return nil
+		}
+	}
+
+	result, err := storage.MigrateLayout(target, false)
+	a.commitChanges(fmt.Sprintf("migrate layout %s -> %s", plan.From, target))
+	if err != nil {
+		if result != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Moved %d %s before the error.\n", len(result.Moves), plural("file", len(result.Moves)))
+		}
+		return fmt.Errorf("%w\n\nRun 'jrnlg migrate-layout %s' again to resume", err, target)
+	}
+
+	a.printMigrationSummary(result, false)
+	return nil
+}
+
+// printMigrationSummary reports the outcome of a migration (or its plan)
+func (a *App) printMigrationSummary(result *internal.MigrationResult, dryRun bool) {
+	verb := "Moved"
+	if dryRun {
+		verb = "Would move"
+	}
+
+	fmt.Printf("%s %d %s to the %s layout (%d already in place).\n",
+		verb, len(result.Moves), plural("file", len(result.Moves)), result.To, result.InPlace)
+
+	if len(result.Unparsable) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: left %d unparsable %s in place (see 'jrnlg doctor'):\n",
+			len(result.Unparsable), plural("file", len(result.Unparsable)))
+		for _, path := range result.Unparsable {
+			_, _ = fmt.Fprintf(os.Stderr, "  %s\n", a.displayPath(path))
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/jashort/jrnlg/internal"
@@ -10,6 +11,81 @@ import (
 
 // executeSearch performs the actual search logic
 func (a *App) executeSearch(searchArgs SearchArgs) error {
+	if len(searchArgs.Journals) > 0 {
+		return a.executeJournalsSearch(searchArgs)
+	}
+
+	finalResults, err := searchStorage(a.storage, searchArgs)
+	if err != nil {
+		return err
+	}
+
+	// Apply reverse sort if requested (newest first)
+	if searchArgs.Reverse {
+		sort.Slice(finalResults, func(i, j int) bool {
+			return finalResults[i].Timestamp.After(finalResults[j].Timestamp)
+		})
+	}
+
+	// Create colorizer based on color mode
+	colorizer := color.New(searchArgs.ColorMode)
+
+	// Format and display results
+	output := FormatEntries(finalResults, searchArgs.Format, colorizer)
+	fmt.Print(output)
+
+	return nil
+}
+
+// executeJournalsSearch searches several journals, merging their results by timestamp
+// Limit and offset apply to the merged results
+func (a *App) executeJournalsSearch(searchArgs SearchArgs) error {
+	perJournal := searchArgs
+	perJournal.Limit, perJournal.Offset = 0, 0
+
+	var found []journalEntry
+	for _, name := range searchArgs.Journals {
+		_, storage, err := a.openJournal(name)
+		if err != nil {
+			return fmt.Errorf("journal %s: %w", name, err)
+		}
+		results, err := searchStorage(storage, perJournal)
+		if err != nil {
+			return fmt.Errorf("journal %s: %w", name, err)
+		}
+		for _, entry := range results {
+			found = append(found, journalEntry{journal: name, entry: entry})
+		}
+	}
+
+	// Oldest first, keeping the order of the journals for entries at the same time
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].entry.Timestamp.Before(found[j].entry.Timestamp)
+	})
+	start := min(searchArgs.Offset, len(found))
+	end := len(found)
+	if searchArgs.Limit > 0 {
+		end = min(start+searchArgs.Limit, end)
+	}
+	found = found[start:end]
+
+	if searchArgs.Reverse {
+		slices.Reverse(found)
+	}
+
+	entries := make([]*internal.JournalEntry, len(found))
+	journals := make([]string, len(found))
+	for i, result := range found {
+		entries[i], journals[i] = result.entry, result.journal
+	}
+
+	colorizer := color.New(searchArgs.ColorMode)
+	fmt.Print(FormatJournalEntries(entries, journals, searchArgs.Format, colorizer))
+	return nil
+}
+
+// searchStorage returns the entries of one journal matching the search terms, oldest first
+func searchStorage(storage internal.Storage, searchArgs SearchArgs) ([]*internal.JournalEntry, error) {
 	// Build entry filter for date ranges and pagination
 	filter := internal.EntryFilter{
 		Limit:  searchArgs.Limit,
@@ -28,59 +104,48 @@ func (a *App) executeSearch(searchArgs SearchArgs) error {
 
 	// Search by tags
 	if len(searchArgs.Tags) > 0 {
-		results, err := a.storage.SearchByTags(searchArgs.Tags, filter)
+		results, err := storage.SearchByTags(searchArgs.Tags, filter)
 		if err != nil {
-			return fmt.Errorf("tag search failed: %w", err)
+			return nil, fmt.Errorf("tag search failed: %w", err)
 		}
 		resultSets = append(resultSets, results)
 	}
 
 	// Search by mentions
 	if len(searchArgs.Mentions) > 0 {
-		results, err := a.storage.SearchByMentions(searchArgs.Mentions, filter)
+		results, err := storage.SearchByMentions(searchArgs.Mentions, filter)
 		if err != nil {
-			return fmt.Errorf("mention search failed: %w", err)
+			return nil, fmt.Errorf("mention search failed: %w", err)
 		}
 		resultSets = append(resultSets, results)
 	}
 
 	// Search by keywords
 	for _, keyword := range searchArgs.Keywords {
-		results, err := a.storage.SearchByKeyword(keyword, filter)
+		results, err := storage.SearchByKeyword(keyword, filter)
 		if err != nil {
-			return fmt.Errorf("keyword search failed: %w", err)
+			return nil, fmt.Errorf("keyword search failed: %w", err)
 		}
 		resultSets = append(resultSets, results)
 	}
 
 	// If no search terms, just list all entries in date range
-	var finalResults []*internal.JournalEntry
 	if len(resultSets) == 0 {
-		results, err := a.storage.ListEntries(filter)
+		results, err := storage.ListEntries(filter)
 		if err != nil {
-			return fmt.Errorf("listing entries failed: %w", err)
+			return nil, fmt.Errorf("listing entries failed: %w", err)
 		}
-		finalResults = results
-	} else {
-		// Intersect all result sets (AND logic)
-		finalResults = intersectResults(resultSets)
-	}
-
-	// Apply reverse sort if requested (newest first)
-	if searchArgs.Reverse {
-		sort.Slice(finalResults, func(i, j int) bool {
-			return finalResults[i].Timestamp.After(finalResults[j].Timestamp)
-		})
+		return results, nil
 	}
 
-	// Create colorizer based on color mode
-	colorizer := color.New(searchArgs.ColorMode)
-
-	// Format and display results
-	output := FormatEntries(finalResults, searchArgs.Format, colorizer)
-	fmt.Print(output)
+	// Intersect all result sets (AND logic)
+	return intersectResults(resultSets), nil
+}
 
-	return nil
+// journalEntry is an entry found in one of several journals searched together
+type journalEntry struct {
+	journal string
+	entry   *internal.JournalEntry
 }
 
 // intersectResults returns only entries present in ALL result sets
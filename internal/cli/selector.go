@@ -9,11 +9,11 @@ import (
 
 // EntrySelector handles finding entries based on various selectors
 type EntrySelector struct {
-	storage *internal.FileSystemStorage
+	storage internal.Storage
 }
 
 // NewEntrySelector creates a new entry selector
-func NewEntrySelector(storage *internal.FileSystemStorage) *EntrySelector {
+func NewEntrySelector(storage internal.Storage) *EntrySelector {
 	return &EntrySelector{storage: storage}
 }
 
@@ -15,11 +15,12 @@ import (
 type statsOptions struct {
 	FromDate *time.Time
 	ToDate   *time.Time
-	All      bool   // --all flag
-	Tag      string // --tag filter
-	Mention  string // --mention filter
-	Format   string // "default", "json", "detailed"
-	Detailed bool   // --detailed flag (sets format to "detailed")
+	All      bool     // --all flag
+	Tag      string   // --tag filter
+	Mention  string   // --mention filter
+	Format   string   // "default", "json", "detailed"
+	Detailed bool     // --detailed flag (sets format to "detailed")
+	Journals []string // Named journals to combine (empty = the selected journal)
 }
 
 // executeStats performs the actual stats logic
@@ -54,13 +55,13 @@ func (a *App) executeStats(opts *statsOptions) error {
 	// Display statistics
 	switch opts.Format {
 	case "json":
-		output := displayStatsJSON(stats)
+		output := displayStatsJSON(stats, opts.Journals)
 		fmt.Println(output)
 	case "detailed":
-		output := displayStatsDetailed(stats)
+		output := displayStatsDetailed(stats, opts.Journals)
 		fmt.Print(output)
 	default:
-		output := displayStatsDefault(stats)
+		output := displayStatsDefault(stats, opts.Journals)
 		fmt.Print(output)
 	}
 
@@ -78,13 +79,16 @@ func (a *App) fetchEntriesForStats(opts *statsOptions) ([]*internal.IndexedEntry
 	if opts.All {
 		// Build index for all entries
 		filter := internal.EntryFilter{} // No filters = all entries
-		index, err := a.storage.GetIndex(filter)
+		indexes, err := a.statsIndexes(filter, opts.Journals)
 		if err != nil {
 			return nil, time.Time{}, time.Time{}, false, fmt.Errorf("failed to build index: %w", err)
 		}
 
 		// Get all entries
-		allEntries := index.GetAllEntries()
+		var allEntries []*internal.IndexedEntry
+		for _, index := range indexes {
+			allEntries = append(allEntries, index.GetAllEntries()...)
+		}
 		if len(allEntries) == 0 {
 			return nil, time.Time{}, time.Time{}, false, fmt.Errorf("no entries found")
 		}
@@ -134,19 +138,48 @@ func (a *App) fetchEntriesForStats(opts *statsOptions) ([]*internal.IndexedEntry
 		StartDate: &startDate,
 		EndDate:   &endDate,
 	}
-	index, err := a.storage.GetIndex(filter)
+	indexes, err := a.statsIndexes(filter, opts.Journals)
 	if err != nil {
 		return nil, time.Time{}, time.Time{}, false, fmt.Errorf("failed to build index: %w", err)
 	}
 
 	// Fetch entries in range
-	entries := index.GetEntriesInRange(startDate, endDate)
+	var entries []*internal.IndexedEntry
+	for _, index := range indexes {
+		entries = append(entries, index.GetEntriesInRange(startDate, endDate)...)
+	}
 
 	return entries, startDate, endDate, isAllTime, nil
 }
 
+// statsIndexes builds the index of the selected journal, or of each of several journals
+func (a *App) statsIndexes(filter internal.EntryFilter, journals []string) ([]*internal.Index, error) {
+	if len(journals) == 0 {
+		index, err := a.storage.GetIndex(filter)
+		if err != nil {
+			return nil, err
+		}
+		return []*internal.Index{index}, nil
+	}
+
+	indexes := make([]*internal.Index, 0, len(journals))
+	for _, name := range journals {
+		_, storage, err := a.openJournal(name)
+		if err != nil {
+			return nil, fmt.Errorf("journal %s: %w", name, err)
+		}
+		index, err := storage.GetIndex(filter)
+		if err != nil {
+			return nil, fmt.Errorf("journal %s: %w", name, err)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
 // displayStatsDefault renders statistics in default format with colors
-func displayStatsDefault(stats *internal.Statistics) string {
+// Statistics combined from several journals name them in the header
+func displayStatsDefault(stats *internal.Statistics, journals []string) string {
 	var sb strings.Builder
 
 	// Header
@@ -162,6 +195,9 @@ func displayStatsDefault(stats *internal.Statistics) string {
 
 	// Date range
 	dateFormat := "Jan 2, 2006"
+	if len(journals) > 0 {
+		sb.WriteString(" in " + strings.Join(journals, ", "))
+	}
 	sb.WriteString(fmt.Sprintf(" (%s - %s):\n\n",
 		stats.Period.StartDate.Format(dateFormat),
 		stats.Period.EndDate.Format(dateFormat)))
@@ -234,11 +270,11 @@ func displayStatsDefault(stats *internal.Statistics) string {
 }
 
 // displayStatsDetailed renders statistics in detailed format
-func displayStatsDetailed(stats *internal.Statistics) string {
+func displayStatsDetailed(stats *internal.Statistics, journals []string) string {
 	var sb strings.Builder
 
 	// Start with default output
-	sb.WriteString(displayStatsDefault(stats))
+	sb.WriteString(displayStatsDefault(stats, journals))
 
 	// Add detailed sections
 	sb.WriteString("\n")
@@ -340,7 +376,7 @@ func displayStatsDetailed(stats *internal.Statistics) string {
 
 // displayStatsJSON renders statistics as JSON
 // nolint:gofmt
-func displayStatsJSON(stats *internal.Statistics) string {
+func displayStatsJSON(stats *internal.Statistics, journals []string) string {
 	output := map[string]any{
 		"period": map[string]any{
 			"start_date":  stats.Period.StartDate.Format(time.RFC3339),
@@ -404,6 +440,11 @@ func displayStatsJSON(stats *internal.Statistics) string {
 		"busiest_time": stats.Patterns.BusiestTime,
 	}
 
+	// Add the combined journals if there are several
+	if len(journals) > 0 {
+		output["journals"] = journals
+	}
+
 	// Add filter info if present
 	if stats.FilteredBy != nil {
 		output["filtered_by"] = map[string]any{
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// executeSync syncs the journal with a git remote
+func (a *App) executeSync(remote string) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	result, err := storage.SyncGit(remote, a.resolveSyncConflict)
+	if errors.Is(err, internal.ErrNotGitRepository) {
+		return fmt.Errorf("%w; set JRNLG_GIT_AUTOCOMMIT=true or run 'git init' and add a remote", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.Committed {
+		fmt.Println("Committed local changes.")
+	}
+	if len(result.Pulled) > 0 {
+		fmt.Printf("Pulled %d changed %s from %s.\n", len(result.Pulled), plural("file", len(result.Pulled)), remote)
+	}
+	for _, move := range result.Renamed {
+		fmt.Printf("Kept both entries for %s (remote copy saved as %s)\n",
+			a.displayPath(move.From), a.displayPath(move.To))
+	}
+	for _, path := range result.Merged {
+		fmt.Printf("Merged edits from both sides: %s\n", a.displayPath(path))
+	}
+	for _, path := range result.Resolved {
+		fmt.Printf("Resolved conflicting edits: %s\n", a.displayPath(path))
+	}
+	for _, path := range result.Kept {
+		fmt.Printf("Kept entry edited on one side and deleted on the other: %s\n", a.displayPath(path))
+	}
+	if result.Pushed {
+		fmt.Printf("Pushed to %s.\n", remote)
+	}
+
+	fmt.Println("✓ Sync complete")
+	return nil
+}
+
+// resolveSyncConflict opens the editor on an entry edited on both sides of a sync
+func (a *App) resolveSyncConflict(path, merged string) (string, error) {
+	fmt.Printf("\n⚠ %s was edited on both sides. Opening editor to resolve the conflict...\n", a.displayPath(path))
+	resolved, err := OpenEditor(merged, a.config)
+	if err != nil {
+		return "", fmt.Errorf("failed to open editor: %w", err)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jashort/jrnlg/internal"
+)
+
+// executeSyncDir reconciles the journal with a copy in another directory
+func (a *App) executeSyncDir(otherPath string) error {
+	storage, err := a.fileStorage()
+	if err != nil {
+		return err
+	}
+
+	peer := internal.NewFileSystemStorage(otherPath, a.config)
+
+	result, err := storage.SyncDir(peer)
+	a.commitChanges("sync with " + otherPath)
+	if err != nil {
+		return err
+	}
+
+	sections := []struct {
+		heading string
+		paths   []string
+	}{
+		{"Copied to " + otherPath, result.ToPeer},
+		{"Copied from " + otherPath, result.FromPeer},
+		{"Deleted here (deleted in " + otherPath + ")", result.DeletedHere},
+		{"Deleted in " + otherPath + " (deleted here)", result.DeletedThere},
+		{"Restored (edited on one side, deleted on the other)", result.Restored},
+	}
+	for _, section := range sections {
+		if len(section.paths) == 0 {
+			continue
+		}
+		fmt.Printf("%s:\n", section.heading)
+		for _, path := range section.paths {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+
+	if len(result.Conflicts) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "⚠ Edited in both places (left unchanged):\n")
+		for _, path := range result.Conflicts {
+			_, _ = fmt.Fprintf(os.Stderr, "  %s\n", path)
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Make both copies the same (or delete one) and sync again.\n")
+	}
+
+	fmt.Printf("✓ Synced with %s: %d %s, %d %s\n", otherPath,
+		result.Changes(), plural("change", result.Changes()),
+		len(result.Conflicts), plural("conflict", len(result.Conflicts)))
+	return nil
+}
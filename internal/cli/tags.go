@@ -2,13 +2,11 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/jashort/jrnlg/internal"
-	"github.com/jashort/jrnlg/internal/cli/color"
 )
 
 // MetadataType represents the type of metadata (tag or mention)
@@ -41,26 +39,20 @@ func (m MetadataType) MaxLength() int {
 }
 
 // listMentions displays all mentions with their counts
-func (a *App) listMentions(orphanedOnly bool) error {
-	return a.listMetadata(MetadataTypeMention, orphanedOnly)
+// Counts are combined across journals, if any are given
+func (a *App) listMentions(orphanedOnly bool, journals []string) error {
+	return a.listMetadata(MetadataTypeMention, orphanedOnly, journals)
 }
 
 // listTags displays all tags with their counts
-func (a *App) listTags(orphanedOnly bool) error {
-	return a.listMetadata(MetadataTypeTag, orphanedOnly)
+// Counts are combined across journals, if any are given
+func (a *App) listTags(orphanedOnly bool, journals []string) error {
+	return a.listMetadata(MetadataTypeTag, orphanedOnly, journals)
 }
 
 // listMetadata is the unified function for listing tags or mentions
-func (a *App) listMetadata(metadataType MetadataType, orphanedOnly bool) error {
-	// Get statistics based on type
-	var stats map[string]int
-	var err error
-	if metadataType == MetadataTypeTag {
-		stats, err = a.storage.GetTagStatistics()
-	} else {
-		stats, err = a.storage.GetMentionStatistics()
-	}
-
+func (a *App) listMetadata(metadataType MetadataType, orphanedOnly bool, journals []string) error {
+	stats, err := a.metadataStatistics(metadataType, journals)
 	if err != nil {
 		return fmt.Errorf("failed to get %s statistics: %w", metadataType.Name(), err)
 	}
@@ -90,7 +82,7 @@ func (a *App) listMetadata(metadataType MetadataType, orphanedOnly bool) error {
 	sorted := sortStatisticsAlpha(stats)
 
 	// Format output
-	colorizer := color.New(color.Auto)
+	colorizer := a.colorizer()
 	for _, item := range sorted {
 		// Apply appropriate colorization
 		var displayName string
@@ -110,6 +102,38 @@ func (a *App) listMetadata(metadataType MetadataType, orphanedOnly bool) error {
 	return nil
 }
 
+// metadataStatistics returns the tag or mention usage counts of the selected journal,
+// or the summed counts of several journals
+func (a *App) metadataStatistics(metadataType MetadataType, journals []string) (map[string]int, error) {
+	if len(journals) == 0 {
+		return storageMetadataStatistics(a.storage, metadataType)
+	}
+
+	stats := make(map[string]int)
+	for _, name := range journals {
+		_, storage, err := a.openJournal(name)
+		if err != nil {
+			return nil, fmt.Errorf("journal %s: %w", name, err)
+		}
+		journalStats, err := storageMetadataStatistics(storage, metadataType)
+		if err != nil {
+			return nil, fmt.Errorf("journal %s: %w", name, err)
+		}
+		for item, count := range journalStats {
+			stats[item] += count
+		}
+	}
+	return stats, nil
+}
+
+// storageMetadataStatistics returns the tag or mention usage counts of one journal
+func storageMetadataStatistics(storage internal.Storage, metadataType MetadataType) (map[string]int, error) {
+	if metadataType == MetadataTypeTag {
+		return storage.GetTagStatistics()
+	}
+	return storage.GetMentionStatistics()
+}
+
 // renameTags handles the tag rename subcommand (Kong-compatible signature)
 func (a *App) renameTags(oldName, newName string, dryRun, force bool) error {
 	return a.renameMetadata(oldName, newName, MetadataTypeTag, dryRun, force)
@@ -175,7 +199,7 @@ func (a *App) renameMetadata(oldName, newName string, metadataType MetadataType,
 	)
 
 	if !force && !dryRun {
-		showPreview(filePaths, 5)
+		a.showPreview(filePaths, 5)
 		if len(filePaths) > 5 {
 			fmt.Printf("... and %d more\n\n", len(filePaths)-5)
 		}
@@ -227,6 +251,12 @@ func (a *App) renameMetadata(oldName, newName string, metadataType MetadataType,
 		updated, err = a.storage.ReplaceMentionInEntries(oldName, newName, false)
 	}
 
+	// Commit whatever was updated, even if some entries failed
+	if len(updated) > 0 {
+		a.commitChanges(fmt.Sprintf("rename %s%s -> %s%s in %d %s",
+			metadataType.Symbol(), oldName, metadataType.Symbol(), newName, len(updated), plural("entry", len(updated))))
+	}
+
 	if err != nil {
 		return fmt.Errorf("rename failed: %w", err)
 	}
@@ -261,16 +291,11 @@ func sortStatisticsAlpha(stats map[string]int) []statItem {
 	return items
 }
 
-func showPreview(filePaths []string, maxCount int) {
+func (a *App) showPreview(filePaths []string, maxCount int) {
 	count := min(maxCount, len(filePaths))
 	for i := 0; i < count; i++ {
 		// Parse the file directly
-		content, err := os.ReadFile(filePaths[i])
-		if err != nil {
-			continue
-		}
-
-		entry, err := internal.ParseEntry(string(content))
+		entry, err := a.storage.GetEntryByPath(filePaths[i])
 		if err != nil {
 			continue
 		}
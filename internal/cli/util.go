@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"bufio"
+	"fmt"
+	"os"
 	"strings"
 	"time"
 )
@@ -37,3 +40,32 @@ func ParseTimestamp(s string) (time.Time, error) {
 	// Parse as UTC (same as file naming)
 	return time.Parse("2006-01-02-15-04-05", s)
 }
+
+// stdin reads the answers to prompts. It is kept from one prompt to the next, so answers
+// piped in ahead aren't lost in an earlier prompt's buffer, and replaced if os.Stdin is.
+var stdin struct {
+	file   *os.File
+	reader *bufio.Reader
+}
+
+// promptChoice asks until the user enters one of the choices, ignoring case and spaces
+// around it. It fails when the input ends.
+func promptChoice(choices ...string) (string, error) {
+	if stdin.file != os.Stdin {
+		stdin.file, stdin.reader = os.Stdin, bufio.NewReader(os.Stdin)
+	}
+	for {
+		fmt.Printf("Choose (%s): ", strings.Join(choices, "/"))
+
+		input, err := stdin.reader.ReadString('\n')
+		response := strings.ToLower(strings.TrimSpace(input))
+		for _, choice := range choices {
+			if response == choice {
+				return choice, nil
+			}
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+	}
+}
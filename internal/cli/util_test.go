@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"os"
 	"testing"
 	"time"
 )
@@ -278,3 +279,29 @@ func TestParseTimestamp(t *testing.T) {
 		})
 	}
 }
+
+func TestPromptChoice(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe() error = %v", err)
+	}
+	// Answers for several prompts, piped in at once; the last one without a newline
+	_, _ = w.WriteString("x\n  K \nkeep both\ns")
+	_ = w.Close()
+	oldStdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = oldStdin }()
+
+	_ = captureStdout(t, func() error {
+		for _, want := range []string{"k", "s"} {
+			choice, err := promptChoice("e", "k", "s")
+			if err != nil || choice != want {
+				t.Errorf("promptChoice() = %q, %v; want %q", choice, err, want)
+			}
+		}
+		if choice, err := promptChoice("e", "k", "s"); err == nil {
+			t.Errorf("promptChoice() at the end of input = %q, want an error", choice)
+		}
+		return nil
+	})
+}
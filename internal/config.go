@@ -1,20 +1,43 @@
 package internal
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // Config holds configuration for journal storage
 type Config struct {
-	StoragePath     string       // Path to store journal entries
-	ParallelParse   bool         // Enable parallel parsing of entries
-	MaxParseWorkers int          // Maximum number of parallel parsing workers
-	EditorArgs      []string     // Additional arguments to pass to the editor
-	Logger          *slog.Logger // Structured logger
+	StoragePath       string            // Path to store journal entries
+	Backend           Backend           // Storage backend for entries
+	JrnlFile          string            // Journal file of the jrnl backend
+	WebDAVURL         string            // Journal collection of the webdav backend
+	WebDAVUser        string            // WebDAV basic auth user
+	WebDAVPassword    string            // WebDAV basic auth password
+	WebDAVCache       string            // Local cache of the webdav backend, for offline reads
+	ParallelParse     bool              // Enable parallel parsing of entries
+	MaxParseWorkers   int               // Maximum number of parallel parsing workers
+	EditorArgs        []string          // Additional arguments to pass to the editor
+	Layout            Layout            // Layout for new journals (existing journals record their own)
+	GitAutoCommit     bool              // Commit changed entry files to git after every mutating command
+	PassphraseCommand string            // Command that prints the passphrase of an encrypted journal
+	SigningKeyPath    string            // ed25519 private key for signing entries
+	Editor            string            // Editor command (VISUAL and EDITOR take precedence)
+	Format            string            // Default output format of search
+	Color             string            // Default color mode
+	Timezone          string            // Time zone for entries (TZ takes precedence)
+	Templates         map[string]string // Entry templates by name ("default" is used by add)
+	Journal           string            // Name of the selected journal ("" without named journals)
+	DefaultJournal    string            // Journal used when none is selected
+	Journals          []string          // Names of the journals in the config file, sorted
+	Project           string            // Directory of the project journal in use ("" if none)
+	Logger            *slog.Logger      // Structured logger
+
+	sources map[string]string // Where each setting not at its default came from
 }
 
 // DefaultConfig returns a configuration with default values
@@ -47,26 +70,66 @@ func DefaultConfig() *Config {
 
 	return &Config{
 		StoragePath:     filepath.Join(homeDir, ".jrnlg", "entries"),
+		Backend:         BackendFiles,
+		JrnlFile:        filepath.Join(homeDir, ".local", "share", "jrnl", "journal.txt"),
+		WebDAVCache:     filepath.Join(homeDir, ".jrnlg", "webdav-cache"),
+		SigningKeyPath:  filepath.Join(homeDir, ".jrnlg", "signing.key"),
 		ParallelParse:   true,
 		MaxParseWorkers: runtime.NumCPU(),
+		Layout:          LayoutYearMonth,
+		Format:          "full",
+		Color:           "auto",
 		Logger:          logger,
+		sources:         make(map[string]string),
 	}
 }
 
-// LoadConfig loads configuration from environment variables and defaults
+// LoadConfig loads configuration from the config file, environment variables and defaults
+// for the journal selected by JRNLG_JOURNAL, the project journal of the working directory,
+// or the default journal
 func LoadConfig() (*Config, error) {
+	return LoadJournalConfig("")
+}
+
+// LoadJournalConfig loads configuration for the named journal, or like LoadConfig if
+// journal is ""
+func LoadJournalConfig(journal string) (*Config, error) {
 	config := DefaultConfig()
+	if journal == "" {
+		journal = os.Getenv("JRNLG_JOURNAL")
+	}
 
-	// Override with environment variable if set
-	if storagePath := os.Getenv("JRNLG_STORAGE_PATH"); storagePath != "" {
-		config.StoragePath = storagePath
+	// Unless a named journal was asked for, a project journal takes over
+	if journal == "" {
+		if dir, err := os.Getwd(); err == nil {
+			config.Project = FindProjectJournal(dir)
+		}
 	}
 
-	// Parse editor arguments from environment variable
-	if editorArgs := os.Getenv("JRNLG_EDITOR_ARGS"); editorArgs != "" {
-		config.EditorArgs = parseEditorArgs(editorArgs)
+	// Config files, overridden by environment variables
+	if err := config.loadConfigFile(ConfigPath(), journal); err != nil {
+		return nil, err
+	}
+	if config.Project != "" {
+		if err := config.loadProject(); err != nil {
+			return nil, err
+		}
+	}
+	for _, setting := range Settings {
+		for _, env := range setting.Env {
+			if text := os.Getenv(env); text != "" {
+				if _, err := setting.Set(config, text); err != nil {
+					return nil, fmt.Errorf("%s: %w", env, err)
+				}
+				config.sources[setting.Key] = env
+				break
+			}
+		}
 	}
 
+	// Secrets are only read from the environment
+	config.WebDAVPassword = os.Getenv("JRNLG_WEBDAV_PASSWORD")
+
 	return config, nil
 }
 
@@ -107,3 +170,15 @@ func parseEditorArgs(input string) []string {
 
 	return args
 }
+
+// Location returns the configured time zone, or nil if none is set
+func (c *Config) Location() *time.Location {
+	if c.Timezone == "" {
+		return nil
+	}
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil // Validated when loaded
+	}
+	return location
+}
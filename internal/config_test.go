@@ -1,8 +1,13 @@
 package internal
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/BurntSushi/toml"
 )
 
 func TestParseEditorArgs(t *testing.T) {
@@ -90,3 +95,256 @@ func TestLoadConfig_NoEditorArgs(t *testing.T) {
 		t.Errorf("EditorArgs should be empty, got %v", config.EditorArgs)
 	}
 }
+
+func TestLoadConfig_Layout(t *testing.T) {
+	t.Setenv("JRNLG_LAYOUT", "year-week")
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Layout != LayoutYearWeek {
+		t.Errorf("Layout = %q, want %q", config.Layout, LayoutYearWeek)
+	}
+
+	t.Setenv("JRNLG_LAYOUT", "yearly")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() expected error for invalid JRNLG_LAYOUT")
+	}
+}
+
+func TestLoadConfig_GitAutoCommit(t *testing.T) {
+	t.Setenv("JRNLG_GIT_AUTOCOMMIT", "true")
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !config.GitAutoCommit {
+		t.Error("GitAutoCommit = false, want true")
+	}
+
+	t.Setenv("JRNLG_GIT_AUTOCOMMIT", "sometimes")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() expected error for invalid JRNLG_GIT_AUTOCOMMIT")
+	}
+}
+
+func TestLoadConfig_PassphraseCommand(t *testing.T) {
+	t.Setenv("JRNLG_PASSPHRASE_COMMAND", "pass show jrnlg")
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.PassphraseCommand != "pass show jrnlg" {
+		t.Errorf("PassphraseCommand = %q", config.PassphraseCommand)
+	}
+}
+
+func TestLoadConfig_SigningKey(t *testing.T) {
+	t.Setenv("JRNLG_SIGNING_KEY", "/secure/jrnlg.key")
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.SigningKeyPath != "/secure/jrnlg.key" {
+		t.Errorf("SigningKeyPath = %q", config.SigningKeyPath)
+	}
+}
+
+func TestLoadConfig_Backend(t *testing.T) {
+	t.Setenv("JRNLG_BACKEND", "jrnl")
+	t.Setenv("JRNLG_JRNL_FILE", "/shared/journal.txt")
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Backend != BackendJrnl || config.JrnlFile != "/shared/journal.txt" {
+		t.Errorf("Backend = %q, JrnlFile = %q", config.Backend, config.JrnlFile)
+	}
+
+	t.Setenv("JRNLG_BACKEND", "sqlite")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() expected error for invalid JRNLG_BACKEND")
+	}
+}
+
+// writeConfigFile creates a config file in a new XDG_CONFIG_HOME
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), DirPermissions); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig_File(t *testing.T) {
+	writeConfigFile(t, `
+storage_path = "/srv/journal"
+format = "summary"
+max_parse_workers = 2
+editor_args = ["+startinsert"]
+layout = "flat"
+
+[templates]
+standup = "Yesterday:\nToday:\n"
+`)
+	t.Setenv("JRNLG_LAYOUT", "slug") // The environment overrides the file
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.StoragePath != "/srv/journal" || config.Format != "summary" || config.MaxParseWorkers != 2 {
+		t.Errorf("StoragePath = %q, Format = %q, MaxParseWorkers = %d", config.StoragePath, config.Format, config.MaxParseWorkers)
+	}
+	if !reflect.DeepEqual(config.EditorArgs, []string{"+startinsert"}) {
+		t.Errorf("EditorArgs = %v", config.EditorArgs)
+	}
+	if config.Templates["standup"] != "Yesterday:\nToday:\n" {
+		t.Errorf("Templates = %v", config.Templates)
+	}
+	if config.Layout != LayoutSlug {
+		t.Errorf("Layout = %q, want slug from JRNLG_LAYOUT", config.Layout)
+	}
+
+	for key, want := range map[string]string{"format": "config file", "layout": "JRNLG_LAYOUT", "color": "default"} {
+		if got := config.Source(key); got != want {
+			t.Errorf("Source(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestLoadConfig_FileErrors(t *testing.T) {
+	tests := map[string]string{
+		"format = \"fancy\"":          "format: invalid format",
+		"max_parse_workers = \"8\"":   "max_parse_workers: must be a whole number",
+		"parallel_parse = 1":          "parallel_parse: must be true or false",
+		"timezone = \"Mars/Olympus\"": "timezone:",
+		"colour = \"always\"":         `unknown key "colour"`,
+		"format = ":                   "line 1",
+	}
+	for content, want := range tests {
+		path := writeConfigFile(t, content+"\n")
+		_, err := LoadConfig()
+		if err == nil || !strings.Contains(err.Error(), want) || !strings.Contains(err.Error(), path) {
+			t.Errorf("LoadConfig() with %q error = %v, want %q", content, err, want)
+		}
+	}
+}
+
+func TestSetConfigFileValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(`# My settings
+format = "full" # for now
+
+[templates]
+standup = """
+Yesterday:
+"""
+`), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	for key, value := range map[string]any{
+		"format":                     "summary",
+		"max_parse_workers":          4,
+		"templates.standup":          "Today:\n",
+		"templates.weekly":           "Goals:\n",
+		"parallel_parse":             false,
+		"editor_args":                []string{"-nw"},
+		"journals.work.storage_path": "/srv/work",
+	} {
+		if err := SetConfigFileValue(path, key, value); err != nil {
+			t.Fatalf("SetConfigFileValue(%s) error = %v", key, err)
+		}
+	}
+
+	content, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(content), "# My settings\n") {
+		t.Errorf("comment lost:\n%s", content)
+	}
+
+	var values map[string]any
+	if _, err := toml.Decode(string(content), &values); err != nil {
+		t.Fatalf("config file invalid: %v\n%s", err, content)
+	}
+	templates := values["templates"].(map[string]any)
+	if values["format"] != "summary" || values["max_parse_workers"] != int64(4) || values["parallel_parse"] != false ||
+		templates["standup"] != "Today:\n" || templates["weekly"] != "Goals:\n" ||
+		values["journals"].(map[string]any)["work"].(map[string]any)["storage_path"] != "/srv/work" {
+		t.Errorf("config file =\n%s", content)
+	}
+}
+
+func TestLoadJournalConfig(t *testing.T) {
+	writeConfigFile(t, `
+storage_path = "/srv/journal"
+format = "summary"
+default_journal = "personal"
+
+[journals.work]
+storage_path = "/srv/work"
+format = "json"
+
+[journals.personal]
+backend = "jrnl"
+jrnl_file = "/srv/personal.txt"
+`)
+	t.Setenv("JRNLG_JOURNAL", "")
+
+	// The default journal is used when none is given
+	config, err := LoadJournalConfig("")
+	if err != nil {
+		t.Fatalf("LoadJournalConfig() error = %v", err)
+	}
+	if config.Journal != "personal" || config.Backend != BackendJrnl || config.JrnlFile != "/srv/personal.txt" {
+		t.Errorf("Journal = %q, Backend = %q, JrnlFile = %q", config.Journal, config.Backend, config.JrnlFile)
+	}
+	if !reflect.DeepEqual(config.Journals, []string{"personal", "work"}) {
+		t.Errorf("Journals = %v", config.Journals)
+	}
+
+	// A journal's settings override the top-level ones
+	config, err = LoadJournalConfig("work")
+	if err != nil {
+		t.Fatalf("LoadJournalConfig(work) error = %v", err)
+	}
+	if config.StoragePath != "/srv/work" || config.Format != "json" || config.Source("format") != "journal work" {
+		t.Errorf("StoragePath = %q, Format = %q from %s", config.StoragePath, config.Format, config.Source("format"))
+	}
+
+	// JRNLG_JOURNAL selects a journal, and the environment still overrides its settings
+	t.Setenv("JRNLG_JOURNAL", "work")
+	t.Setenv("JRNLG_STORAGE_PATH", "/tmp/elsewhere")
+	config, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Journal != "work" || config.StoragePath != "/tmp/elsewhere" {
+		t.Errorf("Journal = %q, StoragePath = %q", config.Journal, config.StoragePath)
+	}
+
+	if _, err := LoadJournalConfig("travel"); err == nil || !strings.Contains(err.Error(), "journals: personal, work") {
+		t.Errorf("LoadJournalConfig(travel) error = %v, want unknown journal", err)
+	}
+}
+
+func TestLoadJournalConfig_Errors(t *testing.T) {
+	t.Setenv("JRNLG_JOURNAL", "")
+	tests := map[string]string{
+		"[journals.work]\nformat = \"fancy\"": "journals.work.format: invalid format",
+		"[journals.work]\ncolour = \"never\"": `unknown key "journals.work.colour"`,
+		"default_journal = \"work\"":          `unknown journal "work": no journals are defined`,
+	}
+	for content, want := range tests {
+		writeConfigFile(t, content+"\n")
+		if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadConfig() with %q error = %v, want %q", content, err, want)
+		}
+	}
+}
@@ -0,0 +1,562 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigPath returns the path of the config file: $XDG_CONFIG_HOME/jrnlg/config.toml,
+// or ~/.config/jrnlg/config.toml
+func ConfigPath() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "jrnlg", "config.toml")
+}
+
+// settingKind is the type of a setting's value
+type settingKind int
+
+const (
+	kindString settingKind = iota
+	kindBool
+	kindInt
+	kindList
+)
+
+// Setting is a configuration key that can be set in the config file
+type Setting struct {
+	Key  string   // Name in the config file
+	Env  []string // Environment variables overriding the file, highest precedence first
+	kind settingKind
+	// apply validates a value (string, bool, int or []string by kind) and stores it
+	apply func(c *Config, value any) error
+	// value returns the current value
+	value func(c *Config) any
+}
+
+// TemplatesKey is the config file table of entry templates
+const TemplatesKey = "templates"
+
+// JournalsKey is the config file table of named journals
+const JournalsKey = "journals"
+
+// Settings lists the keys of the config file, in the order they're listed
+var Settings = []*Setting{
+	pathSetting("storage_path", "JRNLG_STORAGE_PATH", func(c *Config) *string { return &c.StoragePath }),
+	{
+		Key: "backend", Env: []string{"JRNLG_BACKEND"},
+		apply: func(c *Config, value any) (err error) {
+			c.Backend, err = ParseBackend(value.(string))
+			return err
+		},
+		value: func(c *Config) any { return string(c.Backend) },
+	},
+	{
+		Key: "layout", Env: []string{"JRNLG_LAYOUT"},
+		apply: func(c *Config, value any) (err error) {
+			c.Layout, err = ParseLayout(value.(string))
+			return err
+		},
+		value: func(c *Config) any { return string(c.Layout) },
+	},
+	pathSetting("jrnl_file", "JRNLG_JRNL_FILE", func(c *Config) *string { return &c.JrnlFile }),
+	stringSetting("webdav_url", "JRNLG_WEBDAV_URL", func(c *Config) *string { return &c.WebDAVURL }),
+	stringSetting("webdav_user", "JRNLG_WEBDAV_USER", func(c *Config) *string { return &c.WebDAVUser }),
+	pathSetting("webdav_cache", "JRNLG_WEBDAV_CACHE", func(c *Config) *string { return &c.WebDAVCache }),
+	{
+		Key: "editor", Env: []string{"VISUAL", "EDITOR"},
+		apply: func(c *Config, value any) error {
+			c.Editor = value.(string)
+			return nil
+		},
+		value: func(c *Config) any { return c.Editor },
+	},
+	{
+		Key: "editor_args", Env: []string{"JRNLG_EDITOR_ARGS"}, kind: kindList,
+		apply: func(c *Config, value any) error {
+			c.EditorArgs = value.([]string)
+			return nil
+		},
+		value: func(c *Config) any { return c.EditorArgs },
+	},
+	{
+		Key: "format",
+		apply: func(c *Config, value any) error {
+			switch format := value.(string); format {
+			case "full", "summary", "json":
+				c.Format = format
+				return nil
+			}
+			return fmt.Errorf("invalid format %q: must be one of full, summary, json", value)
+		},
+		value: func(c *Config) any { return c.Format },
+	},
+	{
+		Key: "color",
+		apply: func(c *Config, value any) error {
+			switch mode := value.(string); mode {
+			case "auto", "always", "never":
+				c.Color = mode
+				return nil
+			}
+			return fmt.Errorf("invalid color mode %q: must be auto, always, or never", value)
+		},
+		value: func(c *Config) any { return c.Color },
+	},
+	{
+		Key: "parallel_parse", kind: kindBool,
+		apply: func(c *Config, value any) error {
+			c.ParallelParse = value.(bool)
+			return nil
+		},
+		value: func(c *Config) any { return c.ParallelParse },
+	},
+	{
+		Key: "max_parse_workers", kind: kindInt,
+		apply: func(c *Config, value any) error {
+			if value.(int) < 1 {
+				return fmt.Errorf("must be at least 1")
+			}
+			c.MaxParseWorkers = value.(int)
+			return nil
+		},
+		value: func(c *Config) any { return c.MaxParseWorkers },
+	},
+	{
+		Key: "timezone",
+		apply: func(c *Config, value any) error {
+			if _, err := time.LoadLocation(value.(string)); err != nil {
+				return err
+			}
+			c.Timezone = value.(string)
+			return nil
+		},
+		value: func(c *Config) any { return c.Timezone },
+	},
+	{
+		Key: "git_autocommit", Env: []string{"JRNLG_GIT_AUTOCOMMIT"}, kind: kindBool,
+		apply: func(c *Config, value any) error {
+			c.GitAutoCommit = value.(bool)
+			return nil
+		},
+		value: func(c *Config) any { return c.GitAutoCommit },
+	},
+	pathSetting("signing_key", "JRNLG_SIGNING_KEY", func(c *Config) *string { return &c.SigningKeyPath }),
+	stringSetting("passphrase_command", "JRNLG_PASSPHRASE_COMMAND", func(c *Config) *string { return &c.PassphraseCommand }),
+	{
+		Key: "default_journal", Env: []string{"JRNLG_JOURNAL"},
+		apply: func(c *Config, value any) error {
+			if !tableKeyName.MatchString(value.(string)) {
+				return fmt.Errorf("invalid journal name %q: use letters, digits, - and _", value)
+			}
+			c.DefaultJournal = value.(string)
+			return nil
+		},
+		value: func(c *Config) any { return c.DefaultJournal },
+	},
+}
+
+// stringSetting returns a setting stored in a string field
+func stringSetting(key, env string, field func(c *Config) *string) *Setting {
+	return &Setting{
+		Key: key, Env: []string{env},
+		apply: func(c *Config, value any) error {
+			*field(c) = value.(string)
+			return nil
+		},
+		value: func(c *Config) any { return *field(c) },
+	}
+}
+
+// pathSetting returns a setting holding a file path; a leading ~/ is the home directory
+func pathSetting(key, env string, field func(c *Config) *string) *Setting {
+	setting := stringSetting(key, env, field)
+	setting.apply = func(c *Config, value any) error {
+		*field(c) = expandHome(value.(string))
+		return nil
+	}
+	return setting
+}
+
+// expandHome replaces a leading ~/ with the home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, rest)
+		}
+	}
+	return path
+}
+
+// tableKeyName matches template and journal names, which must be bare TOML keys
+var tableKeyName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// LookupSetting returns the setting for key, including templates.<name> keys and the
+// journals.<journal>.<key> keys of named journals
+func LookupSetting(key string) (*Setting, error) {
+	for _, setting := range Settings {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+
+	if journal, name, ok := splitJournalKey(key); ok {
+		setting, err := LookupSetting(name)
+		if err != nil || strings.Contains(name, ".") || setting.Key == "default_journal" {
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+		if !tableKeyName.MatchString(journal) {
+			return nil, fmt.Errorf("invalid journal name %q: use letters, digits, - and _", journal)
+		}
+		return &Setting{Key: key, kind: setting.kind, apply: setting.apply, value: setting.value}, nil
+	}
+
+	if name, ok := strings.CutPrefix(key, TemplatesKey+"."); ok {
+		if !tableKeyName.MatchString(name) {
+			return nil, fmt.Errorf("invalid template name %q: use letters, digits, - and _", name)
+		}
+		return &Setting{
+			Key: key,
+			apply: func(c *Config, value any) error {
+				if c.Templates == nil {
+					c.Templates = make(map[string]string)
+				}
+				c.Templates[name] = value.(string)
+				return nil
+			},
+			value: func(c *Config) any { return c.Templates[name] },
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", key)
+}
+
+// splitJournalKey splits a journals.<journal>.<key> key
+func splitJournalKey(key string) (journal, name string, ok bool) {
+	rest, ok := strings.CutPrefix(key, JournalsKey+".")
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(rest, ".")
+}
+
+// Value returns the setting's current value in config
+func (s *Setting) Value(c *Config) any {
+	return s.value(c)
+}
+
+// Set parses a value given as text (from the environment or the command line), validates
+// it and stores it in config. Returns the parsed value.
+func (s *Setting) Set(c *Config, text string) (any, error) {
+	var value any
+	switch s.kind {
+	case kindBool:
+		enabled, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q (use true or false)", text)
+		}
+		value = enabled
+	case kindInt:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q (use a whole number)", text)
+		}
+		value = n
+	case kindList:
+		value = parseEditorArgs(text)
+	default:
+		value = text
+	}
+	return value, s.apply(c, value)
+}
+
+// setFromFile validates a value decoded from the config file and stores it in config
+func (s *Setting) setFromFile(c *Config, value any) error {
+	switch s.kind {
+	case kindBool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("must be true or false")
+		}
+	case kindInt:
+		n, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("must be a whole number")
+		}
+		value = int(n)
+	case kindList:
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("must be a list of strings")
+		}
+		list := make([]string, len(items))
+		for i, item := range items {
+			if list[i], ok = item.(string); !ok {
+				return fmt.Errorf("must be a list of strings")
+			}
+		}
+		value = list
+	default:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("must be a string")
+		}
+	}
+	return s.apply(c, value)
+}
+
+// loadConfigFile applies the settings in the config file at path, if there is one, and
+// those of the selected journal ("" for the default journal, unless a project journal is
+// in use)
+func (c *Config) loadConfigFile(path, journal string) error {
+	keys, err := decodeConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	journalKeys := make(map[string][]configValue)
+	for _, key := range keys {
+		setting, err := LookupSetting(key.name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		// Journal settings are checked now, and applied once the journal is known
+		target := c
+		name, _, isJournal := splitJournalKey(key.name)
+		if isJournal {
+			target = &Config{}
+			journalKeys[name] = append(journalKeys[name], key)
+		}
+		if err := setting.setFromFile(target, key.value); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, key.name, err)
+		}
+		if !isJournal {
+			c.sources[key.name] = "config file"
+		}
+	}
+
+	c.Journals = make([]string, 0, len(journalKeys))
+	for name := range journalKeys {
+		c.Journals = append(c.Journals, name)
+	}
+	sort.Strings(c.Journals)
+
+	if journal == "" && c.Project == "" {
+		journal = c.DefaultJournal
+	}
+	if journal == "" {
+		return nil
+	}
+	keys, ok := journalKeys[journal]
+	if !ok {
+		if len(c.Journals) == 0 {
+			return fmt.Errorf("unknown journal %q: no journals are defined in %s", journal, path)
+		}
+		return fmt.Errorf("unknown journal %q (journals: %s)", journal, strings.Join(c.Journals, ", "))
+	}
+	for _, key := range keys {
+		_, name, _ := splitJournalKey(key.name)
+		setting, _ := LookupSetting(name)
+		if err := setting.setFromFile(c, key.value); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, key.name, err)
+		}
+		c.sources[name] = "journal " + journal
+	}
+	c.Journal = journal
+	return nil
+}
+
+// loadProject stores entries in the project journal's directory, with the settings of its
+// config file. Journals are only defined in the user's config file.
+func (c *Config) loadProject() error {
+	c.StoragePath, c.Backend = c.Project, BackendFiles
+	c.sources["storage_path"], c.sources["backend"] = "project journal", "project journal"
+
+	path := filepath.Join(c.Project, ProjectConfigFile)
+	keys, err := decodeConfigFile(path)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		setting, err := LookupSetting(key.name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if _, _, isJournal := splitJournalKey(key.name); isJournal || key.name == "default_journal" {
+			return fmt.Errorf("%s: %s can only be set in %s", path, key.name, ConfigPath())
+		}
+		if err := setting.setFromFile(c, key.value); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, key.name, err)
+		}
+		c.sources[key.name] = "project config"
+	}
+	return nil
+}
+
+// decodeConfigFile returns the keys of the config file at path; none if it doesn't exist
+func decodeConfigFile(path string) ([]configValue, error) {
+	var values map[string]any
+	if _, err := toml.DecodeFile(path, &values); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return flattenConfig(values), nil
+}
+
+// configValue is a key of the config file with its value
+type configValue struct {
+	name  string
+	value any
+}
+
+// flattenConfig returns the keys of a decoded config file, sorted, with the entries of the
+// templates table as templates.<name> and those of journal tables as journals.<journal>.<key>
+func flattenConfig(values map[string]any) []configValue {
+	var keys []configValue
+	for name, value := range values {
+		table, isTable := value.(map[string]any)
+		switch {
+		case isTable && name == TemplatesKey:
+			for template, text := range table {
+				keys = append(keys, configValue{TemplatesKey + "." + template, text})
+			}
+		case isTable && name == JournalsKey:
+			for journal, settings := range table {
+				journalTable, ok := settings.(map[string]any)
+				if !ok {
+					keys = append(keys, configValue{JournalsKey + "." + journal, settings})
+					continue
+				}
+				for key, setting := range journalTable {
+					keys = append(keys, configValue{JournalsKey + "." + journal + "." + key, setting})
+				}
+			}
+		default:
+			keys = append(keys, configValue{name, value})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+	return keys
+}
+
+// Source describes where the value of a setting came from: "default", "config file" or
+// the environment variable that set it
+func (c *Config) Source(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return "default"
+}
+
+// FormatConfigValue renders a setting's value as TOML
+func FormatConfigValue(value any) string {
+	var sb strings.Builder
+	if err := toml.NewEncoder(&sb).Encode(map[string]any{"v": value}); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(strings.TrimPrefix(sb.String(), "v = "))
+}
+
+// tableHeader matches TOML table headers
+var tableHeader = regexp.MustCompile(`^\s*\[`)
+
+// SetConfigFileValue writes key = value into the config file at path, replacing the key's
+// current line and keeping the rest of the file (including comments) as it is
+func SetConfigFileValue(path, key string, value any) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// templates.<name> and journals.<journal>.<key> are set in their tables
+	table, name := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		table, name = key[:i], key[i+1:]
+	}
+	line := name + " = " + FormatConfigValue(value)
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(content) == 0 {
+		lines = nil
+	}
+	lines = setTOMLLine(lines, table, name, line)
+
+	updated := strings.Join(lines, "\n") + "\n"
+	var check map[string]any
+	if _, err := toml.Decode(updated, &check); err != nil {
+		return fmt.Errorf("can't update %s in %s, edit it by hand: %w", key, path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), DirPermissions); err != nil {
+		return err
+	}
+	return replaceFile(path, []byte(updated))
+}
+
+// setTOMLLine replaces the line (or lines, for a multi-line value) of key name in table
+// ("" for top-level keys) with line, or adds it at the end of the table
+func setTOMLLine(lines []string, table, name, line string) []string {
+	keyLine := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(name) + `\s*=`)
+
+	// Find the table's lines: top-level keys come before the first table header
+	start, end := 0, len(lines)
+	if table != "" {
+		start = -1
+		for i, l := range lines {
+			if strings.TrimSpace(l) == "["+table+"]" {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			return append(lines, "["+table+"]", line)
+		}
+	}
+	for i := start; i < len(lines); i++ {
+		if tableHeader.MatchString(lines[i]) {
+			end = i
+			break
+		}
+	}
+
+	for i := start; i < end; i++ {
+		if keyLine.MatchString(lines[i]) {
+			last := valueEnd(lines, i, end)
+			return append(lines[:i], append([]string{line}, lines[last+1:]...)...)
+		}
+	}
+
+	// Add after the table's last key, before blank lines separating it from the next table
+	insert := end
+	for insert > start && strings.TrimSpace(lines[insert-1]) == "" {
+		insert--
+	}
+	return append(lines[:insert], append([]string{line}, lines[insert:]...)...)
+}
+
+// valueEnd returns the last line of the key-value pair starting at line first, which is
+// the first line at which it parses
+func valueEnd(lines []string, first, end int) int {
+	for last := first; last < end; last++ {
+		var check map[string]any
+		if _, err := toml.Decode(strings.Join(lines[first:last+1], "\n"), &check); err == nil {
+			return last
+		}
+	}
+	return first
+}
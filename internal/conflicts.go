@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// conflictCopyName matches conflict copies left by file sync tools, capturing the primary name:
+//
+//	2024-02-09-14-30-00.sync-conflict-20240210-101500-ABCDEF1.md     (Syncthing)
+//	2024-02-09-14-30-00 (conflicted copy).md                          (Dropbox, Nextcloud)
+//	2024-02-09-14-30-00 (Jane's conflicted copy 2024-02-10).md        (Dropbox)
+var conflictCopyName = regexp.MustCompile(`^(.+?)(?:\.sync-conflict-[^.]*| \([^)]*conflicted copy[^)]*\))\.md$`)
+
+// conflictCopyPrimary returns the filename of the entry a conflict copy belongs to
+func conflictCopyPrimary(name string) (string, bool) {
+	match := conflictCopyName.FindStringSubmatch(name)
+	if match == nil {
+		return "", false
+	}
+	return match[1] + MarkdownExt, true
+}
+
+// isConflictCopy reports whether a filename is a conflict copy left by a file sync tool
+func isConflictCopy(name string) bool {
+	_, ok := conflictCopyPrimary(name)
+	return ok
+}
+
+// ConflictCopy is a conflicting version of an entry left next to it by a file sync tool
+type ConflictCopy struct {
+	Path    string // The conflict copy
+	Primary string // The entry it conflicts with ("" if the entry no longer exists)
+}
+
+// FindConflictCopies returns all conflict copies in the storage tree, sorted by path
+func (fs *FileSystemStorage) FindConflictCopies() ([]ConflictCopy, error) {
+	var copies []ConflictCopy
+
+	err := filepath.WalkDir(fs.basePath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == fs.basePath && os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != fs.basePath && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		primaryName, ok := conflictCopyPrimary(d.Name())
+		if !ok {
+			return nil
+		}
+
+		conflict := ConflictCopy{Path: path}
+		primary := filepath.Join(filepath.Dir(path), primaryName)
+		if _, err := os.Stat(primary); err == nil {
+			conflict.Primary = primary
+		} else if entry, err := fs.parseFile(path); err == nil {
+			// The entry may have moved (e.g. by a layout migration); look it up by timestamp
+			if found, err := fs.GetEntryPath(entry.Timestamp); err == nil {
+				conflict.Primary = found
+			}
+		}
+
+		copies = append(copies, conflict)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan storage: %w", err)
+	}
+
+	sort.Slice(copies, func(i, j int) bool {
+		return copies[i].Path < copies[j].Path
+	})
+	return copies, nil
+}
+
+// KeepConflictPrimary resolves a conflict by deleting the conflict copy
+func (fs *FileSystemStorage) KeepConflictPrimary(conflict ConflictCopy) error {
+	if err := fs.removeFile(conflict.Path); err != nil {
+		return fmt.Errorf("failed to remove conflict copy: %w", err)
+	}
+	fs.InvalidateIndex()
+	return nil
+}
+
+// KeepConflictCopy resolves a conflict by replacing the entry with the conflict copy
+// Returns the path the copy was saved to
+func (fs *FileSystemStorage) KeepConflictCopy(conflict ConflictCopy) (string, error) {
+	if conflict.Primary == "" {
+		return fs.KeepConflictBoth(conflict)
+	}
+
+	content, err := fs.readEntryFile(conflict.Path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := ParseEntry(string(content)); err != nil {
+		return "", fmt.Errorf("conflict copy is not a valid entry: %w", err)
+	}
+
+	return conflict.Primary, fs.ResolveConflictWith(conflict, string(content))
+}
+
+// KeepConflictBoth resolves a conflict by keeping the conflict copy as a separate entry
+// with a collision suffix. Returns the path of the new entry.
+func (fs *FileSystemStorage) KeepConflictBoth(conflict ConflictCopy) (string, error) {
+	entry, err := fs.parseFile(conflict.Path)
+	if err != nil {
+		return "", fmt.Errorf("conflict copy is not a valid entry: %w", err)
+	}
+
+	target := fs.availableEntryPath(entry)
+	if target == "" {
+		return "", fmt.Errorf("too many entries with same timestamp")
+	}
+	chain, err := fs.openChain()
+	if err != nil {
+		return "", err
+	}
+	text, err := fs.readEntryFile(conflict.Path)
+	if err != nil {
+		return "", err
+	}
+
+	if err := fs.ensureDirectories(target); err != nil {
+		return "", err
+	}
+	if err := fs.renameFile(conflict.Path, target); err != nil {
+		return "", fmt.Errorf("failed to keep conflict copy: %w", err)
+	}
+
+	fs.InvalidateIndex()
+	return target, fs.signWrite(chain, target, entry, text, "")
+}
+
+// ResolveConflictWith resolves a conflict by writing content (e.g. a manual merge of both
+// versions) to the entry and deleting the conflict copy
+func (fs *FileSystemStorage) ResolveConflictWith(conflict ConflictCopy, content string) error {
+	if conflict.Primary == "" {
+		return fmt.Errorf("the entry for %s no longer exists", filepath.Base(conflict.Path))
+	}
+	if HasConflictMarkers(content) {
+		return fmt.Errorf("unresolved conflict markers remain")
+	}
+	entry, err := ParseEntry(content)
+	if err != nil {
+		return fmt.Errorf("invalid entry format: %w", err)
+	}
+	chain, err := fs.openChain()
+	if err != nil {
+		return err
+	}
+	previous := fs.textHash(conflict.Primary)
+
+	if err := fs.writeEntryFile(conflict.Primary, []byte(content)); err != nil {
+		return fmt.Errorf("failed to update entry: %w", err)
+	}
+	if err := fs.KeepConflictPrimary(conflict); err != nil {
+		return err
+	}
+	return fs.signWrite(chain, conflict.Primary, entry, []byte(content), previous)
+}
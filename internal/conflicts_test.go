@@ -0,0 +1,205 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const conflictEntryText = "## Monday 2024-02-09 02:30 PM UTC\n\n"
+
+// setupConflict saves an entry and writes a conflict copy with a different body next to it
+func setupConflict(t *testing.T, copyName string) (*FileSystemStorage, string, ConflictCopy) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2024, 2, 9, 14, 30, 0, 0, time.UTC), Body: "Original text."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	writeTestFile(t, tmpDir, filepath.Join("2024", "02", copyName), conflictEntryText+"Edited on the laptop.\n")
+
+	copies, err := storage.FindConflictCopies()
+	if err != nil {
+		t.Fatalf("FindConflictCopies() error = %v", err)
+	}
+	if len(copies) != 1 {
+		t.Fatalf("FindConflictCopies() = %+v, want 1 copy", copies)
+	}
+	return storage, tmpDir, copies[0]
+}
+
+func readBody(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	entry, err := ParseEntry(string(content))
+	if err != nil {
+		t.Fatalf("ParseEntry() error = %v", err)
+	}
+	return entry.Body
+}
+
+func TestConflictCopyPrimary(t *testing.T) {
+	tests := []struct {
+		name    string
+		primary string
+		ok      bool
+	}{
+		{"2024-02-09-14-30-00.sync-conflict-20240210-101500-ABCDEF1.md", "2024-02-09-14-30-00.md", true},
+		{"2024-02-09-14-30-00 (conflicted copy).md", "2024-02-09-14-30-00.md", true},
+		{"2024-02-09-14-30-00 (Jane's conflicted copy 2024-02-10).md", "2024-02-09-14-30-00.md", true},
+		{"2024-02-09-14-30-00-01_my-title.sync-conflict-20240210.md", "2024-02-09-14-30-00-01_my-title.md", true},
+		{"2024-02-09-14-30-00.md", "", false},
+		{"2024-02-09-14-30-00_conflicted-copy.md", "", false},
+		{"notes (copy).md", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, ok := conflictCopyPrimary(tt.name)
+			if ok != tt.ok || primary != tt.primary {
+				t.Errorf("conflictCopyPrimary(%q) = %q, %v; want %q, %v", tt.name, primary, ok, tt.primary, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFindConflictCopies(t *testing.T) {
+	storage, tmpDir, conflict := setupConflict(t, "2024-02-09-14-30-00 (conflicted copy).md")
+
+	wantPrimary := filepath.Join(tmpDir, "2024", "02", "2024-02-09-14-30-00.md")
+	if conflict.Primary != wantPrimary {
+		t.Errorf("Primary = %q, want %q", conflict.Primary, wantPrimary)
+	}
+
+	// Conflict copies are not listed as entries
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Body != "Original text." {
+		t.Errorf("ListEntries() = %+v, want only the original entry", entries)
+	}
+}
+
+func TestFindConflictCopies_PrimaryMissing(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+	writeTestFile(t, tmpDir, filepath.Join("2024", "02", "2024-02-09-14-30-00.sync-conflict-20240210.md"), conflictEntryText+"Orphan.\n")
+
+	copies, err := storage.FindConflictCopies()
+	if err != nil {
+		t.Fatalf("FindConflictCopies() error = %v", err)
+	}
+	if len(copies) != 1 || copies[0].Primary != "" {
+		t.Fatalf("FindConflictCopies() = %+v, want 1 copy without primary", copies)
+	}
+
+	// Keeping the copy makes it a regular entry
+	path, err := storage.KeepConflictCopy(copies[0])
+	if err != nil {
+		t.Fatalf("KeepConflictCopy() error = %v", err)
+	}
+	if filepath.Base(path) != "2024-02-09-14-30-00.md" {
+		t.Errorf("KeepConflictCopy() path = %q", path)
+	}
+	if got := readBody(t, path); got != "Orphan." {
+		t.Errorf("Body = %q, want %q", got, "Orphan.")
+	}
+}
+
+func TestKeepConflictPrimary(t *testing.T) {
+	storage, _, conflict := setupConflict(t, "2024-02-09-14-30-00.sync-conflict-20240210.md")
+
+	if err := storage.KeepConflictPrimary(conflict); err != nil {
+		t.Fatalf("KeepConflictPrimary() error = %v", err)
+	}
+	if _, err := os.Stat(conflict.Path); !os.IsNotExist(err) {
+		t.Errorf("Conflict copy still exists")
+	}
+	if got := readBody(t, conflict.Primary); got != "Original text." {
+		t.Errorf("Body = %q, want original", got)
+	}
+}
+
+func TestKeepConflictCopy(t *testing.T) {
+	storage, _, conflict := setupConflict(t, "2024-02-09-14-30-00.sync-conflict-20240210.md")
+
+	path, err := storage.KeepConflictCopy(conflict)
+	if err != nil {
+		t.Fatalf("KeepConflictCopy() error = %v", err)
+	}
+	if path != conflict.Primary {
+		t.Errorf("KeepConflictCopy() path = %q, want %q", path, conflict.Primary)
+	}
+	if _, err := os.Stat(conflict.Path); !os.IsNotExist(err) {
+		t.Errorf("Conflict copy still exists")
+	}
+	if got := readBody(t, conflict.Primary); got != "Edited on the laptop." {
+		t.Errorf("Body = %q, want the copy's body", got)
+	}
+}
+
+func TestKeepConflictBoth(t *testing.T) {
+	storage, _, conflict := setupConflict(t, "2024-02-09-14-30-00 (conflicted copy).md")
+
+	path, err := storage.KeepConflictBoth(conflict)
+	if err != nil {
+		t.Fatalf("KeepConflictBoth() error = %v", err)
+	}
+	if filepath.Base(path) != "2024-02-09-14-30-00-01.md" {
+		t.Errorf("KeepConflictBoth() path = %q, want collision suffix", path)
+	}
+
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("ListEntries() returned %d entries, want 2", len(entries))
+	}
+}
+
+func TestResolveConflictWith(t *testing.T) {
+	storage, _, conflict := setupConflict(t, "2024-02-09-14-30-00 (conflicted copy).md")
+
+	// Markers left in place are rejected and nothing changes
+	marked := conflictEntryText + MarkConflicts("Original text.\n", "Edited on the laptop.\n")
+	if err := storage.ResolveConflictWith(conflict, marked); err == nil {
+		t.Fatal("ResolveConflictWith() with markers: expected error")
+	}
+	if _, err := os.Stat(conflict.Path); err != nil {
+		t.Errorf("Conflict copy removed after failed resolution: %v", err)
+	}
+
+	merged := conflictEntryText + "Original text.\nEdited on the laptop.\n"
+	if err := storage.ResolveConflictWith(conflict, merged); err != nil {
+		t.Fatalf("ResolveConflictWith() error = %v", err)
+	}
+	if _, err := os.Stat(conflict.Path); !os.IsNotExist(err) {
+		t.Errorf("Conflict copy still exists")
+	}
+	if got := readBody(t, conflict.Primary); !strings.Contains(got, "Edited on the laptop.") || !strings.Contains(got, "Original text.") {
+		t.Errorf("Body = %q, want merged text", got)
+	}
+}
+
+func TestCheckIntegrity_ReportsSyncConflicts(t *testing.T) {
+	storage, _, conflict := setupConflict(t, "2024-02-09-14-30-00.sync-conflict-20240210.md")
+
+	report, err := storage.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity() error = %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Kind != IssueSyncConflict || report.Issues[0].Path != conflict.Path {
+		t.Fatalf("Issues = %+v, want one sync conflict", report.Issues)
+	}
+	if report.Issues[0].Fixable {
+		t.Errorf("Sync conflicts should not be fixable automatically")
+	}
+}
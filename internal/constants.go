@@ -38,6 +38,63 @@ const (
 	// FileTimestampFormat is the format for entry filenames (UTC)
 	// Format: 2006-01-02-15-04-05
 	FileTimestampFormat = "2006-01-02 15:04:05"
+
+	// FileNameTimestampLayout is the timestamp part of entry filenames (UTC)
+	// Format: 2006-01-02-15-04-05
+	FileNameTimestampLayout = "2006-01-02-15-04-05"
+)
+
+// Storage layout
+const (
+	// LayoutStateFile records the layout of a storage directory (and any migration in progress)
+	LayoutStateFile = ".layout.json"
+
+	// MaxSlugLength is the maximum length of the title slug in slug layout filenames
+	MaxSlugLength = 50
+)
+
+// Drafts
+const (
+	// DraftsDirName is the directory inside the storage path that holds unsaved editor drafts
+	DraftsDirName = ".drafts"
+
+	// DraftIDFormat is the format for draft IDs (local creation time)
+	// Format: 20060102-150405
+	DraftIDFormat = "20060102-150405"
+)
+
+// Directory sync
+const (
+	// SyncStateFile records the state of directory syncs (last synced hashes and tombstones)
+	SyncStateFile = ".sync-state.json"
+)
+
+// Encryption
+const (
+	// EncryptionStateFile records the key derivation parameters of an encrypted journal
+	EncryptionStateFile = ".encryption.json"
+)
+
+// Signing
+const (
+	// ChainFile is the hash chain manifest of a journal with signed entries
+	ChainFile = ".chain.jsonl"
+)
+
+// Project journals
+const (
+	// ProjectDirName is the directory of a project journal, found by walking up from the
+	// working directory
+	ProjectDirName = ".jrnlg"
+
+	// ProjectConfigFile is the config file inside a project journal
+	ProjectConfigFile = "config.toml"
+)
+
+// Imports
+const (
+	// StarredTag is the tag of imported entries that were starred (jrnl) or favorites
+	StarredTag = "starred"
 )
 
 // File extensions
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dayOneExport is a journal of a Day One JSON export
+type dayOneExport struct {
+	Entries []struct {
+		UUID         string   `json:"uuid"`
+		CreationDate string   `json:"creationDate"`
+		TimeZone     string   `json:"timeZone"`
+		Text         string   `json:"text"`
+		Tags         []string `json:"tags"`
+		Starred      bool     `json:"starred"`
+		Photos       []struct {
+			Identifier string `json:"identifier"`
+			MD5        string `json:"md5"`
+			Type       string `json:"type"`
+		} `json:"photos"`
+	} `json:"entries"`
+}
+
+// dayOneMoment matches a photo in the text of a Day One entry: ![](dayone-moment://ID)
+var dayOneMoment = regexp.MustCompile(`!\[([^\]]*)\]\(dayone-moment://([0-9A-Fa-f]+)\)`)
+
+// ParseDayOneExport reads the entries of a Day One JSON export: the zip archive, with a
+// JSON file per journal and a photos folder, or one of its JSON files. Entries keep the
+// time zone they were written in, and starred entries get a #starred tag.
+//
+// jrnlg has no attachments, so photos aren't imported: they are listed as the entry's
+// attachments, and the entry text links to them as photos/<md5>.<type>, where they are
+// in the archive.
+func ParseDayOneExport(content []byte) ([]ImportItem, error) {
+	if !bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		return parseDayOneJournal("", content)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+	var files []*zip.File
+	for _, file := range archive.File {
+		if path.Dir(file.Name) == "." && strings.EqualFold(path.Ext(file.Name), ".json") {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("not a Day One export: no journal JSON files")
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	var items []ImportItem
+	for _, file := range files {
+		journal, err := readZipFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		journalItems, err := parseDayOneJournal(file.Name+" ", journal)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		items = append(items, journalItems...)
+	}
+	return items, nil
+}
+
+// readZipFile returns the content of a file in a zip archive
+func readZipFile(file *zip.File) ([]byte, error) {
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
+
+// parseDayOneJournal reads the entries of a Day One journal JSON file. Sources start
+// with prefix, to tell the journals of an archive apart.
+func parseDayOneJournal(prefix string, content []byte) ([]ImportItem, error) {
+	var export dayOneExport
+	if err := json.Unmarshal(content, &export); err != nil {
+		return nil, fmt.Errorf("invalid Day One JSON export: %w", err)
+	}
+	if export.Entries == nil {
+		return nil, fmt.Errorf("not a Day One JSON export: no entries")
+	}
+
+	items := make([]ImportItem, len(export.Entries))
+	for i, e := range export.Entries {
+		source := fmt.Sprintf("%sentry %d", prefix, i+1)
+		timestamp, err := time.Parse(time.RFC3339, e.CreationDate)
+		if err != nil {
+			items[i] = failedImport(source, fmt.Errorf("invalid creationDate %q", e.CreationDate))
+			continue
+		}
+		location, err := time.LoadLocation(e.TimeZone)
+		if e.TimeZone == "" || err != nil {
+			location = time.Local
+		}
+		timestamp = timestamp.In(location)
+
+		// Photos link to where they are in the archive
+		photos := make(map[string]string, len(e.Photos))
+		attachments := make([]string, 0, len(e.Photos))
+		for _, photo := range e.Photos {
+			file := "photos/" + photo.MD5 + "." + photo.Type
+			photos[photo.Identifier] = file
+			attachments = append(attachments, file)
+		}
+		body := dayOneMoment.ReplaceAllStringFunc(e.Text, func(moment string) string {
+			match := dayOneMoment.FindStringSubmatch(moment)
+			if file, ok := photos[match[2]]; ok {
+				return "![" + match[1] + "](" + file + ")"
+			}
+			return moment
+		})
+
+		tags := make([]string, 0, len(e.Tags)+1)
+		for _, name := range e.Tags {
+			tags = append(tags, tagFromName(name))
+		}
+		if e.Starred {
+			tags = append(tags, "#"+StarredTag)
+		}
+
+		items[i] = importedEntry(source, timestamp, appendTags(unescapeDayOne(body), tags))
+		items[i].Attachments = attachments
+	}
+	return items, nil
+}
+
+// unescapeDayOne removes the backslashes Day One puts before Markdown punctuation in
+// entry text, e.g. "Hello\!" or "\#tag"
+func unescapeDayOne(text string) string {
+	return dayOneEscape.ReplaceAllString(text, "$1")
+}
+
+// dayOneEscape matches a backslash-escaped Markdown character
+var dayOneEscape = regexp.MustCompile(`\\([\\!#*+\-.()\[\]_{}<>|` + "`" + `])`)
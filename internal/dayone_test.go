@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const dayOneJournal = `{
+  "metadata": {"version": "1.0"},
+  "entries": [
+    {
+      "uuid": "A1",
+      "creationDate": "2024-02-09T22:30:00Z",
+      "timeZone": "Asia/Tokyo",
+      "text": "Ramen night\\! ![](dayone-moment://F00D)\n\nBest in town.",
+      "tags": ["food", "Road Trip"],
+      "starred": true,
+      "photos": [{"identifier": "F00D", "md5": "abc123", "type": "jpeg"}]
+    },
+    {
+      "uuid": "A2",
+      "creationDate": "2024-02-10T08:00:00Z",
+      "timeZone": "Not/AZone",
+      "text": "Morning pages"
+    },
+    {
+      "uuid": "A3",
+      "creationDate": "yesterday",
+      "text": "Lost date"
+    }
+  ]
+}`
+
+func TestParseDayOneExport(t *testing.T) {
+	items, err := ParseDayOneExport([]byte(dayOneJournal))
+	if err != nil {
+		t.Fatalf("ParseDayOneExport() error = %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3", len(items))
+	}
+
+	// The entry keeps the time zone it was written in
+	first := items[0]
+	if first.Action != ImportNew {
+		t.Fatalf("entry 1: %v", first.Err)
+	}
+	if got := FormatTimestamp(first.Entry.Timestamp); got != "Saturday 2024-02-10 7:30 AM JST" {
+		t.Errorf("entry 1 header = %q", got)
+	}
+	wantBody := "Ramen night! ![](photos/abc123.jpeg)\n\nBest in town.\n\n#food #Road-Trip #starred"
+	if first.Entry.Body != wantBody {
+		t.Errorf("entry 1 body = %q, want %q", first.Entry.Body, wantBody)
+	}
+	if want := []string{"food", "road-trip", "starred"}; !reflect.DeepEqual(first.Entry.Tags, want) {
+		t.Errorf("entry 1 tags = %v, want %v", first.Entry.Tags, want)
+	}
+	if want := []string{"photos/abc123.jpeg"}; !reflect.DeepEqual(first.Attachments, want) {
+		t.Errorf("entry 1 attachments = %v, want %v", first.Attachments, want)
+	}
+
+	// An unknown time zone falls back to local time
+	if got := items[1].Entry.Timestamp; !got.Equal(time.Date(2024, 2, 10, 8, 0, 0, 0, time.UTC)) || got.Location() != time.Local {
+		t.Errorf("entry 2 timestamp = %v", got)
+	}
+	if items[2].Action != ImportFailed || items[2].Source != "entry 3" {
+		t.Errorf("entry 3 = %+v, want failed", items[2])
+	}
+
+	if _, err := ParseDayOneExport([]byte(`{"metadata": {}}`)); err == nil {
+		t.Error("ParseDayOneExport() without entries: expected error")
+	}
+}
+
+func TestParseDayOneExport_Zip(t *testing.T) {
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	files := map[string]string{
+		"Journal.json":       dayOneJournal,
+		"Travel.json":        `{"entries": [{"creationDate": "2023-07-01T12:00:00Z", "timeZone": "UTC", "text": "Lisbon"}]}`,
+		"photos/abc123.jpeg": "not really a photo",
+		"photos/meta.json":   "{}",
+	}
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	items, err := ParseDayOneExport(archive.Bytes())
+	if err != nil {
+		t.Fatalf("ParseDayOneExport() error = %v", err)
+	}
+	if len(items) != 4 || items[0].Source != "Journal.json entry 1" || items[3].Source != "Travel.json entry 1" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestImportEntries_DayOneTimeZone(t *testing.T) {
+	// Headers with a time zone abbreviation unknown here are still found as duplicates
+	storage := NewFileSystemStorage(t.TempDir(), DefaultConfig())
+	items, err := ParseDayOneExport([]byte(dayOneJournal))
+	if err != nil {
+		t.Fatalf("ParseDayOneExport() error = %v", err)
+	}
+	if _, err := ImportEntries(storage, items, false); err != nil {
+		t.Fatalf("ImportEntries() error = %v", err)
+	}
+	result, err := ImportEntries(storage, items, false)
+	if err != nil {
+		t.Fatalf("ImportEntries() again error = %v", err)
+	}
+	if result.Count(ImportNew) != 0 || result.Count(ImportDuplicate) != 2 {
+		var actions []string
+		for _, item := range result.Items {
+			actions = append(actions, string(item.Action))
+		}
+		t.Errorf("second import actions = %s", strings.Join(actions, ", "))
+	}
+}
@@ -0,0 +1,318 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IssueKind identifies a category of storage integrity problem
+type IssueKind string
+
+const (
+	IssueUnparsable        IssueKind = "unparsable"         // File can't be parsed as an entry
+	IssueWrongDirectory    IssueKind = "wrong-directory"    // Entry is not in the directory its layout expects
+	IssueTimestampMismatch IssueKind = "timestamp-mismatch" // Filename doesn't match the header timestamp
+	IssueStrayTempFile     IssueKind = "stray-temp-file"    // Leftover temp file from an interrupted write
+	IssueClutter           IssueKind = "clutter"            // File that isn't a journal entry
+	IssueDuplicate         IssueKind = "duplicate"          // Same timestamp and body as another entry
+	IssueSyncConflict      IssueKind = "sync-conflict"      // Conflict copy left by a file sync tool
+)
+
+// Issue describes a single problem found in the storage tree
+type Issue struct {
+	Kind    IssueKind
+	Path    string // File with the problem
+	Detail  string // Human-readable explanation
+	Target  string // Where the fix moves the file ("" if the fix removes it)
+	Fixable bool   // Whether RepairIssues can fix it safely
+}
+
+// IntegrityReport is the result of checking the storage tree
+type IntegrityReport struct {
+	FilesScanned int
+	Issues       []Issue
+}
+
+// Fixable returns the issues that can be repaired automatically
+func (r *IntegrityReport) Fixable() []Issue {
+	var fixable []Issue
+	for _, issue := range r.Issues {
+		if issue.Fixable {
+			fixable = append(fixable, issue)
+		}
+	}
+	return fixable
+}
+
+// CheckIntegrity walks the storage tree and reports problems that ListEntries skips silently
+// Files and directories starting with "." (drafts, version control, layout state) are not
+// checked, except temp files left by interrupted writes. Year archives aren't checked either.
+func (fs *FileSystemStorage) CheckIntegrity() (*IntegrityReport, error) {
+	if err := fs.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
+	report := &IntegrityReport{}
+
+	// Parsed entries grouped by timestamp+body for duplicate detection
+	type parsed struct {
+		path  string
+		entry *JournalEntry
+	}
+	byContent := make(map[string][]parsed)
+	locationIssues := make(map[string]Issue)
+
+	err := filepath.WalkDir(fs.basePath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == fs.basePath && os.IsNotExist(err) {
+				return filepath.SkipAll // Nothing stored yet
+			}
+			return err
+		}
+
+		name := d.Name()
+		if d.IsDir() {
+			if path != fs.basePath && strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Temp files left by writeAtomic
+		if strings.HasPrefix(name, tempFilePrefix) {
+			report.FilesScanned++
+			report.Issues = append(report.Issues, fs.checkTempFile(path))
+			return nil
+		}
+		if strings.HasPrefix(name, ".") || fs.isYearArchive(path) {
+			return nil
+		}
+		// Project journals keep their config file next to the entries
+		if path == filepath.Join(fs.basePath, ProjectConfigFile) {
+			return nil
+		}
+
+		report.FilesScanned++
+
+		if !isMarkdownFile(name) {
+			report.Issues = append(report.Issues, Issue{
+				Kind:   IssueClutter,
+				Path:   path,
+				Detail: "not a journal entry (only .md files are read)",
+			})
+			return nil
+		}
+
+		if isConflictCopy(name) {
+			report.Issues = append(report.Issues, Issue{
+				Kind:   IssueSyncConflict,
+				Path:   path,
+				Detail: "conflict copy left by a file sync tool (resolve with 'jrnlg conflicts')",
+			})
+			return nil
+		}
+
+		entry, err := fs.parseFile(path)
+		if err != nil {
+			if inner := errors.Unwrap(err); inner != nil {
+				err = inner
+			}
+			report.Issues = append(report.Issues, Issue{
+				Kind:   IssueUnparsable,
+				Path:   path,
+				Detail: err.Error(),
+			})
+			return nil
+		}
+
+		if issue, ok := fs.checkLocation(path, entry); ok {
+			locationIssues[path] = issue
+		}
+
+		key := entry.Timestamp.UTC().Format(FileTimestampFormat) + "\x00" + entry.Body
+		byContent[key] = append(byContent[key], parsed{path: path, entry: entry})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan storage: %w", err)
+	}
+
+	// Duplicates: keep the copy at the canonical path (or the first one), report the rest
+	for _, group := range byContent {
+		if len(group) < 2 {
+			continue
+		}
+
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].path < group[j].path
+		})
+
+		keep := 0
+		canonical := fs.buildFilePath(group[0].entry.Timestamp)
+		for i, p := range group {
+			if p.path == canonical {
+				keep = i
+			}
+		}
+
+		for i, p := range group {
+			if i == keep {
+				continue
+			}
+			report.Issues = append(report.Issues, Issue{
+				Kind:    IssueDuplicate,
+				Path:    p.path,
+				Detail:  "same timestamp and content as " + fs.relativePath(group[keep].path),
+				Fixable: true,
+			})
+			delete(locationIssues, p.path) // Removing the duplicate fixes its location too
+		}
+	}
+
+	for _, issue := range locationIssues {
+		report.Issues = append(report.Issues, issue)
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Kind != report.Issues[j].Kind {
+			return report.Issues[i].Kind < report.Issues[j].Kind
+		}
+		return report.Issues[i].Path < report.Issues[j].Path
+	})
+
+	return report, nil
+}
+
+// checkLocation compares an entry's path with the path its layout derives from the header timestamp
+func (fs *FileSystemStorage) checkLocation(path string, entry *JournalEntry) (Issue, bool) {
+	ts, collision, ok := parseEntryFileName(filepath.Base(path))
+	if !ok || ts != entry.Timestamp.UTC().Format(FileNameTimestampLayout) {
+		expected := fs.freeEntryPath(fs.layout, entry, path, nil)
+		return Issue{
+			Kind: IssueTimestampMismatch,
+			Path: path,
+			Detail: fmt.Sprintf("filename doesn't match header timestamp %s (expected %s)",
+				FormatTimestamp(entry.Timestamp), filepath.Base(expected)),
+			Target:  expected,
+			Fixable: expected != "",
+		}, true
+	}
+
+	expectedDir := fs.layout.dir(fs.basePath, entry.Timestamp)
+	if filepath.Dir(path) != expectedDir {
+		return Issue{
+			Kind:    IssueWrongDirectory,
+			Path:    path,
+			Detail:  "should be in " + fs.relativePath(expectedDir),
+			Target:  filepath.Join(expectedDir, fs.layout.fileName(entry.Timestamp, collision, entry.Body)),
+			Fixable: true,
+		}, true
+	}
+
+	return Issue{}, false
+}
+
+// checkTempFile decides whether a leftover temp file can be cleaned up safely
+func (fs *FileSystemStorage) checkTempFile(path string) Issue {
+	issue := Issue{
+		Kind:   IssueStrayTempFile,
+		Path:   path,
+		Detail: "left behind by an interrupted write",
+	}
+
+	target := filepath.Join(filepath.Dir(path), strings.TrimPrefix(filepath.Base(path), tempFilePrefix))
+	tempContent, err := fs.readEntryFile(path)
+	if err != nil {
+		return issue
+	}
+
+	targetContent, err := fs.readEntryFile(target)
+	switch {
+	case err == nil && bytes.Equal(tempContent, targetContent):
+		// The write completed; the temp copy is redundant
+		issue.Detail += " (identical to " + filepath.Base(target) + ")"
+		issue.Fixable = true
+	case os.IsNotExist(err):
+		// The write never completed; restore the entry if the temp copy is valid
+		if _, parseErr := ParseEntry(string(tempContent)); parseErr == nil {
+			issue.Detail += " (entry was never written; can be restored)"
+			issue.Target = target
+			issue.Fixable = true
+		}
+	default:
+		issue.Detail += " (differs from " + filepath.Base(target) + "; review manually)"
+	}
+
+	return issue
+}
+
+// RepairIssues fixes the given issues where it is safe to do so
+// Moves never overwrite existing files; duplicates and redundant temp files are removed
+// Returns the issues that were fixed, with the Target files were actually moved to
+func (fs *FileSystemStorage) RepairIssues(issues []Issue) ([]Issue, error) {
+	var fixed []Issue
+	var errs []error
+
+	for _, issue := range issues {
+		if !issue.Fixable {
+			continue
+		}
+
+		var err error
+		if issue.Target == "" {
+			err = fs.removeFile(issue.Path)
+		} else {
+			issue.Target, err = fs.moveEntryFile(issue.Path, issue.Target)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fix %s: %w", fs.relativePath(issue.Path), err))
+			continue
+		}
+		fixed = append(fixed, issue)
+	}
+
+	if len(fixed) > 0 {
+		fs.InvalidateIndex()
+	}
+
+	if len(errs) > 0 {
+		return fixed, errors.Join(errs...)
+	}
+	return fixed, nil
+}
+
+// moveEntryFile moves an entry file to target, picking another collision slot if target is taken
+// Returns the path the file was moved to
+func (fs *FileSystemStorage) moveEntryFile(source, target string) (string, error) {
+	if _, err := os.Stat(target); err == nil {
+		entry, err := fs.parseFile(source)
+		if err != nil {
+			return "", err
+		}
+		target = fs.freeEntryPath(fs.layout, entry, source, nil)
+		if target == "" {
+			return "", fmt.Errorf("too many entries with same timestamp")
+		}
+	}
+
+	if err := fs.ensureDirectories(target); err != nil {
+		return "", err
+	}
+
+	return target, fs.renameFile(source, target)
+}
+
+// relativePath returns path relative to the storage root, for display
+func (fs *FileSystemStorage) relativePath(path string) string {
+	rel, err := filepath.Rel(fs.basePath, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
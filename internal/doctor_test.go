@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestFile writes content to path under dir, creating directories as needed
+func writeTestFile(t *testing.T, dir, path, content string) string {
+	t.Helper()
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), DirPermissions); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return full
+}
+
+func issueKinds(report *IntegrityReport) map[IssueKind]int {
+	kinds := make(map[IssueKind]int)
+	for _, issue := range report.Issues {
+		kinds[issue.Kind]++
+	}
+	return kinds
+}
+
+func TestCheckIntegrity_Clean(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC), Body: "Fine."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	// Drafts are not part of the check
+	writeTestFile(t, tmpDir, filepath.Join(DraftsDirName, "draft.md"), "not an entry")
+
+	report, err := storage.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity() error = %v", err)
+	}
+	if report.FilesScanned != 1 {
+		t.Errorf("FilesScanned = %d, want 1", report.FilesScanned)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", report.Issues)
+	}
+}
+
+func TestCheckIntegrity_MissingStorage(t *testing.T) {
+	storage := NewFileSystemStorage(filepath.Join(t.TempDir(), "missing"), nil)
+
+	report, err := storage.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity() error = %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", report.Issues)
+	}
+}
+
+func TestCheckIntegrity_ProjectJournal(t *testing.T) {
+	project, err := InitProjectJournal(t.TempDir())
+	if err != nil {
+		t.Fatalf("InitProjectJournal() error = %v", err)
+	}
+	storage := NewFileSystemStorage(project, nil)
+
+	// A fresh project journal only holds its config file
+	report, err := storage.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity() error = %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Expected no issues in a new project journal, got %+v", report.Issues)
+	}
+
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC), Body: "Fine."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	// Only the config file at the root is the project's; one among the entries is clutter
+	writeTestFile(t, project, filepath.Join("2026", ProjectConfigFile), "layout = \"flat\"\n")
+
+	report, err = storage.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity() error = %v", err)
+	}
+	if kinds := issueKinds(report); len(report.Issues) != 1 || kinds[IssueClutter] != 1 {
+		t.Errorf("Expected the nested config file as clutter, got %+v", report.Issues)
+	}
+}
+
+func TestCheckIntegrity_FindsAndFixesIssues(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+
+	entry := "## Monday 2026-02-09 2:30 PM UTC\n\nEntry body.\n"
+	other := "## Tuesday 2026-02-10 9:00 AM UTC\n\nOther body.\n"
+	third := "## Wednesday 2026-02-11 9:00 AM UTC\n\nThird body.\n"
+
+	canonical := writeTestFile(t, tmpDir, "2026/02/2026-02-09-14-30-00.md", entry)
+	duplicate := writeTestFile(t, tmpDir, "2026/02/2026-02-09-14-30-00-01.md", entry)
+	unparsable := writeTestFile(t, tmpDir, "2026/02/2026-02-12-10-00-00.md", "no header here")
+	wrongDir := writeTestFile(t, tmpDir, "2026/03/2026-02-10-09-00-00.md", other)
+	mismatch := writeTestFile(t, tmpDir, "2026/02/notes.md", third)
+	clutter := writeTestFile(t, tmpDir, "2026/02/photo.jpg", "binary")
+	redundantTemp := writeTestFile(t, tmpDir, "2026/02/.tmp-2026-02-09-14-30-00.md", entry)
+
+	report, err := storage.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity() error = %v", err)
+	}
+
+	kinds := issueKinds(report)
+	expected := map[IssueKind]int{
+		IssueDuplicate:         1,
+		IssueUnparsable:        1,
+		IssueWrongDirectory:    1,
+		IssueTimestampMismatch: 1,
+		IssueClutter:           1,
+		IssueStrayTempFile:     1,
+	}
+	for kind, count := range expected {
+		if kinds[kind] != count {
+			t.Errorf("%s issues = %d, want %d", kind, kinds[kind], count)
+		}
+	}
+
+	fixed, err := storage.RepairIssues(report.Fixable())
+	if err != nil {
+		t.Fatalf("RepairIssues() error = %v", err)
+	}
+	if len(fixed) != 4 {
+		t.Errorf("Fixed %d issues, want 4", len(fixed))
+	}
+
+	// Removed
+	for _, path := range []string{duplicate, redundantTemp, wrongDir, mismatch} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should no longer exist", path)
+		}
+	}
+
+	// Left alone
+	for _, path := range []string{canonical, unparsable, clutter} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should still exist: %v", path, err)
+		}
+	}
+
+	// Moved to canonical paths
+	for _, path := range []string{"2026/02/2026-02-10-09-00-00.md", "2026/02/2026-02-11-09-00-00.md"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, path)); err != nil {
+			t.Errorf("%s should exist after fix: %v", path, err)
+		}
+	}
+}
+
+func TestRepairIssues_ReportsActualTarget(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+
+	// Both belong at 2026/02/2026-02-10-09-00-00.md; the second move takes another slot
+	writeTestFile(t, tmpDir, "2026/03/2026-02-10-09-00-00.md", "## Tuesday 2026-02-10 9:00 AM UTC\n\nFirst.\n")
+	writeTestFile(t, tmpDir, "2026/04/2026-02-10-09-00-00.md", "## Tuesday 2026-02-10 9:00 AM UTC\n\nSecond.\n")
+
+	report, err := storage.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity() error = %v", err)
+	}
+	fixed, err := storage.RepairIssues(report.Fixable())
+	if err != nil {
+		t.Fatalf("RepairIssues() error = %v", err)
+	}
+	if len(fixed) != 2 {
+		t.Fatalf("Fixed %d issues, want 2", len(fixed))
+	}
+	targets := map[string]bool{}
+	for _, issue := range fixed {
+		if _, err := os.Stat(issue.Target); err != nil {
+			t.Errorf("%s was reported moved to %s: %v", issue.Path, issue.Target, err)
+		}
+		targets[issue.Target] = true
+	}
+	if len(targets) != 2 {
+		t.Errorf("fixed issues report the same target: %+v", fixed)
+	}
+}
+
+func TestCheckIntegrity_RestoresInterruptedWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+
+	writeTestFile(t, tmpDir, "2026/02/.tmp-2026-02-09-14-30-00.md", "## Monday 2026-02-09 2:30 PM UTC\n\nOnly copy.\n")
+
+	report, err := storage.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity() error = %v", err)
+	}
+	if _, err := storage.RepairIssues(report.Fixable()); err != nil {
+		t.Fatalf("RepairIssues() error = %v", err)
+	}
+
+	entry, err := storage.GetEntry(time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetEntry() error = %v", err)
+	}
+	if entry.Body != "Only copy." {
+		t.Errorf("Body = %q, want %q", entry.Body, "Only copy.")
+	}
+}
@@ -0,0 +1,274 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Draft describes editor text that has not been saved as an entry yet.
+// Drafts survive invalid entries and crashed or killed editor sessions.
+type Draft struct {
+	ID        string    `json:"-"`
+	Target    string    `json:"target,omitempty"`    // Entry file being edited ("" = new entry)
+	Base      string    `json:"base,omitempty"`      // Entry content when editing started
+	BaseHash  string    `json:"base_hash,omitempty"` // Entry version hash when editing started
+	Error     string    `json:"error,omitempty"`     // Last validation error, if any
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"-"` // Modification time of the draft text
+}
+
+// IsEdit reports whether the draft is an edit of an existing entry
+func (d *Draft) IsEdit() bool {
+	return d.Target != ""
+}
+
+// DraftStore keeps drafts in a directory: <id>.md holds the text, <id>.json the metadata
+type DraftStore struct {
+	dir   string
+	codec draftCodec // Encrypts drafts of encrypted journals (nil = plain text)
+}
+
+// draftCodec converts draft files between their stored and plain text form
+type draftCodec interface {
+	encode(plaintext []byte) ([]byte, error)
+	decode(content []byte) ([]byte, error)
+}
+
+// NewDraftStore creates a draft store rooted at dir
+func NewDraftStore(dir string) *DraftStore {
+	return &DraftStore{dir: dir}
+}
+
+// Drafts returns the draft store of the journal
+// Drafts of an encrypted journal are encrypted like its entries
+func (fs *FileSystemStorage) Drafts() *DraftStore {
+	return &DraftStore{dir: DraftsPath(fs.basePath), codec: fs}
+}
+
+// DraftsPath returns the drafts directory for a journal storage path
+func DraftsPath(storagePath string) string {
+	return filepath.Join(storagePath, DraftsDirName)
+}
+
+// Path returns the file path of the draft text, for opening in an editor
+func (ds *DraftStore) Path(id string) string {
+	return filepath.Join(ds.dir, id+MarkdownExt)
+}
+
+// metaPath returns the file path of the draft metadata
+func (ds *DraftStore) metaPath(id string) string {
+	return filepath.Join(ds.dir, id+".json")
+}
+
+// Create writes a new draft with the given content and metadata
+// The draft ID is derived from the current time
+func (ds *DraftStore) Create(content string, draft *Draft) error {
+	if err := os.MkdirAll(ds.dir, DirPermissions); err != nil {
+		return fmt.Errorf("failed to create drafts directory: %w", err)
+	}
+
+	draft.CreatedAt = time.Now()
+	base := draft.CreatedAt.Format(DraftIDFormat)
+
+	// Pick an unused ID (same-second drafts get a numeric suffix)
+	draft.ID = base
+	for i := 1; ; i++ {
+		if _, err := os.Stat(ds.Path(draft.ID)); os.IsNotExist(err) {
+			break
+		}
+		if i >= MaxCollisionAttempts {
+			return fmt.Errorf("too many drafts with the same timestamp")
+		}
+		draft.ID = fmt.Sprintf("%s-%02d", base, i)
+	}
+
+	if err := ds.WriteContent(draft.ID, content); err != nil {
+		return err
+	}
+	return ds.Update(draft)
+}
+
+// Update writes the draft metadata
+func (ds *DraftStore) Update(draft *Draft) error {
+	data, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode draft metadata: %w", err)
+	}
+	if err := ds.writeFile(ds.metaPath(draft.ID), data); err != nil {
+		return fmt.Errorf("failed to write draft metadata: %w", err)
+	}
+	return nil
+}
+
+// WriteContent replaces the draft text
+func (ds *DraftStore) WriteContent(id, content string) error {
+	if err := ds.writeFile(ds.Path(id), []byte(content)); err != nil {
+		return fmt.Errorf("failed to write draft: %w", err)
+	}
+	return nil
+}
+
+// writeFile writes a draft file, encoding it first
+func (ds *DraftStore) writeFile(path string, data []byte) error {
+	if ds.codec != nil {
+		var err error
+		if data, err = ds.codec.encode(data); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, FilePermissions)
+}
+
+// readFile reads and decodes a draft file
+func (ds *DraftStore) readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || ds.codec == nil {
+		return data, err
+	}
+	return ds.codec.decode(data)
+}
+
+// files returns the paths of all files in the drafts directory
+func (ds *DraftStore) files() []string {
+	dirEntries, err := os.ReadDir(ds.dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() {
+			files = append(files, filepath.Join(ds.dir, dirEntry.Name()))
+		}
+	}
+	return files
+}
+
+// ReadContent returns the draft text
+func (ds *DraftStore) ReadContent(id string) (string, error) {
+	content, err := ds.readFile(ds.Path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("draft not found: %s", id)
+		}
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Get loads a draft's metadata by ID
+// Drafts whose metadata is missing are treated as new entries
+func (ds *DraftStore) Get(id string) (*Draft, error) {
+	info, err := os.Stat(ds.Path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("draft not found: %s", id)
+		}
+		return nil, err
+	}
+
+	draft := &Draft{CreatedAt: info.ModTime()}
+	data, err := ds.readFile(ds.metaPath(id))
+	if err == nil {
+		if err := json.Unmarshal(data, draft); err != nil {
+			return nil, fmt.Errorf("invalid metadata for draft %s: %w", id, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	draft.ID = id
+	draft.UpdatedAt = info.ModTime()
+	return draft, nil
+}
+
+// List returns all drafts, oldest first
+func (ds *DraftStore) List() ([]*Draft, error) {
+	dirEntries, err := os.ReadDir(ds.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Draft{}, nil
+		}
+		return nil, fmt.Errorf("failed to read drafts directory: %w", err)
+	}
+
+	drafts := make([]*Draft, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !isMarkdownFile(dirEntry.Name()) {
+			continue
+		}
+
+		draft, err := ds.Get(strings.TrimSuffix(dirEntry.Name(), MarkdownExt))
+		if err != nil {
+			continue // Skip unreadable drafts
+		}
+		drafts = append(drafts, draft)
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].CreatedAt.Before(drafts[j].CreatedAt)
+	})
+
+	return drafts, nil
+}
+
+// Discard removes a draft and its metadata
+func (ds *DraftStore) Discard(id string) error {
+	if err := os.Remove(ds.Path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("draft not found: %s", id)
+		}
+		return fmt.Errorf("failed to discard draft: %w", err)
+	}
+
+	if err := os.Remove(ds.metaPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to discard draft metadata: %w", err)
+	}
+
+	return nil
+}
+
+// Draft notices are HTML comments inserted above the draft text to explain why it was reopened
+const (
+	draftNoticeStart = "<!-- jrnlg:"
+	draftNoticeEnd   = "-->"
+)
+
+// AddDraftNotice prepends an explanatory comment describing err to the draft content
+func AddDraftNotice(content string, err error) string {
+	var sb strings.Builder
+	sb.WriteString(draftNoticeStart)
+	sb.WriteString(" This entry could not be saved:\n")
+	for _, line := range strings.Split(err.Error(), "\n") {
+		sb.WriteString("     ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("     Fix the entry below and save, or delete everything to discard it.\n")
+	sb.WriteString("     This comment is removed automatically. ")
+	sb.WriteString(draftNoticeEnd)
+	sb.WriteString("\n")
+	sb.WriteString(StripDraftNotice(content))
+	return sb.String()
+}
+
+// StripDraftNotice removes a leading notice comment added by AddDraftNotice
+func StripDraftNotice(content string) string {
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+	if !strings.HasPrefix(trimmed, draftNoticeStart) {
+		return content
+	}
+
+	end := strings.Index(trimmed, draftNoticeEnd)
+	if end == -1 {
+		return content
+	}
+
+	return strings.TrimLeft(trimmed[end+len(draftNoticeEnd):], "\r\n")
+}
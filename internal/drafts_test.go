@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDraftStore_CreateGetList(t *testing.T) {
+	store := NewDraftStore(DraftsPath(t.TempDir()))
+
+	first := &Draft{}
+	if err := store.Create("## Monday 2026-02-09 2:30 PM UTC\n\nFirst draft", first); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	second := &Draft{Target: "/journal/2026/02/2026-02-09-14-30-00.md", Base: "base", BaseHash: "abc"}
+	if err := store.Create("Second draft", second); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if first.ID == second.ID {
+		t.Fatalf("Draft IDs should be unique, both are %q", first.ID)
+	}
+
+	got, err := store.Get(second.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !got.IsEdit() || got.Target != second.Target || got.Base != "base" || got.BaseHash != "abc" {
+		t.Errorf("Get() = %+v, want metadata of %+v", got, second)
+	}
+
+	content, err := store.ReadContent(first.ID)
+	if err != nil {
+		t.Fatalf("ReadContent() error = %v", err)
+	}
+	if !strings.Contains(content, "First draft") {
+		t.Errorf("ReadContent() = %q, want first draft text", content)
+	}
+
+	drafts, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(drafts) != 2 {
+		t.Fatalf("List() returned %d drafts, want 2", len(drafts))
+	}
+}
+
+func TestDraftStore_Discard(t *testing.T) {
+	store := NewDraftStore(DraftsPath(t.TempDir()))
+
+	draft := &Draft{}
+	if err := store.Create("text", draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := store.Discard(draft.ID); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+
+	if _, err := store.Get(draft.ID); err == nil {
+		t.Error("Get() should fail after Discard()")
+	}
+	if err := store.Discard(draft.ID); err == nil {
+		t.Error("Discard() of missing draft should fail")
+	}
+}
+
+func TestDraftStore_ListEmpty(t *testing.T) {
+	store := NewDraftStore(DraftsPath(t.TempDir()))
+
+	drafts, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(drafts) != 0 {
+		t.Errorf("List() returned %d drafts, want 0", len(drafts))
+	}
+}
+
+func TestDraftNotice(t *testing.T) {
+	content := "## Bad header\n\nMy text"
+	withNotice := AddDraftNotice(content, errors.New("invalid timestamp\nsecond line"))
+
+	if !strings.HasPrefix(withNotice, "<!-- jrnlg:") {
+		t.Errorf("AddDraftNotice() should start with notice comment, got %q", withNotice)
+	}
+	if !strings.Contains(withNotice, "second line") {
+		t.Error("AddDraftNotice() should include every line of the error")
+	}
+
+	if stripped := StripDraftNotice(withNotice); stripped != content {
+		t.Errorf("StripDraftNotice() = %q, want %q", stripped, content)
+	}
+
+	// Adding a notice twice replaces the old one
+	again := AddDraftNotice(withNotice, errors.New("other error"))
+	if strings.Count(again, "<!-- jrnlg:") != 1 {
+		t.Errorf("AddDraftNotice() should replace existing notice, got %q", again)
+	}
+
+	if StripDraftNotice(content) != content {
+		t.Error("StripDraftNotice() should leave content without a notice unchanged")
+	}
+}
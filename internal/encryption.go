@@ -0,0 +1,420 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	// ErrNotEncrypted is returned when decrypting a journal that isn't encrypted
+	ErrNotEncrypted = errors.New("journal is not encrypted")
+	// ErrWrongPassphrase is returned when the passphrase doesn't unlock the journal
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrPassphraseRequired is returned when an encrypted journal is used without a passphrase source
+	ErrPassphraseRequired = errors.New("journal is encrypted: a passphrase is required")
+)
+
+// PassphraseFunc supplies the passphrase of an encrypted journal (e.g. by prompting the user)
+// It is called at most once, the first time an encrypted file is read or written.
+type PassphraseFunc func() (string, error)
+
+// encryptedHeader starts every encrypted file; the rest is the base64-encoded nonce and
+// AES-256-GCM ciphertext. Files stay text, so sync tools and git handle them like entries.
+const encryptedHeader = "jrnlg-encrypted v1\n"
+
+// Key derivation parameters for new journals (scrypt, as recommended for interactive logins)
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	keyLength    = 32 // AES-256
+	saltLength   = 16
+	keyCheckText = "jrnlg"
+)
+
+// encryptionState is stored in the storage root of an encrypted journal. It holds what is
+// needed to derive the key from the passphrase, and a known text encrypted with the key to
+// tell a wrong passphrase from a damaged file.
+type encryptionState struct {
+	KDF   string `json:"kdf"`
+	Salt  []byte `json:"salt"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Check string `json:"check"`
+}
+
+// readEncryptionState loads the encryption state file, returning nil if the journal isn't encrypted
+func readEncryptionState(basePath string) (*encryptionState, error) {
+	data, err := os.ReadFile(filepath.Join(basePath, EncryptionStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var state encryptionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EncryptionStateFile, err)
+	}
+	if state.KDF != "scrypt" {
+		return nil, fmt.Errorf("invalid %s: unsupported key derivation %q", EncryptionStateFile, state.KDF)
+	}
+	return &state, nil
+}
+
+// deriveKey turns a passphrase into an AES-GCM cipher using the state's salt and parameters
+func (s *encryptionState) deriveKey(passphrase string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), s.Salt, s.N, s.R, s.P, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sameKey reports whether two states derive the same key from the same passphrase
+func (s *encryptionState) sameKey(other *encryptionState) bool {
+	return bytes.Equal(s.Salt, other.Salt) && s.N == other.N && s.R == other.R && s.P == other.P
+}
+
+// isEncrypted reports whether file content is encrypted
+func isEncrypted(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(encryptedHeader))
+}
+
+// seal encrypts plaintext with a fresh random nonce
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(encryptedHeader))
+	return []byte(encryptedHeader + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// open decrypts content produced by seal
+func open(aead cipher.AEAD, content []byte) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content[len(encryptedHeader):])))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("damaged encrypted file")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(encryptedHeader))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt: wrong passphrase or damaged file")
+	}
+	return plaintext, nil
+}
+
+// reloadEncryptionState rereads the key record (e.g. after a sync brought in a new one)
+func (fs *FileSystemStorage) reloadEncryptionState() {
+	state, err := readEncryptionState(fs.basePath)
+
+	fs.keyMu.Lock()
+	defer fs.keyMu.Unlock()
+	if err == nil && state != nil && fs.encryption != nil && state.sameKey(fs.encryption) {
+		return // Unchanged: keep the unlocked key
+	}
+	fs.encryption, fs.encryptionErr = state, err
+	fs.key, fs.unlockErr = nil, nil
+}
+
+// IsEncrypted reports whether the journal is encrypted
+func (fs *FileSystemStorage) IsEncrypted() bool {
+	return fs.encryption != nil || fs.encryptionErr != nil
+}
+
+// SetPassphraseFunc sets where the passphrase of an encrypted journal comes from
+func (fs *FileSystemStorage) SetPassphraseFunc(passphrase PassphraseFunc) {
+	fs.passphrase = passphrase
+}
+
+// unlock derives the key from the passphrase on first use
+// A failed attempt is remembered, so parallel reads don't ask again
+func (fs *FileSystemStorage) unlock() (cipher.AEAD, error) {
+	fs.keyMu.Lock()
+	defer fs.keyMu.Unlock()
+
+	if fs.encryptionErr != nil {
+		return nil, fs.encryptionErr
+	}
+	if fs.encryption == nil {
+		return nil, ErrNotEncrypted
+	}
+	if fs.key != nil || fs.unlockErr != nil {
+		return fs.key, fs.unlockErr
+	}
+	if fs.passphrase == nil {
+		return nil, ErrPassphraseRequired
+	}
+
+	passphrase, err := fs.passphrase()
+	if err != nil {
+		fs.unlockErr = err
+		return nil, err
+	}
+	fs.key, fs.unlockErr = fs.encryption.unlockWith(passphrase)
+	return fs.key, fs.unlockErr
+}
+
+// unlockWith derives the key from passphrase and verifies it against the check text
+func (s *encryptionState) unlockWith(passphrase string) (cipher.AEAD, error) {
+	aead, err := s.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := open(aead, []byte(s.Check)); err != nil || string(check) != keyCheckText {
+		return nil, ErrWrongPassphrase
+	}
+	return aead, nil
+}
+
+// ensureUnlocked asks for the passphrase of an encrypted journal up front, so a wrong
+// passphrase fails the operation instead of making every entry look invalid
+func (fs *FileSystemStorage) ensureUnlocked() error {
+	if !fs.IsEncrypted() {
+		return nil
+	}
+	_, err := fs.unlock()
+	return err
+}
+
+// decode returns the plaintext of file content, decrypting it if it is encrypted
+// Plain files are returned as they are, so a half-converted journal stays readable
+func (fs *FileSystemStorage) decode(content []byte) ([]byte, error) {
+	if !isEncrypted(content) {
+		return content, nil
+	}
+	aead, err := fs.unlock()
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, content)
+}
+
+// encode returns plaintext as it should be stored: encrypted if the journal is encrypted
+func (fs *FileSystemStorage) encode(plaintext []byte) ([]byte, error) {
+	if !fs.IsEncrypted() {
+		return plaintext, nil
+	}
+	aead, err := fs.unlock()
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, plaintext)
+}
+
+// readEntryFile reads an entry file and returns its plaintext
+func (fs *FileSystemStorage) readEntryFile(filePath string) ([]byte, error) {
+	content, err := fs.readStoredFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return fs.decode(content)
+}
+
+// writeEntryFile atomically writes an entry file, encrypting it if the journal is encrypted
+func (fs *FileSystemStorage) writeEntryFile(filePath string, plaintext []byte) error {
+	content, err := fs.encode(plaintext)
+	if err != nil {
+		return err
+	}
+	return fs.writeAtomic(filePath, content)
+}
+
+// ReadEntryText returns the text of an entry file (or conflict copy), decrypted if needed
+func (fs *FileSystemStorage) ReadEntryText(filePath string) (string, error) {
+	content, err := fs.readEntryFile(filePath)
+	return string(content), err
+}
+
+// Encrypt encrypts every entry and draft in place with a key derived from passphrase.
+// The key is recorded before any file is converted, so an interrupted run can be resumed
+// by running it again with the same passphrase. Returns the number of entries encrypted.
+func (fs *FileSystemStorage) Encrypt(passphrase string) (int, error) {
+	if fs.encryptionErr != nil {
+		return 0, fs.encryptionErr
+	}
+	if err := fs.checkNoArchives("encrypting"); err != nil {
+		return 0, err
+	}
+
+	if fs.encryption == nil {
+		if passphrase == "" {
+			return 0, fmt.Errorf("passphrase cannot be empty")
+		}
+		state, aead, err := newEncryptionState(passphrase)
+		if err != nil {
+			return 0, err
+		}
+		if err := fs.writeEncryptionState(state); err != nil {
+			return 0, err
+		}
+		fs.keyMu.Lock()
+		fs.key = aead
+		fs.keyMu.Unlock()
+	} else {
+		// Resuming: the passphrase must match the recorded key
+		aead, err := fs.encryption.unlockWith(passphrase)
+		if err != nil {
+			return 0, err
+		}
+		fs.keyMu.Lock()
+		fs.key, fs.unlockErr = aead, nil
+		fs.keyMu.Unlock()
+	}
+
+	return fs.convertFiles(func(content []byte) (bool, []byte, error) {
+		if isEncrypted(content) {
+			return false, nil, nil
+		}
+		sealed, err := seal(fs.key, content)
+		return true, sealed, err
+	})
+}
+
+// Decrypt stores every entry and draft as plain text again and removes the key record.
+// An interrupted run can be resumed by running it again. Returns the number of entries decrypted.
+func (fs *FileSystemStorage) Decrypt() (int, error) {
+	if !fs.IsEncrypted() {
+		return 0, ErrNotEncrypted
+	}
+	if err := fs.checkNoArchives("decrypting"); err != nil {
+		return 0, err
+	}
+	aead, err := fs.unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := fs.convertFiles(func(content []byte) (bool, []byte, error) {
+		if !isEncrypted(content) {
+			return false, nil, nil
+		}
+		plaintext, err := open(aead, content)
+		return true, plaintext, err
+	})
+	if err != nil {
+		return count, err
+	}
+
+	// Only forget the key once every file is plain text
+	if err := fs.removeFile(filepath.Join(fs.basePath, EncryptionStateFile)); err != nil {
+		return count, fmt.Errorf("failed to remove %s: %w", EncryptionStateFile, err)
+	}
+	fs.keyMu.Lock()
+	fs.encryption, fs.key, fs.unlockErr = nil, nil, nil
+	fs.keyMu.Unlock()
+
+	return count, nil
+}
+
+// newEncryptionState creates a key record with a random salt for passphrase
+func newEncryptionState(passphrase string) (*encryptionState, cipher.AEAD, error) {
+	state := &encryptionState{KDF: "scrypt", Salt: make([]byte, saltLength), N: scryptN, R: scryptR, P: scryptP}
+	if _, err := rand.Read(state.Salt); err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := state.deriveKey(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	check, err := seal(aead, []byte(keyCheckText))
+	if err != nil {
+		return nil, nil, err
+	}
+	state.Check = string(check)
+
+	return state, aead, nil
+}
+
+// writeEncryptionState records the key parameters in the storage root
+func (fs *FileSystemStorage) writeEncryptionState(state *encryptionState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(fs.basePath, DirPermissions); err != nil {
+		return err
+	}
+	if err := fs.writeAtomic(filepath.Join(fs.basePath, EncryptionStateFile), data); err != nil {
+		return fmt.Errorf("failed to record encryption key: %w", err)
+	}
+
+	fs.keyMu.Lock()
+	defer fs.keyMu.Unlock()
+	if fs.encryption == nil || !state.sameKey(fs.encryption) {
+		fs.encryption, fs.encryptionErr = state, nil
+		fs.key, fs.unlockErr = nil, nil
+	}
+	return nil
+}
+
+// convertFiles rewrites entry files, conflict copies and drafts with convert, which
+// reports whether a file needs rewriting. Returns the number of entry files rewritten.
+func (fs *FileSystemStorage) convertFiles(convert func([]byte) (bool, []byte, error)) (int, error) {
+	entries, err := fs.findAllFiles()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find files: %w", err)
+	}
+
+	conflicts, err := fs.FindConflictCopies()
+	if err != nil {
+		return 0, err
+	}
+	for _, conflict := range conflicts {
+		entries = append(entries, conflict.Path)
+	}
+	drafts := fs.Drafts().files()
+
+	count := 0
+	for i, path := range append(entries, drafts...) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return count, err
+		}
+
+		rewrite, converted, err := convert(content)
+		if err != nil {
+			return count, fmt.Errorf("%s: %w", path, err)
+		}
+		if !rewrite {
+			continue
+		}
+
+		if i >= len(entries) {
+			// Drafts are not part of the journal's history
+			if err := replaceFile(path, converted); err != nil {
+				return count, fmt.Errorf("failed to rewrite %s: %w", path, err)
+			}
+			continue
+		}
+
+		if err := fs.writeAtomic(path, converted); err != nil {
+			return count, fmt.Errorf("failed to rewrite %s: %w", path, err)
+		}
+		if isConflictCopy(filepath.Base(path)) {
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,202 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newEncryptedStorage creates a storage with one plain entry and encrypts it
+func newEncryptedStorage(t *testing.T, passphrase string) (*FileSystemStorage, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC), Body: "Met with @alice about #reviews."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	count, err := storage.Encrypt(passphrase)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Encrypt() = %d, want 1", count)
+	}
+	return storage, tmpDir
+}
+
+func TestEncrypt_EntriesEncryptedAtRest(t *testing.T) {
+	storage, tmpDir := newEncryptedStorage(t, "correct horse")
+
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC), Body: "Second #secret entry."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	files, err := storage.findAllFiles()
+	if err != nil {
+		t.Fatalf("findAllFiles() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("found %d files, want 2", len(files))
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if !isEncrypted(content) || strings.Contains(string(content), "#") {
+			t.Errorf("%s is not encrypted:\n%s", file, content)
+		}
+	}
+
+	// A new storage instance needs the passphrase
+	reopened := NewFileSystemStorage(tmpDir, nil)
+	if !reopened.IsEncrypted() {
+		t.Fatal("IsEncrypted() = false after Encrypt")
+	}
+	if _, err := reopened.ListEntries(EntryFilter{}); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("ListEntries() without passphrase error = %v, want ErrPassphraseRequired", err)
+	}
+
+	reopened = NewFileSystemStorage(tmpDir, nil)
+	reopened.SetPassphraseFunc(func() (string, error) { return "correct horse", nil })
+	entries, err := reopened.SearchByTags([]string{"secret"}, EntryFilter{})
+	if err != nil {
+		t.Fatalf("SearchByTags() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Body != "Second #secret entry." {
+		t.Errorf("SearchByTags() = %+v", entries)
+	}
+}
+
+func TestEncrypt_WrongPassphrase(t *testing.T) {
+	_, tmpDir := newEncryptedStorage(t, "correct horse")
+
+	calls := 0
+	storage := NewFileSystemStorage(tmpDir, nil)
+	storage.SetPassphraseFunc(func() (string, error) {
+		calls++
+		return "battery staple", nil
+	})
+
+	if _, err := storage.ListEntries(EntryFilter{}); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("ListEntries() error = %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := storage.ListEntries(EntryFilter{}); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("second ListEntries() error = %v, want ErrWrongPassphrase", err)
+	}
+	if calls != 1 {
+		t.Errorf("passphrase asked %d times, want 1", calls)
+	}
+
+	// Resuming an encryption also checks the passphrase
+	if _, err := storage.Encrypt("battery staple"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Encrypt() with wrong passphrase error = %v", err)
+	}
+}
+
+func TestEncrypt_ResumesInterruptedRun(t *testing.T) {
+	storage, tmpDir := newEncryptedStorage(t, "correct horse")
+
+	// A plain file left over from an interrupted run is still readable...
+	plain := writeTestFile(t, tmpDir, filepath.Join("2026", "02", "2026-02-11-08-00-00.md"),
+		"## Wednesday 2026-02-11 8:00 AM UTC\n\nNot yet encrypted.\n")
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("ListEntries() returned %d entries, want 2", len(entries))
+	}
+
+	// ...and encrypted by running again
+	count, err := NewFileSystemStorage(tmpDir, nil).Encrypt("correct horse")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Encrypt() = %d, want 1", count)
+	}
+	content, _ := os.ReadFile(plain)
+	if !isEncrypted(content) {
+		t.Error("leftover file was not encrypted")
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	storage, tmpDir := newEncryptedStorage(t, "correct horse")
+
+	draft := &Draft{}
+	if err := storage.Drafts().Create("## Monday 2026-02-09 2:30 PM UTC\n\nDraft text", draft); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	raw, _ := os.ReadFile(storage.Drafts().Path(draft.ID))
+	if !isEncrypted(raw) {
+		t.Error("draft of an encrypted journal is not encrypted")
+	}
+
+	reopened := NewFileSystemStorage(tmpDir, nil)
+	reopened.SetPassphraseFunc(func() (string, error) { return "correct horse", nil })
+	count, err := reopened.Decrypt()
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Decrypt() = %d, want 1", count)
+	}
+
+	if reopened.IsEncrypted() || NewFileSystemStorage(tmpDir, nil).IsEncrypted() {
+		t.Error("journal still encrypted after Decrypt")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, EncryptionStateFile)); !os.IsNotExist(err) {
+		t.Error("encryption state file not removed")
+	}
+
+	entry, err := NewFileSystemStorage(tmpDir, nil).GetEntry(time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetEntry() error = %v", err)
+	}
+	if entry.Body != "Met with @alice about #reviews." {
+		t.Errorf("Body = %q", entry.Body)
+	}
+	content, err := NewDraftStore(DraftsPath(tmpDir)).ReadContent(draft.ID)
+	if err != nil || !strings.Contains(content, "Draft text") {
+		t.Errorf("draft not decrypted: %q, %v", content, err)
+	}
+
+	if _, err := reopened.Decrypt(); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("Decrypt() of plain journal error = %v, want ErrNotEncrypted", err)
+	}
+}
+
+func TestSyncDir_Encryption(t *testing.T) {
+	storage, _ := newEncryptedStorage(t, "correct horse")
+
+	// An empty copy adopts the key; entries are copied encrypted
+	peerDir := t.TempDir()
+	if _, err := storage.SyncDir(NewFileSystemStorage(peerDir, nil)); err != nil {
+		t.Fatalf("SyncDir() error = %v", err)
+	}
+	peer := NewFileSystemStorage(peerDir, nil)
+	if !peer.IsEncrypted() {
+		t.Fatal("peer did not adopt the encryption key")
+	}
+	peer.SetPassphraseFunc(func() (string, error) { return "correct horse", nil })
+	entries, err := peer.ListEntries(EntryFilter{})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("peer ListEntries() = %d entries, %v", len(entries), err)
+	}
+
+	// A plain journal with entries can't be synced with an encrypted one
+	plainDir := t.TempDir()
+	plain := NewFileSystemStorage(plainDir, nil)
+	if err := plain.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), Body: "Plain."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	if _, err := storage.SyncDir(plain); err == nil {
+		t.Error("SyncDir() with a plain journal: expected error")
+	}
+}
@@ -1,6 +1,9 @@
 package internal
 
 import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -12,14 +15,41 @@ import (
 	"time"
 )
 
+// ErrEntryModified is returned when an entry file changed on disk after it was read
+var ErrEntryModified = errors.New("entry was modified by another process")
+
+// EntryVersion identifies the on-disk state of an entry file
+// Used to detect concurrent modification (e.g., by a sync tool or another terminal)
+type EntryVersion struct {
+	Hash    string    // SHA-256 of the file content
+	ModTime time.Time // Last modification time of the file
+}
+
+// tempFilePrefix is prepended to the filename of in-progress atomic writes
+const tempFilePrefix = ".tmp-"
+
 // FileSystemStorage implements journal entry storage using the filesystem
 type FileSystemStorage struct {
-	basePath  string
-	config    *Config
-	indexOnce sync.Once
-	index     *Index
-	indexErr  error
-	mu        sync.RWMutex
+	basePath    string
+	config      *Config
+	layout      Layout // Layout of entry files in basePath
+	migratingTo Layout // Target of an unfinished layout migration ("" = none)
+	indexOnce   sync.Once
+	index       *Index
+	indexErr    error
+	mu          sync.RWMutex
+	changes     map[string]bool // Files written or removed since the last TakeChanges
+	changesMu   sync.Mutex
+
+	encryption    *encryptionState // Key record of an encrypted journal (nil = not encrypted)
+	encryptionErr error            // Set if the key record can't be read
+	passphrase    PassphraseFunc
+	key           cipher.AEAD // Derived on first use
+	unlockErr     error
+	keyMu         sync.Mutex
+
+	archives   map[string]*yearArchive // Year archives read so far, by year
+	archivesMu sync.Mutex
 }
 
 // NewFileSystemStorage creates a new filesystem-based storage
@@ -27,62 +57,80 @@ func NewFileSystemStorage(basePath string, config *Config) *FileSystemStorage {
 	if config == nil {
 		config = DefaultConfig()
 	}
-	return &FileSystemStorage{
+	fs := &FileSystemStorage{
 		basePath: basePath,
 		config:   config,
+		layout:   config.Layout.orDefault(),
 	}
+
+	// A layout recorded in the storage directory wins over the configured default
+	if state, err := readLayoutState(basePath); err == nil && state != nil {
+		fs.layout = state.Layout.orDefault()
+		fs.migratingTo = state.MigratingTo
+	}
+
+	fs.encryption, fs.encryptionErr = readEncryptionState(basePath)
+
+	return fs
+}
+
+// Layout returns the layout of entry files in this storage
+func (fs *FileSystemStorage) Layout() Layout {
+	return fs.layout
 }
 
 // SaveEntry writes a journal entry to disk
-// The entry is stored in: <basePath>/<year>/<month>/YYYY-MM-DD-HH-MM-SS.md
+// The entry is stored according to the layout, by default in: <basePath>/<year>/<month>/YYYY-MM-DD-HH-MM-SS.md
 // Timestamp is converted to UTC for consistent file naming and sorting
 func (fs *FileSystemStorage) SaveEntry(entry *JournalEntry) error {
-	// Build file path (uses UTC for consistent naming)
-	filePath := fs.buildFilePath(entry.Timestamp)
-
-	// Handle collision - if file exists, append suffix
-	filePath = fs.findAvailablePath(filePath)
+	// Build file path (uses UTC for consistent naming, adds a suffix on collision)
+	filePath := fs.availableEntryPath(entry)
 	if filePath == "" {
 		return fmt.Errorf("too many entries with same timestamp")
 	}
+	if err := fs.checkWritable(filePath); err != nil {
+		return err
+	}
 
 	// Ensure directories exist
 	if err := fs.ensureDirectories(filePath); err != nil {
 		return fmt.Errorf("failed to create directories: %w", err)
 	}
 
+	// Record the layout, so the journal keeps it if the configured default changes
+	if err := fs.ensureLayoutState(); err != nil {
+		return err
+	}
+
+	// Make sure the entry can be signed before writing it
+	chain, err := fs.openChain()
+	if err != nil {
+		return err
+	}
+
 	// Serialize entry to markdown
 	markdown := SerializeEntry(entry)
 
 	// Write atomically (temp file + rename)
-	if err := fs.writeAtomic(filePath, []byte(markdown)); err != nil {
+	if err := fs.writeEntryFile(filePath, []byte(markdown)); err != nil {
 		return fmt.Errorf("failed to write entry: %w", err)
 	}
 
-	return nil
+	return fs.signWrite(chain, filePath, entry, []byte(markdown), "")
 }
 
 // GetEntry retrieves a journal entry by timestamp
 // Searches for files matching the timestamp (including collision suffixes)
 func (fs *FileSystemStorage) GetEntry(timestamp time.Time) (*JournalEntry, error) {
-	// Build expected file path
-	basePath := fs.buildFilePath(timestamp)
-
-	// Try base path first
-	if entry, err := fs.parseFile(basePath); err == nil {
-		return entry, nil
+	if err := fs.ensureUnlocked(); err != nil {
+		return nil, err
 	}
 
-	// Try with collision suffixes (-01, -02, etc.)
-	for i := 1; i < MaxCollisionAttempts; i++ {
-		path := fmt.Sprintf("%s-%02d%s", basePath[:len(basePath)-len(MarkdownExt)], i, MarkdownExt)
+	// Try base path first, then collision suffixes (-01, -02, etc.)
+	for _, path := range fs.findEntryFiles(timestamp) {
 		if entry, err := fs.parseFile(path); err == nil {
 			return entry, nil
 		}
-		// If file doesn't exist, stop trying
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			break
-		}
 	}
 
 	return nil, fmt.Errorf("entry not found: %s", timestamp.Format(FileTimestampFormat))
@@ -92,6 +140,10 @@ func (fs *FileSystemStorage) GetEntry(timestamp time.Time) (*JournalEntry, error
 // Entries are sorted by timestamp (oldest first)
 // Supports date range filtering, limit, and offset
 func (fs *FileSystemStorage) ListEntries(filter EntryFilter) ([]*JournalEntry, error) {
+	if err := fs.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
 	// Find all matching files
 	files, err := fs.findFiles(filter)
 	if err != nil {
@@ -152,29 +204,60 @@ func (fs *FileSystemStorage) ListEntries(filter EntryFilter) ([]*JournalEntry, e
 		fs.config.Logger.Warn("skipping invalid file during list", "error", err)
 	}
 
-	// Sort by timestamp (oldest first)
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Timestamp.Before(entries[j].Timestamp)
-	})
+	return sortAndPage(entries, filter), nil
+}
 
-	// Apply offset and limit
-	start := filter.Offset
-	if start > len(entries) {
-		return []*JournalEntry{}, nil
+// findFiles locates all markdown files in the date range specified by the filter
+// Optimizes by only scanning the directories the layout uses for that range
+func (fs *FileSystemStorage) findFiles(filter EntryFilter) ([]string, error) {
+	// Mid-migration, entries may be in the old or the new layout
+	if fs.migratingTo != "" {
+		return fs.findAllFiles()
 	}
 
-	end := len(entries)
-	if filter.Limit > 0 && start+filter.Limit < end {
-		end = start + filter.Limit
+	var files []string
+	for _, dir := range fs.entryDirs(filter) {
+		// Read all files in this directory
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // Skip directories we can't read
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			// Only include .md files (skip hidden temp files and sync conflict copies)
+			if !isMarkdownFile(entry.Name()) || strings.HasPrefix(entry.Name(), ".") || isConflictCopy(entry.Name()) {
+				continue
+			}
+
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
 	}
 
-	return entries[start:end], nil
+	// Entries in archived years (week directories may belong to the year before or after)
+	first, last := DefaultStartYear, DefaultEndYear
+	if filter.StartDate != nil {
+		first = filter.StartDate.UTC().Year() - 1
+	}
+	if filter.EndDate != nil {
+		last = filter.EndDate.UTC().Year() + 1
+	}
+	archived, err := fs.archivedEntryFiles(first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, archived...), nil
 }
 
-// findFiles locates all markdown files in the date range specified by the filter
-// Optimizes by only scanning relevant year/month directories
-func (fs *FileSystemStorage) findFiles(filter EntryFilter) ([]string, error) {
-	var files []string
+// entryDirs returns the existing directories that may hold entries in the filter's date range
+func (fs *FileSystemStorage) entryDirs(filter EntryFilter) []string {
+	if fs.layout == LayoutFlat {
+		return []string{fs.basePath}
+	}
 
 	// Determine year and month ranges to scan
 	startYear, startMonth := DefaultStartYear, DefaultStartMonth
@@ -190,6 +273,16 @@ func (fs *FileSystemStorage) findFiles(filter EntryFilter) ([]string, error) {
 		endYear, endMonth = utc.Year(), int(utc.Month())
 	}
 
+	var dirs []string
+
+	// Week directories are grouped by ISO year, which can differ from the calendar year by one
+	if fs.layout == LayoutYearWeek {
+		for year := startYear - 1; year <= endYear+1; year++ {
+			dirs = append(dirs, subdirectories(filepath.Join(fs.basePath, fmt.Sprintf("%04d", year)))...)
+		}
+		return dirs
+	}
+
 	// Scan each year/month directory
 	for year := startYear; year <= endYear; year++ {
 		yearPath := filepath.Join(fs.basePath, fmt.Sprintf("%04d", year))
@@ -216,58 +309,151 @@ func (fs *FileSystemStorage) findFiles(filter EntryFilter) ([]string, error) {
 				continue
 			}
 
-			// Read all files in this month directory
-			entries, err := os.ReadDir(monthPath)
-			if err != nil {
-				continue // Skip directories we can't read
+			if fs.layout == LayoutYearMonthDay {
+				dirs = append(dirs, subdirectories(monthPath)...)
+			} else {
+				dirs = append(dirs, monthPath)
 			}
+		}
+	}
 
-			for _, entry := range entries {
-				if entry.IsDir() {
-					continue
-				}
+	return dirs
+}
 
-				// Only include .md files
-				if !isMarkdownFile(entry.Name()) {
-					continue
-				}
+// findAllFiles walks the whole storage tree for entry files, regardless of layout, and
+// includes the entries of archived years
+// Hidden files and directories (drafts, temp files, version control) and sync conflict
+// copies are skipped
+func (fs *FileSystemStorage) findAllFiles() ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(fs.basePath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == fs.basePath && os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
+		}
 
-				filePath := filepath.Join(monthPath, entry.Name())
-				files = append(files, filePath)
+		hidden := strings.HasPrefix(d.Name(), ".") && path != fs.basePath
+		if d.IsDir() {
+			if hidden {
+				return filepath.SkipDir
 			}
+			return nil
+		}
+
+		if !hidden && isMarkdownFile(d.Name()) && !isConflictCopy(d.Name()) {
+			files = append(files, path)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return files, nil
+	archived, err := fs.archivedEntryFiles(DefaultStartYear, DefaultEndYear)
+	return append(files, archived...), err
 }
 
-// buildFilePath constructs the file path for an entry
-// Format: <basePath>/<year>/<month>/YYYY-MM-DD-HH-MM-SS.md
+// subdirectories returns the non-hidden subdirectories of dir
+func subdirectories(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			dirs = append(dirs, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return dirs
+}
+
+// buildFilePath constructs the file path for an entry, without collision suffix or title slug
+// Default format: <basePath>/<year>/<month>/YYYY-MM-DD-HH-MM-SS.md
 // Uses UTC time for consistent naming across timezones
 func (fs *FileSystemStorage) buildFilePath(timestamp time.Time) string {
-	utc := timestamp.UTC()
-	year := fmt.Sprintf("%04d", utc.Year())
-	month := fmt.Sprintf("%02d", int(utc.Month()))
-	filename := fmt.Sprintf("%04d-%02d-%02d-%02d-%02d-%02d.md",
-		utc.Year(), utc.Month(), utc.Day(),
-		utc.Hour(), utc.Minute(), utc.Second())
+	return filepath.Join(fs.layout.dir(fs.basePath, timestamp), fs.layout.fileName(timestamp, 0, ""))
+}
+
+// findEntryFiles returns the files holding entries with the given timestamp,
+// ordered by collision suffix (base file first)
+func (fs *FileSystemStorage) findEntryFiles(timestamp time.Time) []string {
+	files := fs.entryFilesIn(fs.layout.dir(fs.basePath, timestamp), timestamp)
 
-	return filepath.Join(fs.basePath, year, month, filename)
+	// Mid-migration, the entry may already have moved to the new layout
+	if fs.migratingTo != "" {
+		if dir := fs.migratingTo.dir(fs.basePath, timestamp); dir != fs.layout.dir(fs.basePath, timestamp) {
+			files = append(files, fs.entryFilesIn(dir, timestamp)...)
+		}
+	}
+
+	return files
+}
+
+// entryFilesIn returns the files in dir (or its archive) named for the given timestamp,
+// ordered by collision suffix
+func (fs *FileSystemStorage) entryFilesIn(dir string, timestamp time.Time) []string {
+	var names []string
+	if dirEntries, err := os.ReadDir(dir); err == nil {
+		for _, dirEntry := range dirEntries {
+			if !dirEntry.IsDir() {
+				names = append(names, dirEntry.Name())
+			}
+		}
+	}
+	for _, path := range fs.archivedFilesIn(dir) {
+		names = append(names, filepath.Base(path))
+	}
+
+	want := timestamp.UTC().Format(FileNameTimestampLayout)
+	collisions := make(map[string]int)
+	var files []string
+
+	for _, name := range names {
+		ts, collision, ok := parseEntryFileName(name)
+		if !ok || ts != want {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		collisions[path] = collision
+		files = append(files, path)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return collisions[files[i]] < collisions[files[j]]
+	})
+
+	return files
 }
 
-// findAvailablePath checks if file exists and appends suffix if needed
+// availableEntryPath returns a free path for a new entry in the current layout
 // Returns empty string if too many collisions (>= MaxCollisionAttempts)
-func (fs *FileSystemStorage) findAvailablePath(basePath string) string {
-	// Check if base path is available
-	if _, err := os.Stat(basePath); os.IsNotExist(err) {
-		return basePath
+func (fs *FileSystemStorage) availableEntryPath(entry *JournalEntry) string {
+	return fs.freeEntryPath(fs.layout, entry, "", nil)
+}
+
+// freeEntryPath picks the first unused collision slot for entry in the given layout.
+// ignore is a path not counted as taken (the file being moved); reserved holds paths
+// already claimed but not yet written (used for dry runs).
+func (fs *FileSystemStorage) freeEntryPath(layout Layout, entry *JournalEntry, ignore string, reserved map[string]bool) string {
+	dir := layout.dir(fs.basePath, entry.Timestamp)
+
+	used := make(map[int]bool)
+	for _, path := range fs.entryFilesIn(dir, entry.Timestamp) {
+		if path == ignore {
+			continue
+		}
+		_, collision, _ := parseEntryFileName(filepath.Base(path))
+		used[collision] = true
 	}
 
-	// Try appending suffixes
-	baseWithoutExt := basePath[:len(basePath)-len(MarkdownExt)] // Remove extension
-	for i := 1; i < MaxCollisionAttempts; i++ {
-		path := fmt.Sprintf("%s-%02d%s", baseWithoutExt, i, MarkdownExt)
-		if _, err := os.Stat(path); os.IsNotExist(err) {
+	for collision := 0; collision < MaxCollisionAttempts; collision++ {
+		path := filepath.Join(dir, layout.fileName(entry.Timestamp, collision, entry.Body))
+		if !used[collision] && !reserved[path] {
 			return path
 		}
 	}
@@ -277,15 +463,31 @@ func (fs *FileSystemStorage) findAvailablePath(basePath string) string {
 
 // ensureDirectories creates year and month directories if they don't exist
 func (fs *FileSystemStorage) ensureDirectories(filePath string) error {
+	if err := fs.checkWritable(filePath); err != nil {
+		return err
+	}
 	dir := filepath.Dir(filePath)
 	return os.MkdirAll(dir, DirPermissions)
 }
 
-// writeAtomic writes content to a file atomically using temp file + rename
+// writeAtomic writes content to a file atomically and records the change
 func (fs *FileSystemStorage) writeAtomic(filePath string, content []byte) error {
+	if err := fs.checkWritable(filePath); err != nil {
+		return err
+	}
+	if err := replaceFile(filePath, content); err != nil {
+		return err
+	}
+
+	fs.recordChange(filePath)
+	return nil
+}
+
+// replaceFile writes content to a file atomically using temp file + rename
+func replaceFile(filePath string, content []byte) error {
 	// Create temp file in same directory
 	dir := filepath.Dir(filePath)
-	tmpFile := filepath.Join(dir, ".tmp-"+filepath.Base(filePath))
+	tmpFile := filepath.Join(dir, tempFilePrefix+filepath.Base(filePath))
 
 	// Write to temp file
 	if err := os.WriteFile(tmpFile, content, FilePermissions); err != nil {
@@ -301,10 +503,61 @@ func (fs *FileSystemStorage) writeAtomic(filePath string, content []byte) error
 	return nil
 }
 
+// removeFile deletes a file in the storage tree and records the change
+func (fs *FileSystemStorage) removeFile(filePath string) error {
+	if err := fs.checkWritable(filePath); err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil {
+		return err
+	}
+	fs.recordChange(filePath)
+	return nil
+}
+
+// renameFile moves a file within the storage tree and records both paths as changed
+func (fs *FileSystemStorage) renameFile(source, target string) error {
+	if err := fs.checkWritable(source, target); err != nil {
+		return err
+	}
+	if err := os.Rename(source, target); err != nil {
+		return err
+	}
+	fs.recordChange(source, target)
+	return nil
+}
+
+// recordChange notes files that were written, moved or removed
+func (fs *FileSystemStorage) recordChange(paths ...string) {
+	fs.changesMu.Lock()
+	defer fs.changesMu.Unlock()
+	if fs.changes == nil {
+		fs.changes = make(map[string]bool)
+	}
+	for _, path := range paths {
+		fs.changes[path] = true
+	}
+}
+
+// TakeChanges returns the files written, moved or removed since the last call, sorted,
+// and clears the list. Used to version exactly the files an operation touched.
+func (fs *FileSystemStorage) TakeChanges() []string {
+	fs.changesMu.Lock()
+	defer fs.changesMu.Unlock()
+
+	paths := make([]string, 0, len(fs.changes))
+	for path := range fs.changes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	fs.changes = nil
+	return paths
+}
+
 // parseFile reads and parses a single entry file
 func (fs *FileSystemStorage) parseFile(filePath string) (*JournalEntry, error) {
-	// Read file
-	content, err := os.ReadFile(filePath)
+	// Read file (decrypting it if needed)
+	content, err := fs.readEntryFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -318,6 +571,25 @@ func (fs *FileSystemStorage) parseFile(filePath string) (*JournalEntry, error) {
 	return entry, nil
 }
 
+// sortAndPage sorts entries by timestamp (oldest first) and applies the filter's offset and limit
+func sortAndPage(entries []*JournalEntry, filter EntryFilter) []*JournalEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	start := filter.Offset
+	if start > len(entries) {
+		return []*JournalEntry{}
+	}
+
+	end := len(entries)
+	if filter.Limit > 0 && start+filter.Limit < end {
+		end = start + filter.Limit
+	}
+
+	return entries[start:end]
+}
+
 // isMarkdownFile checks if a file has .md extension
 func isMarkdownFile(path string) bool {
 	return strings.HasSuffix(strings.ToLower(path), ".md")
@@ -374,6 +646,10 @@ func (fs *FileSystemStorage) SearchByKeyword(keyword string, filter EntryFilter)
 // getOrCreateIndex returns the existing index or builds a new one
 // Only builds index for the files in the specified date range
 func (fs *FileSystemStorage) getOrCreateIndex(filter EntryFilter) (*Index, error) {
+	if err := fs.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
 	fs.indexOnce.Do(func() {
 		files, err := fs.findFiles(filter)
 		if err != nil {
@@ -409,23 +685,7 @@ func (fs *FileSystemStorage) indexedEntriesToFull(indexed []*IndexedEntry, filte
 		entries = append(entries, entry)
 	}
 
-	// Sort by timestamp (oldest first)
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Timestamp.Before(entries[j].Timestamp)
-	})
-
-	// Apply offset and limit
-	start := filter.Offset
-	if start > len(entries) {
-		return []*JournalEntry{}, nil
-	}
-
-	end := len(entries)
-	if filter.Limit > 0 && start+filter.Limit < end {
-		end = start + filter.Limit
-	}
-
-	return entries[start:end], nil
+	return sortAndPage(entries, filter), nil
 }
 
 // InvalidateIndex clears the search index, forcing a rebuild on next search
@@ -437,6 +697,18 @@ func (fs *FileSystemStorage) InvalidateIndex() {
 	fs.indexOnce = sync.Once{}
 }
 
+// RefreshIndex re-indexes files changed outside of this storage (e.g. pulled by a sync)
+// Does nothing if the index hasn't been built yet; it will include the changes when it is
+func (fs *FileSystemStorage) RefreshIndex(files []string) {
+	fs.mu.RLock()
+	index := fs.index
+	fs.mu.RUnlock()
+
+	if index != nil {
+		index.Update(files, fs.parseFile)
+	}
+}
+
 // GetIndex returns the existing index or builds a new one
 // This is a public wrapper around getOrCreateIndex for use by CLI commands
 func (fs *FileSystemStorage) GetIndex(filter EntryFilter) (*Index, error) {
@@ -508,23 +780,9 @@ func (fs *FileSystemStorage) GetEntriesWithMention(mention string) ([]string, er
 // GetEntryPath returns the file path for an entry by timestamp
 // Handles collision suffixes (-01, -02, etc.)
 func (fs *FileSystemStorage) GetEntryPath(timestamp time.Time) (string, error) {
-	// Build expected file path
-	basePath := fs.buildFilePath(timestamp)
-
-	// Try base path first
-	if _, err := os.Stat(basePath); err == nil {
-		return basePath, nil
-	}
-
-	// Try with collision suffixes (-01, -02, etc.)
-	for i := 1; i < MaxCollisionAttempts; i++ {
-		path := fmt.Sprintf("%s-%02d%s", basePath[:len(basePath)-len(MarkdownExt)], i, MarkdownExt)
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
-		}
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			break
-		}
+	// Base path first, then collision suffixes (-01, -02, etc.)
+	if files := fs.findEntryFiles(timestamp); len(files) > 0 {
+		return files[0], nil
 	}
 
 	return "", fmt.Errorf("entry not found: %s", timestamp.Format(FileTimestampFormat))
@@ -533,34 +791,106 @@ func (fs *FileSystemStorage) GetEntryPath(timestamp time.Time) (string, error) {
 // UpdateEntry updates an existing entry atomically
 // The entry's timestamp must match the original (timestamp changes not allowed)
 func (fs *FileSystemStorage) UpdateEntry(filePath string, newEntry *JournalEntry) error {
+	if err := fs.checkWritable(filePath); err != nil {
+		return err
+	}
+
 	// Verify file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return fmt.Errorf("entry not found: %s", filePath)
 	}
 
+	// Signed journals record the edit as an amendment of the previous content
+	chain, err := fs.openChain()
+	if err != nil {
+		return err
+	}
+	previous := fs.textHash(filePath)
+
 	// Serialize new content
 	markdown := SerializeEntry(newEntry)
 
 	// Write atomically (overwrites old file)
-	if err := fs.writeAtomic(filePath, []byte(markdown)); err != nil {
+	if err := fs.writeEntryFile(filePath, []byte(markdown)); err != nil {
 		return fmt.Errorf("failed to update entry: %w", err)
 	}
 
 	// Invalidate index
 	fs.InvalidateIndex()
 
-	return nil
+	return fs.signWrite(chain, filePath, newEntry, []byte(markdown), previous)
+}
+
+// GetEntryVersion returns the current content hash and modification time of an entry file
+// Entries in archived years have no version, since they can't be edited
+func (fs *FileSystemStorage) GetEntryVersion(filePath string) (EntryVersion, error) {
+	if err := fs.checkWritable(filePath); err != nil {
+		return EntryVersion{}, err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return EntryVersion{}, fmt.Errorf("entry not found: %s", filePath)
+		}
+		return EntryVersion{}, err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return EntryVersion{}, err
+	}
+
+	return EntryVersion{
+		Hash:    contentHash(content),
+		ModTime: info.ModTime(),
+	}, nil
+}
+
+// contentHash returns the hex-encoded SHA-256 of content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetEntryByPath reads and parses the entry stored at filePath
+func (fs *FileSystemStorage) GetEntryByPath(filePath string) (*JournalEntry, error) {
+	return fs.parseFile(filePath)
+}
+
+// UpdateEntryIfUnchanged updates an entry only if the file still matches the expected version
+// Returns ErrEntryModified if the file was changed since the version was recorded
+func (fs *FileSystemStorage) UpdateEntryIfUnchanged(filePath string, newEntry *JournalEntry, expected EntryVersion) error {
+	current, err := fs.GetEntryVersion(filePath)
+	if err != nil {
+		return err
+	}
+
+	if current.Hash != expected.Hash {
+		return ErrEntryModified
+	}
+
+	return fs.UpdateEntry(filePath, newEntry)
 }
 
 // DeleteEntry removes a single entry by file path
 func (fs *FileSystemStorage) DeleteEntry(filePath string) error {
+	if err := fs.checkWritable(filePath); err != nil {
+		return err
+	}
+
 	// Check file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return fmt.Errorf("entry not found: %s", filePath)
 	}
 
+	chain, err := fs.openChain()
+	if err != nil {
+		return err
+	}
+
 	// Delete file
-	if err := os.Remove(filePath); err != nil {
+	if err := fs.removeEntryFile(chain, filePath); err != nil {
 		return fmt.Errorf("failed to delete entry: %w", err)
 	}
 
@@ -570,9 +900,24 @@ func (fs *FileSystemStorage) DeleteEntry(filePath string) error {
 	return nil
 }
 
+// removeEntryFile deletes an entry file, recording the deletion in the hash chain
+func (fs *FileSystemStorage) removeEntryFile(chain *chainWriter, filePath string) error {
+	hash := fs.textHash(filePath)
+	entry, _, _ := parseEntryFileName(filepath.Base(filePath))
+
+	if err := fs.removeFile(filePath); err != nil {
+		return err
+	}
+	return chain.append(ChainDelete, filePath, entry, hash, "")
+}
+
 // DeleteEntries removes multiple entries matching the filter
 // Returns list of deleted file paths and any errors encountered
 func (fs *FileSystemStorage) DeleteEntries(filter EntryFilter) ([]string, error) {
+	if err := fs.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+
 	// Find all matching file paths
 	files, err := fs.findFiles(filter)
 	if err != nil {
@@ -600,12 +945,17 @@ func (fs *FileSystemStorage) DeleteEntries(filter EntryFilter) ([]string, error)
 		filesToDelete = append(filesToDelete, filePath)
 	}
 
+	chain, err := fs.openChain()
+	if err != nil {
+		return nil, err
+	}
+
 	// Delete each file and collect results
 	var deleted []string
 	var errs []error
 
 	for _, filePath := range filesToDelete {
-		if err := os.Remove(filePath); err != nil {
+		if err := fs.removeEntryFile(chain, filePath); err != nil {
 			errs = append(errs, fmt.Errorf("failed to delete %s: %w", filePath, err))
 			continue
 		}
@@ -626,10 +976,11 @@ func (fs *FileSystemStorage) DeleteEntries(filter EntryFilter) ([]string, error)
 	return deleted, nil
 }
 
-// replaceMetadataInEntries is a unified function for replacing tags or mentions
+// replaceMetadataInEntries is a unified function for replacing tags or mentions in the
+// entries of any storage backend
 // Uses case-insensitive matching with proper word boundaries
 // Returns list of updated file paths
-func (fs *FileSystemStorage) replaceMetadataInEntries(oldValue, newValue, symbol string, filePaths []string, dryRun bool) ([]string, error) {
+func replaceMetadataInEntries(storage Storage, oldValue, newValue, symbol string, filePaths []string, dryRun bool) ([]string, error) {
 	if len(filePaths) == 0 {
 		return []string{}, nil
 	}
@@ -648,7 +999,7 @@ func (fs *FileSystemStorage) replaceMetadataInEntries(oldValue, newValue, symbol
 
 	for _, filePath := range filePaths {
 		// Read current entry
-		entry, err := fs.parseFile(filePath)
+		entry, err := storage.GetEntryByPath(filePath)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("failed to read %s: %w", filePath, err))
 			continue
@@ -693,7 +1044,7 @@ func (fs *FileSystemStorage) replaceMetadataInEntries(oldValue, newValue, symbol
 
 		// Write updated entry (unless dry run)
 		if !dryRun {
-			if err := fs.UpdateEntry(filePath, entry); err != nil {
+			if err := storage.UpdateEntry(filePath, entry); err != nil {
 				errs = append(errs, fmt.Errorf("failed to update %s: %w", filePath, err))
 				continue
 			}
@@ -720,7 +1071,7 @@ func (fs *FileSystemStorage) ReplaceTagInEntries(oldTag, newTag string, dryRun b
 		return nil, fmt.Errorf("failed to get entries with tag: %w", err)
 	}
 
-	return fs.replaceMetadataInEntries(oldTag, newTag, "#", filePaths, dryRun)
+	return replaceMetadataInEntries(fs, oldTag, newTag, "#", filePaths, dryRun)
 }
 
 // ReplaceMentionInEntries replaces oldMention with newMention in all entries
@@ -733,5 +1084,5 @@ func (fs *FileSystemStorage) ReplaceMentionInEntries(oldMention, newMention stri
 		return nil, fmt.Errorf("failed to get entries with mention: %w", err)
 	}
 
-	return fs.replaceMetadataInEntries(oldMention, newMention, "@", filePaths, dryRun)
+	return replaceMetadataInEntries(fs, oldMention, newMention, "@", filePaths, dryRun)
 }
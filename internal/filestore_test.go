@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -2140,3 +2141,42 @@ func TestReplaceMentionInEntries_NotFound(t *testing.T) {
 		t.Errorf("ReplaceMentionInEntries() updated %d entries, want 0", len(updated))
 	}
 }
+
+func TestUpdateEntryIfUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+
+	timestamp := time.Date(2026, 2, 8, 16, 31, 0, 0, time.UTC)
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: timestamp, Body: "Original."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	path, err := storage.GetEntryPath(timestamp)
+	if err != nil {
+		t.Fatalf("GetEntryPath() error = %v", err)
+	}
+
+	version, err := storage.GetEntryVersion(path)
+	if err != nil {
+		t.Fatalf("GetEntryVersion() error = %v", err)
+	}
+
+	// Unchanged file: update succeeds
+	if err := storage.UpdateEntryIfUnchanged(path, &JournalEntry{Timestamp: timestamp, Body: "Mine."}, version); err != nil {
+		t.Fatalf("UpdateEntryIfUnchanged() error = %v", err)
+	}
+
+	// Stale version: update is rejected and the file is left alone
+	err = storage.UpdateEntryIfUnchanged(path, &JournalEntry{Timestamp: timestamp, Body: "Stale."}, version)
+	if !errors.Is(err, ErrEntryModified) {
+		t.Fatalf("UpdateEntryIfUnchanged() error = %v, want ErrEntryModified", err)
+	}
+
+	entry, err := storage.GetEntryByPath(path)
+	if err != nil {
+		t.Fatalf("GetEntryByPath() error = %v", err)
+	}
+	if entry.Body != "Mine." {
+		t.Errorf("Body = %q, want %q", entry.Body, "Mine.")
+	}
+}
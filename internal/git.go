@@ -0,0 +1,314 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotGitRepository is returned when the storage directory is not inside a git work tree
+var ErrNotGitRepository = errors.New("storage directory is not a git repository")
+
+// GitRepo runs the local git binary against a journal storage directory.
+// The storage directory may be the root of the repository or any directory inside one;
+// paths are always relative to the storage directory.
+type GitRepo struct {
+	dir string
+}
+
+// NewGitRepo creates a GitRepo for the given storage directory
+func NewGitRepo(dir string) *GitRepo {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return &GitRepo{dir: dir}
+}
+
+// GitCommit is a commit in the journal's history
+type GitCommit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+	Files   []string // Changed files, relative to the storage directory
+
+	CoAuthors []string // Names from Co-authored-by trailers (History only)
+}
+
+// run executes git in the storage directory and returns its standard output
+// Errors include git's standard error output
+func (g *GitRepo) run(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", g.dir}, args...)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return stdout.String(), nil
+}
+
+// IsRepo reports whether the storage directory is inside a git work tree
+func (g *GitRepo) IsRepo() bool {
+	out, err := g.run("rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// Init creates a git repository in the storage directory
+func (g *GitRepo) Init() error {
+	if err := os.MkdirAll(g.dir, DirPermissions); err != nil {
+		return err
+	}
+	_, err := g.run("init", "--quiet")
+	return err
+}
+
+// CommitFiles commits changes to the given files (added, modified or removed).
+// Only these files are committed; anything else already staged stays staged.
+// Returns false if none of the files had changes to commit.
+func (g *GitRepo) CommitFiles(message string, paths []string) (bool, error) {
+	var existing, missing []string
+	for _, path := range paths {
+		rel, err := g.relative(path)
+		if err != nil {
+			return false, err
+		}
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, rel)
+		} else {
+			missing = append(missing, rel)
+		}
+	}
+
+	// Stage additions and modifications, then removals
+	if len(existing) > 0 {
+		if _, err := g.run(append([]string{"add", "--all", "--"}, existing...)...); err != nil {
+			return false, err
+		}
+	}
+	if len(missing) > 0 {
+		if _, err := g.run(append([]string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--"}, missing...)...); err != nil {
+			return false, err
+		}
+	}
+
+	// Only commit the files that actually differ from HEAD
+	out, err := g.run(append([]string{"diff", "--cached", "--name-only", "-z", "--relative", "--"}, append(existing, missing...)...)...)
+	if err != nil {
+		return false, err
+	}
+	changed := splitNull(out)
+	if len(changed) == 0 {
+		return false, nil
+	}
+
+	if _, err := g.run(append([]string{"commit", "--quiet", "--message", message, "--"}, changed...)...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CommitAll commits every change in the storage directory, except paths matching the
+// given exclude pathspecs. Returns false if there was nothing to commit.
+func (g *GitRepo) CommitAll(message string, excludes ...string) (bool, error) {
+	pathspec := append([]string{"."}, excludes...)
+
+	if _, err := g.run(append([]string{"add", "--all", "--"}, pathspec...)...); err != nil {
+		return false, err
+	}
+
+	out, err := g.run(append([]string{"diff", "--cached", "--name-only", "-z", "--relative", "--"}, pathspec...)...)
+	if err != nil {
+		return false, err
+	}
+	if len(splitNull(out)) == 0 {
+		return false, nil
+	}
+
+	if _, err := g.run(append([]string{"commit", "--quiet", "--message", message, "--"}, pathspec...)...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// unmergedFiles lists files with merge conflicts, mapping each path (relative to the
+// storage directory) to its object IDs by stage: 1 = common base, 2 = ours, 3 = theirs
+func (g *GitRepo) unmergedFiles() (map[string]map[int]string, error) {
+	out, err := g.run("ls-files", "--unmerged", "-z", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	unmerged := make(map[string]map[int]string)
+	for _, record := range splitNull(out) {
+		// <mode> <object> <stage>\t<path>
+		info, path, ok := strings.Cut(record, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 3 {
+			continue
+		}
+		stage, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		if unmerged[path] == nil {
+			unmerged[path] = make(map[int]string)
+		}
+		unmerged[path][stage] = fields[1]
+	}
+
+	return unmerged, nil
+}
+
+// Log returns the most recent commits that touched the storage directory, newest first
+// A limit of 0 returns the full history
+func (g *GitRepo) Log(limit int) ([]GitCommit, error) {
+	if !g.IsRepo() {
+		return nil, ErrNotGitRepository
+	}
+
+	// Each commit starts with a record separator; fields are NUL-separated,
+	// followed by the changed file names
+	args := []string{"log", "--format=%x1e%H%x00%an%x00%aI%x00%s", "--name-only", "--relative"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", limit))
+	}
+	args = append(args, "--", ".")
+
+	out, err := g.run(args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not have any commits") {
+			return []GitCommit{}, nil
+		}
+		return nil, err
+	}
+
+	commits := []GitCommit{}
+	for _, record := range strings.Split(out, "\x1e") {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		header, files, _ := strings.Cut(record, "\n")
+		fields := strings.SplitN(header, "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		date, _ := time.Parse(time.RFC3339, fields[2])
+		commit := GitCommit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    date,
+			Subject: fields[3],
+		}
+		for _, file := range strings.Split(files, "\n") {
+			if file = strings.TrimSpace(file); file != "" {
+				commit.Files = append(commit.Files, file)
+			}
+		}
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// History returns the commits of the current branch by authors matching author (a name
+// or email, or part of one; "" for everyone) between since and until, in the order they
+// were committed. Dates are author dates, so they can be out of order after a rebase.
+// Merge commits are left out.
+func (g *GitRepo) History(author string, since, until *time.Time) ([]GitCommit, error) {
+	if !g.IsRepo() {
+		return nil, fmt.Errorf("%s is not a git repository", g.dir)
+	}
+
+	// Each commit starts with a record separator; fields are NUL-separated, the message
+	// body last
+	args := []string{"log", "--reverse", "--no-merges", "--fixed-strings", "--format=%x1e%H%x00%an%x00%aI%x00%s%x00%b"}
+	if author != "" {
+		args = append(args, "--author="+author)
+	}
+	if since != nil {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	if until != nil {
+		args = append(args, "--until="+until.Format(time.RFC3339))
+	}
+
+	out, err := g.run(args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not have any commits") {
+			return []GitCommit{}, nil
+		}
+		return nil, err
+	}
+
+	commits := []GitCommit{}
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(record, "\x00", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q of commit %s", fields[2], fields[0])
+		}
+		commit := GitCommit{Hash: fields[0], Author: fields[1], Date: date, Subject: fields[3]}
+		for _, line := range strings.Split(fields[4], "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "Co-authored-by") {
+				name, _, _ := strings.Cut(strings.TrimSpace(value), "<")
+				if name = strings.TrimSpace(name); name != "" {
+					commit.CoAuthors = append(commit.CoAuthors, name)
+				}
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// UserEmail returns the email of the git user configured for the repository
+func (g *GitRepo) UserEmail() (string, error) {
+	out, err := g.run("config", "user.email")
+	if err != nil || strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("no git user.email configured for %s", g.dir)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// relative converts a path in the storage tree to a path relative to the storage directory
+func (g *GitRepo) relative(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(g.dir, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside the storage directory", path)
+	}
+	return rel, nil
+}
+
+// splitNull splits NUL-terminated git output into its fields
+func splitNull(out string) []string {
+	var fields []string
+	for _, field := range strings.Split(out, "\x00") {
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
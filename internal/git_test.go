@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setupGitRepo creates a git repository with a fixed identity, skipping if git is unavailable
+func setupGitRepo(t *testing.T) (string, *GitRepo) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	dir := t.TempDir()
+	repo := NewGitRepo(dir)
+	if repo.IsRepo() {
+		t.Skip("temp directory is inside a git repository")
+	}
+	if err := repo.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	return dir, repo
+}
+
+func TestGitRepo_CommitFilesAndLog(t *testing.T) {
+	dir, repo := setupGitRepo(t)
+	storage := NewFileSystemStorage(dir, nil)
+
+	// Nothing committed yet
+	commits, err := repo.Log(0)
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(commits) != 0 {
+		t.Errorf("Log() on empty repository = %d commits, want 0", len(commits))
+	}
+
+	// Add
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Hello #k8s"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	unrelated := writeTestFile(t, dir, "notes.txt", "not part of the commit")
+
+	changes := storage.TakeChanges()
+	entryPath := filepath.Join(dir, "2026", "02", "2026-02-09-14-30-00.md")
+	want := []string{filepath.Join(dir, LayoutStateFile), entryPath}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("TakeChanges() = %v, want %v", changes, want)
+	}
+	if again := storage.TakeChanges(); len(again) != 0 {
+		t.Errorf("TakeChanges() second call = %v, want empty", again)
+	}
+
+	committed, err := repo.CommitFiles("add entry", changes)
+	if err != nil || !committed {
+		t.Fatalf("CommitFiles() = %v, %v", committed, err)
+	}
+
+	// Rename a tag, then delete the entry
+	if _, err := storage.ReplaceTagInEntries("k8s", "kubernetes", false); err != nil {
+		t.Fatalf("ReplaceTagInEntries() error = %v", err)
+	}
+	if _, err := repo.CommitFiles("rename #k8s -> #kubernetes in 1 entry", storage.TakeChanges()); err != nil {
+		t.Fatalf("CommitFiles() error = %v", err)
+	}
+	if err := storage.DeleteEntry(entryPath); err != nil {
+		t.Fatalf("DeleteEntry() error = %v", err)
+	}
+	if _, err := repo.CommitFiles("delete entry", storage.TakeChanges()); err != nil {
+		t.Fatalf("CommitFiles() error = %v", err)
+	}
+
+	// Unchanged files: nothing to commit
+	committed, err = repo.CommitFiles("noop", []string{entryPath})
+	if err != nil || committed {
+		t.Errorf("CommitFiles() with no changes = %v, %v; want false, nil", committed, err)
+	}
+
+	commits, err = repo.Log(0)
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	var subjects []string
+	for _, commit := range commits {
+		subjects = append(subjects, commit.Subject)
+	}
+	wantSubjects := []string{"delete entry", "rename #k8s -> #kubernetes in 1 entry", "add entry"}
+	if !reflect.DeepEqual(subjects, wantSubjects) {
+		t.Errorf("Log() subjects = %v, want %v", subjects, wantSubjects)
+	}
+	if !reflect.DeepEqual(commits[2].Files, []string{LayoutStateFile, "2026/02/2026-02-09-14-30-00.md"}) {
+		t.Errorf("Log() files = %v", commits[2].Files)
+	}
+
+	// Files outside the operation were not committed
+	out, err := repo.run("status", "--porcelain", "--", filepath.Base(unrelated))
+	if err != nil || out == "" {
+		t.Errorf("expected %s to remain uncommitted, status = %q, err = %v", unrelated, out, err)
+	}
+
+	// Limit
+	if commits, _ := repo.Log(1); len(commits) != 1 {
+		t.Errorf("Log(1) = %d commits, want 1", len(commits))
+	}
+}
+
+func TestGitRepo_NotRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := NewGitRepo(t.TempDir())
+	if repo.IsRepo() {
+		t.Skip("temp directory is inside a git repository")
+	}
+	if _, err := repo.Log(0); err != ErrNotGitRepository {
+		t.Errorf("Log() error = %v, want ErrNotGitRepository", err)
+	}
+}
@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GitImportOptions selects the commits of a repository for ReadGitHistory and how they
+// are grouped into entries
+type GitImportOptions struct {
+	Author string     // Name or email (or part of one) of the author; "me" for the repository's git user, "" for everyone
+	Since  *time.Time // Start of the history, from the start of its window
+	Until  *time.Time
+	Window time.Duration // Period of time grouped into an entry: a part of a day, a day or a week (local time)
+}
+
+// Week is the window of weekly work logs, from Monday
+const Week = 7 * 24 * time.Hour
+
+// gitHashLength is how much of a commit hash is written in entries
+const gitHashLength = 8
+
+// loggedCommit matches a commit written in an entry by ReadGitHistory
+var loggedCommit = regexp.MustCompile(`(?m)^- ([0-9a-f]{8}) `)
+
+// ReadGitHistory returns work log entries of the commits of a git repository: one per
+// window with commits, at the time of its last commit. Entries list the commit subjects,
+// tagged with the repository name and mentioning co-authors.
+//
+// Commits already written in an entry of the journal are left out, so running it again
+// only adds the commits made since; windows whose commits are all there are duplicates.
+func ReadGitHistory(storage Storage, repoPath string, options GitImportOptions) ([]ImportItem, error) {
+	if options.Window <= 0 {
+		options.Window = 24 * time.Hour
+	}
+	if options.Window != Week && (options.Window > 24*time.Hour || (24*time.Hour)%options.Window != 0) {
+		return nil, fmt.Errorf("invalid window %v: use a week, a day or a part of a day like 4h", options.Window)
+	}
+
+	repo := NewGitRepo(repoPath)
+	author := options.Author
+	if author == "me" {
+		email, err := repo.UserEmail()
+		if err != nil {
+			return nil, fmt.Errorf("%w; give --author", err)
+		}
+		author = email
+	}
+	since := options.Since
+	if since != nil {
+		start := windowStart(since.Local(), options.Window)
+		since = &start
+	}
+	commits, err := repo.History(author, since, options.Until)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	// git lists commits in the order they were committed; rebased, cherry-picked or
+	// amended commits keep an earlier author date, which windows go by
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].Date.Before(commits[j].Date) })
+
+	logged, err := loggedCommits(storage, commits[0].Date, commits[len(commits)-1].Date, options.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(repo.dir)
+	var items []ImportItem
+	for start := 0; start < len(commits); {
+		window := windowStart(commits[start].Date.Local(), options.Window)
+		end := start
+		for end < len(commits) && windowStart(commits[end].Date.Local(), options.Window).Equal(window) {
+			end++
+		}
+		group := commits[start:end]
+		start = end
+
+		var unlogged []GitCommit
+		for _, commit := range group {
+			if !logged[shortHash(commit)] {
+				unlogged = append(unlogged, commit)
+			}
+		}
+		action := ImportNew
+		if len(unlogged) == 0 {
+			action = ImportDuplicate
+			unlogged = group
+		}
+
+		source := fmt.Sprintf("%s %s", name, window.Format("2006-01-02 15:04"))
+		item := importedEntry(source, unlogged[len(unlogged)-1].Date.Local(), workLog(name, unlogged))
+		if item.Action == ImportNew {
+			item.Action = action
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// windowStart returns the start of the window t is in, in its location
+func windowStart(t time.Time, window time.Duration) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if window == Week {
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day.Add(t.Sub(day) / window * window)
+}
+
+// loggedCommits returns the commits written in entries of the journal around a span of time
+func loggedCommits(storage Storage, first, last time.Time, window time.Duration) (map[string]bool, error) {
+	first, last = first.Add(-window-24*time.Hour), last.Add(window+24*time.Hour)
+	entries, err := storage.ListEntries(EntryFilter{StartDate: &first, EndDate: &last})
+	if err != nil {
+		return nil, err
+	}
+	logged := make(map[string]bool)
+	for _, entry := range entries {
+		for _, match := range loggedCommit.FindAllStringSubmatch(entry.Body, -1) {
+			logged[match[1]] = true
+		}
+	}
+	return logged, nil
+}
+
+// workLog returns the text of the entry of a group of commits
+func workLog(name string, commits []GitCommit) string {
+	var sb strings.Builder
+	if len(commits) == 1 {
+		fmt.Fprintf(&sb, "1 commit to %s\n\n", name)
+	} else {
+		fmt.Fprintf(&sb, "%d commits to %s\n\n", len(commits), name)
+	}
+
+	tags := []string{tagFromName(name)}
+	seen := make(map[string]bool)
+	for _, commit := range commits {
+		fmt.Fprintf(&sb, "- %s %s\n", shortHash(commit), commit.Subject)
+		for _, coAuthor := range commit.CoAuthors {
+			if mention := tagFromName(coAuthor); mention != "" && !seen[mention] {
+				seen[mention] = true
+				tags = append(tags, "@"+mention[1:])
+			}
+		}
+	}
+	return appendTags(sb.String(), tags)
+}
+
+// shortHash returns the abbreviated hash of a commit written in entries
+func shortHash(commit GitCommit) string {
+	if len(commit.Hash) < gitHashLength {
+		return commit.Hash
+	}
+	return commit.Hash[:gitHashLength]
+}
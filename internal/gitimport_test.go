@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadGitHistory(t *testing.T) {
+	dir, repo := setupGitRepo(t)
+	commit := func(date time.Time, author, message string) {
+		t.Helper()
+		t.Setenv("GIT_AUTHOR_DATE", date.Format(time.RFC3339))
+		t.Setenv("GIT_AUTHOR_EMAIL", author)
+		if _, err := repo.run("commit", "--allow-empty", "--quiet", "-m", message); err != nil {
+			t.Fatalf("commit error = %v", err)
+		}
+	}
+	day := func(d, hour int) time.Time { return time.Date(2024, 2, d, hour, 0, 0, 0, time.Local) }
+	commit(day(9, 10), "test@example.com", "Fix parser crash\n\nCo-authored-by: Alice Smith <alice@example.com>")
+	commit(day(9, 11), "other@example.com", "Someone else's work")
+	commit(day(9, 15), "test@example.com", "Add tests\n\nCo-authored-by: Alice Smith <alice@example.com>")
+	commit(day(11, 9), "test@example.com", "Release 1.0")
+	if _, err := repo.run("config", "user.email", "test@example.com"); err != nil {
+		t.Fatalf("git config error = %v", err)
+	}
+
+	storage := NewMemoryStorage()
+	since := day(9, 14) // From the start of its day
+	items, err := ReadGitHistory(storage, dir, GitImportOptions{Author: "me", Since: &since})
+	if err != nil {
+		t.Fatalf("ReadGitHistory() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	name := filepath.Base(dir)
+	first := items[0]
+	if first.Action != ImportNew || !first.Entry.Timestamp.Equal(day(9, 15)) {
+		t.Fatalf("first item = %+v", first)
+	}
+	for _, want := range []string{"2 commits to " + name, " Fix parser crash\n", " Add tests\n", "@Alice-Smith"} {
+		if !strings.Contains(first.Entry.Body, want) {
+			t.Errorf("first entry body = %q, want %q in it", first.Entry.Body, want)
+		}
+	}
+	if strings.Contains(first.Entry.Body, "Someone else") || strings.Count(first.Entry.Body, "@Alice") != 1 {
+		t.Errorf("first entry body = %q", first.Entry.Body)
+	}
+	if _, err := ImportEntries(storage, items, false); err != nil {
+		t.Fatalf("ImportEntries() error = %v", err)
+	}
+
+	// Running it again only adds new commits
+	commit(day(11, 17), "test@example.com", "Fix release notes")
+	items, err = ReadGitHistory(storage, dir, GitImportOptions{Author: "me", Since: &since})
+	if err != nil {
+		t.Fatalf("ReadGitHistory() again error = %v", err)
+	}
+	if len(items) != 2 || items[0].Action != ImportDuplicate || items[1].Action != ImportNew {
+		t.Fatalf("items again = %+v", items)
+	}
+	if body := items[1].Entry.Body; !strings.HasPrefix(body, "1 commit to ") || !strings.Contains(body, "Fix release notes") {
+		t.Errorf("new entry body = %q", body)
+	}
+
+	// Everyone's commits, by week
+	items, err = ReadGitHistory(NewMemoryStorage(), dir, GitImportOptions{Window: Week})
+	if err != nil {
+		t.Fatalf("ReadGitHistory(week) error = %v", err)
+	}
+	if len(items) != 1 || !strings.HasPrefix(items[0].Entry.Body, "5 commits") {
+		t.Errorf("weekly items = %+v", items)
+	}
+
+	if _, err := ReadGitHistory(storage, dir, GitImportOptions{Window: 5 * time.Hour}); err == nil {
+		t.Error("ReadGitHistory(5h window): expected error")
+	}
+}
+
+func TestReadGitHistory_RebasedCommits(t *testing.T) {
+	dir, repo := setupGitRepo(t)
+	commit := func(date time.Time, message string) {
+		t.Helper()
+		t.Setenv("GIT_AUTHOR_DATE", date.Format(time.RFC3339))
+		if _, err := repo.run("commit", "--allow-empty", "--quiet", "-m", message); err != nil {
+			t.Fatalf("commit error = %v", err)
+		}
+	}
+	day := func(d, hour int) time.Time { return time.Date(2024, 2, d, hour, 0, 0, 0, time.Local) }
+	// A branch written on Monday and rebased after Tuesday's commits
+	commit(day(12, 10), "Start the branch")
+	commit(day(13, 9), "Tuesday's fix")
+	commit(day(12, 16), "Finish the branch")
+
+	items, err := ReadGitHistory(NewMemoryStorage(), dir, GitImportOptions{})
+	if err != nil {
+		t.Fatalf("ReadGitHistory() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want one per day: %+v", len(items), items)
+	}
+	monday := items[0].Entry
+	if !monday.Timestamp.Equal(day(12, 16)) || !strings.HasPrefix(monday.Body, "2 commits") {
+		t.Errorf("Monday's entry = %v %q", monday.Timestamp, monday.Body)
+	}
+	if body := monday.Body; strings.Index(body, "Start the branch") > strings.Index(body, "Finish the branch") {
+		t.Errorf("Monday's commits out of order: %q", body)
+	}
+	if !items[1].Entry.Timestamp.Equal(day(13, 9)) {
+		t.Errorf("Tuesday's entry at %v", items[1].Entry.Timestamp)
+	}
+}
+
+func TestWindowStart(t *testing.T) {
+	ts := time.Date(2024, 2, 9, 14, 30, 0, 0, time.UTC) // A Friday
+	tests := map[time.Duration]time.Time{
+		24 * time.Hour: time.Date(2024, 2, 9, 0, 0, 0, 0, time.UTC),
+		4 * time.Hour:  time.Date(2024, 2, 9, 12, 0, 0, 0, time.UTC),
+		Week:           time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC),
+	}
+	for window, want := range tests {
+		if got := windowStart(ts, window); !got.Equal(want) {
+			t.Errorf("windowStart(%v) = %v, want %v", window, got, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jashort/jrnlg/internal/patterns"
+)
+
+// ImportAction is what an import does with an entry of its source
+type ImportAction string
+
+const (
+	ImportNew       ImportAction = "import"    // Saved as a new entry
+	ImportDuplicate ImportAction = "duplicate" // Already in the journal, skipped
+	ImportFailed    ImportAction = "failed"    // Couldn't be read or saved
+)
+
+// ImportItem is an entry of an import source and what the import does with it
+type ImportItem struct {
+	Source string        // Where the entry is in the source, e.g. "line 12"
+	Entry  *JournalEntry // nil if the entry couldn't be read
+	Action ImportAction
+	Err    error // Why the entry failed
+
+	Attachments []string // Files attached to the entry in the source, which aren't imported
+}
+
+// ImportResult describes an import
+type ImportResult struct {
+	Items []ImportItem
+}
+
+// Count returns how many entries got the given action
+func (r *ImportResult) Count(action ImportAction) int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// importedEntry returns the item of an entry read from an import source. The body is
+// parsed like a new entry's, so invalid tags or an empty body fail the item. The time is
+// kept to the minute in its own time zone, which the entry header names.
+func importedEntry(source string, timestamp time.Time, body string) ImportItem {
+	entry, err := ParseEntry(SerializeEntry(&JournalEntry{Timestamp: timestamp, Body: body}))
+	if err != nil {
+		return failedImport(source, err)
+	}
+	entry.Timestamp = timestamp.Truncate(time.Minute)
+	return ImportItem{Source: source, Entry: entry, Action: ImportNew}
+}
+
+// failedImport returns the item of an entry that couldn't be read
+func failedImport(source string, err error) ImportItem {
+	return ImportItem{Source: source, Action: ImportFailed, Err: err}
+}
+
+// ImportEntries saves the entries read from an import source, skipping those already in
+// the journal: entries with the same time (to the minute, in any time zone) and text,
+// which makes importing the same source again harmless. With dryRun nothing is saved.
+func ImportEntries(storage Storage, items []ImportItem, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{Items: items}
+
+	var first, last time.Time
+	for _, item := range items {
+		if item.Entry == nil {
+			continue
+		}
+		if first.IsZero() || item.Entry.Timestamp.Before(first) {
+			first = item.Entry.Timestamp
+		}
+		if item.Entry.Timestamp.After(last) {
+			last = item.Entry.Timestamp
+		}
+	}
+	if first.IsZero() {
+		return result, nil
+	}
+
+	// Entries of the journal in the time span of the import, with a day to spare: headers
+	// in a time zone unknown here are read back as UTC
+	first, last = first.Add(-24*time.Hour), last.Add(24*time.Hour)
+	existing, err := storage.ListEntries(EntryFilter{StartDate: &first, EndDate: &last})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, 2*len(existing))
+	for _, entry := range existing {
+		for _, key := range importKeys(entry) {
+			seen[key] = true
+		}
+	}
+
+	for i := range result.Items {
+		item := &result.Items[i]
+		if item.Action != ImportNew {
+			continue
+		}
+		keys := importKeys(item.Entry)
+		if seen[keys[0]] || seen[keys[1]] {
+			item.Action = ImportDuplicate
+			continue
+		}
+		if !dryRun {
+			if err := storage.SaveEntry(item.Entry); err != nil {
+				item.Action, item.Err = ImportFailed, err
+				continue
+			}
+		}
+		seen[keys[0]], seen[keys[1]] = true, true
+	}
+	return result, nil
+}
+
+// importKeys identify an entry for duplicate detection by its time (to the minute) and
+// text. The time is compared as an instant, so an entry imported again in another time
+// zone is found, and as written in the header, for headers in a time zone unknown here:
+// they are read back with the wrong offset.
+func importKeys(entry *JournalEntry) [2]string {
+	body := strings.TrimSpace(strings.ReplaceAll(entry.Body, "\r\n", "\n"))
+	return [2]string{
+		entry.Timestamp.UTC().Truncate(time.Minute).Format(time.RFC3339) + "\n" + body,
+		FormatTimestamp(entry.Timestamp) + "\n" + body,
+	}
+}
+
+// tagFromName turns a tag name from another app into a jrnlg #tag, replacing characters
+// tags can't have (like spaces) with hyphens. Returns "" if nothing usable is left.
+func tagFromName(name string) string {
+	tag := strings.Trim(invalidTagChars.ReplaceAllString(strings.TrimPrefix(name, "#"), "-"), "-")
+	if patterns.Tag.FindString("#"+tag) != "#"+tag {
+		return ""
+	}
+	return "#" + tag
+}
+
+// invalidTagChars matches runs of characters tags can't contain
+var invalidTagChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// splitNames splits a list of tag names into names, without quotes: at commas if it has
+// any, else at spaces
+func splitNames(value string) []string {
+	fields := strings.Fields(value)
+	if strings.Contains(value, ",") {
+		fields = strings.Split(value, ",")
+	}
+	var names []string
+	for _, name := range fields {
+		if name = strings.Trim(strings.TrimSpace(name), `"'`); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// appendTags adds the tags that aren't in body yet on a line of their own
+// Tags are given with their symbol (#tag or @mention), and match whole tags of the body
+// like ParseEntry reads them: #go isn't in a body tagged #golang.
+func appendTags(body string, tags []string) string {
+	present := make(map[string]bool)
+	for _, match := range patterns.Tag.FindAllStringSubmatch(body, -1) {
+		present["#"+strings.ToLower(match[1])] = true
+	}
+	for _, match := range patterns.Mention.FindAllStringSubmatch(body, -1) {
+		present["@"+strings.ToLower(match[1])] = true
+	}
+
+	var missing []string
+	for _, tag := range tags {
+		if key := strings.ToLower(tag); tag != "" && !present[key] {
+			present[key] = true
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return body
+	}
+	return fmt.Sprintf("%s\n\n%s", strings.TrimRight(body, "\n "), strings.Join(missing, " "))
+}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestImportEntries(t *testing.T) {
+	storage := NewMemoryStorage()
+	ts := time.Date(2024, 2, 9, 14, 30, 0, 0, time.UTC)
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Already here"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	items := []ImportItem{
+		importedEntry("entry 1", ts.Add(20*time.Second), "Already here"),
+		importedEntry("entry 2", ts, "Same time, other text #new"),
+		importedEntry("entry 3", ts.Add(time.Hour), "Later"),
+		importedEntry("entry 4", ts.Add(time.Hour), "Later"),
+		importedEntry("entry 5", ts, "   "),
+		failedImport("entry 6", errors.New("unreadable")),
+	}
+
+	// A dry run saves nothing
+	result, err := ImportEntries(storage, items, true)
+	if err != nil {
+		t.Fatalf("ImportEntries(dry run) error = %v", err)
+	}
+	if result.Count(ImportNew) != 2 || result.Count(ImportDuplicate) != 2 || result.Count(ImportFailed) != 2 {
+		t.Errorf("dry run result = %+v", result.Items)
+	}
+	if entries, _ := storage.ListEntries(EntryFilter{}); len(entries) != 1 {
+		t.Fatalf("dry run saved entries: %d in the journal", len(entries))
+	}
+
+	if _, err := ImportEntries(storage, items, false); err != nil {
+		t.Fatalf("ImportEntries() error = %v", err)
+	}
+	entries, _ := storage.ListEntries(EntryFilter{})
+	if len(entries) != 3 {
+		t.Errorf("journal has %d entries after import, want 3", len(entries))
+	}
+
+	// Importing again finds everything already there
+	result, err = ImportEntries(storage, items, false)
+	if err != nil {
+		t.Fatalf("ImportEntries() again error = %v", err)
+	}
+	if result.Count(ImportNew) != 0 || result.Count(ImportDuplicate) != 4 {
+		t.Errorf("second import result = %+v", result.Items)
+	}
+}
+
+func TestImportEntries_AcrossTimeZones(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	local := time.Local
+	time.Local = paris
+	t.Cleanup(func() { time.Local = local })
+
+	// Headers are read back from the files, so times keep only what the header says
+	storage := NewFileSystemStorage(t.TempDir(), nil)
+	ts := time.Date(2024, 7, 1, 14, 30, 0, 0, paris)
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Written in Paris"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	items := []ImportItem{
+		importedEntry("same zone", ts, "Written in Paris"),
+		importedEntry("other zone", ts.In(newYork), "Written in Paris"),
+		importedEntry("utc", ts.UTC(), "Written in Paris"), // As jrnlg search --format json gives it elsewhere
+		importedEntry("other time", ts.In(newYork).Add(time.Hour), "Written in Paris"),
+	}
+	result, err := ImportEntries(storage, items, false)
+	if err != nil {
+		t.Fatalf("ImportEntries() error = %v", err)
+	}
+	for _, item := range result.Items[:3] {
+		if item.Action != ImportDuplicate {
+			t.Errorf("%s: action = %s, want duplicate", item.Source, item.Action)
+		}
+	}
+	if result.Items[3].Action != ImportNew {
+		t.Errorf("other time: action = %s, want import", result.Items[3].Action)
+	}
+
+	// Importing the entries of the journal into it again finds them all
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ListEntries() = %v, %v", entries, err)
+	}
+	items = nil
+	for _, entry := range entries {
+		items = append(items, importedEntry(FormatTimestamp(entry.Timestamp), entry.Timestamp, entry.Body))
+	}
+	if result, err = ImportEntries(storage, items, false); err != nil || result.Count(ImportDuplicate) != 2 {
+		t.Errorf("second import = %+v, %v", result, err)
+	}
+}
+
+func TestAppendTags(t *testing.T) {
+	tests := []struct {
+		body string
+		tags []string
+		want string
+	}{
+		{"Met @Alice", []string{"@alice"}, "Met @Alice"},
+		{"Notes\n", []string{"#work", "@bob"}, "Notes\n\n#work @bob"},
+		{"Notes", nil, "Notes"},
+		{"Learning #golang", []string{"#go"}, "Learning #golang\n\n#go"},
+		{"Mail bob@example.com", []string{"@example"}, "Mail bob@example.com\n\n@example"},
+		{"Notes #Work", []string{"#work", "#home", "#home"}, "Notes #Work\n\n#home"},
+	}
+	for _, tt := range tests {
+		if got := appendTags(tt.body, tt.tags); got != tt.want {
+			t.Errorf("appendTags(%q, %v) = %q, want %q", tt.body, tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestTagFromName(t *testing.T) {
+	tests := map[string]string{
+		"work":          "#work",
+		"Road Trip":     "#Road-Trip",
+		"#family":       "#family",
+		"  café & co  ": "#caf-co",
+		"2024":          "",
+		"!!!":           "",
+	}
+	for name, want := range tests {
+		if got := tagFromName(name); got != want {
+			t.Errorf("tagFromName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
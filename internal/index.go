@@ -84,29 +84,82 @@ func (idx *Index) Build(files []string, maxWorkers int, parseFunc func(string) (
 			// Skip invalid files
 			continue
 		}
+		idx.add(res.filePath, res.entry)
+	}
 
-		indexed := &IndexedEntry{
-			FilePath:  res.filePath,
-			Timestamp: res.entry.Timestamp,
-			Tags:      res.entry.Tags,
-			Mentions:  res.entry.Mentions,
-		}
+	return nil
+}
 
-		idx.entries = append(idx.entries, indexed)
-		idx.bodyMap[res.filePath] = res.entry.Body
+// Update re-indexes the given files after they changed on disk
+// Files that no longer exist or can't be parsed are removed from the index
+func (idx *Index) Update(files []string, parseFunc func(string) (*JournalEntry, error)) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
 
-		// Build tag index
-		for _, tag := range res.entry.Tags {
-			idx.tagIndex[tag] = append(idx.tagIndex[tag], indexed)
+	for _, filePath := range files {
+		idx.remove(filePath)
+		if entry, err := parseFunc(filePath); err == nil {
+			idx.add(filePath, entry)
 		}
+	}
+}
+
+// add indexes a parsed entry (caller must hold the write lock)
+func (idx *Index) add(filePath string, entry *JournalEntry) {
+	indexed := &IndexedEntry{
+		FilePath:  filePath,
+		Timestamp: entry.Timestamp,
+		Tags:      entry.Tags,
+		Mentions:  entry.Mentions,
+	}
+
+	idx.entries = append(idx.entries, indexed)
+	idx.bodyMap[filePath] = entry.Body
+
+	// Build tag index
+	for _, tag := range entry.Tags {
+		idx.tagIndex[tag] = append(idx.tagIndex[tag], indexed)
+	}
 
-		// Build mention index
-		for _, mention := range res.entry.Mentions {
-			idx.mentionIndex[mention] = append(idx.mentionIndex[mention], indexed)
+	// Build mention index
+	for _, mention := range entry.Mentions {
+		idx.mentionIndex[mention] = append(idx.mentionIndex[mention], indexed)
+	}
+}
+
+// remove drops a file from the index (caller must hold the write lock)
+func (idx *Index) remove(filePath string) {
+	if _, ok := idx.bodyMap[filePath]; !ok {
+		return
+	}
+	delete(idx.bodyMap, filePath)
+
+	idx.entries = removeIndexed(idx.entries, filePath)
+	for tag, entries := range idx.tagIndex {
+		if entries = removeIndexed(entries, filePath); len(entries) > 0 {
+			idx.tagIndex[tag] = entries
+		} else {
+			delete(idx.tagIndex, tag)
 		}
 	}
+	for mention, entries := range idx.mentionIndex {
+		if entries = removeIndexed(entries, filePath); len(entries) > 0 {
+			idx.mentionIndex[mention] = entries
+		} else {
+			delete(idx.mentionIndex, mention)
+		}
+	}
+}
 
-	return nil
+// removeIndexed returns entries without the one stored at filePath
+func removeIndexed(entries []*IndexedEntry, filePath string) []*IndexedEntry {
+	kept := make([]*IndexedEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.FilePath != filePath {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
 }
 
 // SearchByTags finds entries that have ALL the specified tags (AND logic)
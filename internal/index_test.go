@@ -515,3 +515,48 @@ func TestIndex_GetAllEntries(t *testing.T) {
 		t.Errorf("GetAllEntries should return a copy, not original slice")
 	}
 }
+
+func TestIndex_Update(t *testing.T) {
+	tmpDir := t.TempDir()
+	ts := time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC)
+
+	write := func(name, body string) string {
+		path := filepath.Join(tmpDir, name)
+		_ = os.WriteFile(path, []byte(SerializeEntry(&JournalEntry{Timestamp: ts, Body: body})), 0644)
+		return path
+	}
+	parseFunc := func(path string) (*JournalEntry, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return ParseEntry(string(content))
+	}
+
+	changed := write("a.md", "About #k8s")
+	kept := write("b.md", "About #k8s too")
+
+	index := NewIndex()
+	if err := index.Build([]string{changed, kept}, 2, parseFunc); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// Modified and new files are re-indexed, removed files dropped
+	write("a.md", "About #kubernetes")
+	added := write("c.md", "New #kubernetes entry")
+	_ = os.Remove(kept)
+	index.Update([]string{changed, added, kept}, parseFunc)
+
+	if got := len(index.SearchByTags([]string{"k8s"})); got != 0 {
+		t.Errorf("SearchByTags(k8s) = %d entries, want 0", got)
+	}
+	if got := len(index.SearchByTags([]string{"kubernetes"})); got != 2 {
+		t.Errorf("SearchByTags(kubernetes) = %d entries, want 2", got)
+	}
+	if _, ok := index.tagIndex["k8s"]; ok {
+		t.Error("empty tag should be removed from the tag index")
+	}
+	if len(index.GetAllEntries()) != 2 {
+		t.Errorf("GetAllEntries() = %d entries, want 2", len(index.GetAllEntries()))
+	}
+}
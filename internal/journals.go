@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// JournalSummary describes the entries of a journal
+type JournalSummary struct {
+	Entries int
+	Latest  time.Time // Timestamp of the newest entry; zero for an empty journal
+}
+
+// SummarizeJournal counts the entries of a journal and finds the newest one
+// Filesystem journals are summarized from entry file names, so they aren't decrypted
+func SummarizeJournal(storage Storage) (JournalSummary, error) {
+	var summary JournalSummary
+
+	if fs, ok := storage.(*FileSystemStorage); ok {
+		files, err := fs.findFiles(EntryFilter{})
+		if err != nil {
+			return summary, err
+		}
+		for _, file := range files {
+			ts, _, ok := parseEntryFileName(filepath.Base(file))
+			if !ok {
+				continue
+			}
+			timestamp, err := time.Parse(FileNameTimestampLayout, ts)
+			if err != nil {
+				continue
+			}
+			summary.Entries++
+			if timestamp.After(summary.Latest) {
+				summary.Latest = timestamp
+			}
+		}
+		return summary, nil
+	}
+
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil {
+		return summary, err
+	}
+	summary.Entries = len(entries)
+	if len(entries) > 0 {
+		summary.Latest = entries[len(entries)-1].Timestamp
+	}
+	return summary, nil
+}
+
+// StorageLocation describes where the configured journal is stored: its directory, jrnl file
+// or WebDAV URL
+func (c *Config) StorageLocation() string {
+	switch c.Backend {
+	case BackendJrnl:
+		return c.JrnlFile
+	case BackendWebDAV:
+		return c.WebDAVURL
+	}
+	return c.StoragePath
+}
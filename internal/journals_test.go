@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeJournal(t *testing.T) {
+	timestamps := []time.Time{
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 2, 18, 45, 0, 0, time.UTC),
+		time.Date(2023, 12, 31, 23, 59, 0, 0, time.UTC),
+	}
+
+	for name, storage := range map[string]Storage{
+		"filesystem": NewFileSystemStorage(t.TempDir(), nil),
+		"memory":     NewMemoryStorage(),
+	} {
+		summary, err := SummarizeJournal(storage)
+		if err != nil || summary.Entries != 0 || !summary.Latest.IsZero() {
+			t.Errorf("%s: SummarizeJournal() of empty journal = %+v, %v", name, summary, err)
+		}
+
+		for _, ts := range timestamps {
+			if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Entry"}); err != nil {
+				t.Fatalf("%s: SaveEntry() error = %v", name, err)
+			}
+		}
+		summary, err = SummarizeJournal(storage)
+		if err != nil {
+			t.Fatalf("%s: SummarizeJournal() error = %v", name, err)
+		}
+		if summary.Entries != 3 || !summary.Latest.Equal(timestamps[1]) {
+			t.Errorf("%s: SummarizeJournal() = %+v, want 3 entries, latest %v", name, summary, timestamps[1])
+		}
+	}
+}
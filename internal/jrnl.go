@@ -0,0 +1,355 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JrnlTimeFormat is the default time format of jrnl journals (local time)
+const JrnlTimeFormat = "2006-01-02 15:04"
+
+// jrnlHeader matches the first line of a jrnl entry: [2024-02-09 14:30] Title
+var jrnlHeader = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\](?: (.*))?$`)
+
+// JrnlStorage stores the journal in a single plain-text file in the format of jrnl:
+//
+//	[2024-02-09 14:30] The title line of the entry. *
+//	The rest of the entry.
+//
+// Timestamps are local time without seconds, and a trailing " *" marks a starred entry.
+// Entries are served from memory; every change reloads the file if it was changed
+// elsewhere, applies the change and rewrites the whole file atomically. Stars are kept
+// when entries are edited.
+type JrnlStorage struct {
+	*MemoryStorage
+	path     string
+	starred  map[string]bool // Paths of starred entries
+	loaded   []byte          // File content as last read or written
+	changeMu sync.Mutex
+}
+
+// OpenJrnlStorage reads a jrnl journal file. A missing file is an empty journal; it is
+// created when the first entry is saved.
+func OpenJrnlStorage(path string) (*JrnlStorage, error) {
+	j := &JrnlStorage{path: path}
+	if err := j.reload(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Path returns the path of the journal file
+func (j *JrnlStorage) Path() string {
+	return j.path
+}
+
+// IsStarred reports whether the entry at path is starred
+func (j *JrnlStorage) IsStarred(path string) bool {
+	j.changeMu.Lock()
+	defer j.changeMu.Unlock()
+	return j.starred[path]
+}
+
+// reload reads the journal file if it changed since it was last read or written
+func (j *JrnlStorage) reload() error {
+	content, err := os.ReadFile(j.path)
+	if os.IsNotExist(err) {
+		content, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+	if j.MemoryStorage != nil && bytes.Equal(content, j.loaded) {
+		return nil
+	}
+
+	entries, starred, err := ParseJrnl(string(content))
+	if err != nil {
+		return fmt.Errorf("%s: %w", j.path, err)
+	}
+
+	memory := NewMemoryStorage()
+	j.starred = make(map[string]bool)
+	for i, entry := range entries {
+		path, err := memory.saveEntry(entry)
+		if err != nil {
+			return fmt.Errorf("%s: %w", j.path, err)
+		}
+		if starred[i] {
+			j.starred[path] = true
+		}
+	}
+
+	j.MemoryStorage = memory
+	j.loaded = content
+	return nil
+}
+
+// change applies fn to the latest version of the journal and writes the result
+// The file is written even if fn fails part way, so changes already made are kept
+func (j *JrnlStorage) change(fn func() error) error {
+	j.changeMu.Lock()
+	defer j.changeMu.Unlock()
+
+	if err := j.reload(); err != nil {
+		return err
+	}
+	err := fn()
+
+	content := j.serialize()
+	if bytes.Equal(content, j.loaded) {
+		return err
+	}
+	if mkdirErr := os.MkdirAll(filepath.Dir(j.path), DirPermissions); mkdirErr != nil {
+		return mkdirErr
+	}
+	if writeErr := replaceFile(j.path, content); writeErr != nil {
+		return fmt.Errorf("failed to write %s: %w", j.path, writeErr)
+	}
+	j.loaded = content
+	return err
+}
+
+// serialize renders the journal in jrnl's format, oldest entry first
+func (j *JrnlStorage) serialize() []byte {
+	type stored struct {
+		path  string
+		entry *JournalEntry
+	}
+	var entries []stored
+	for _, path := range j.paths() {
+		if entry, err := j.GetEntryByPath(path); err == nil {
+			entries = append(entries, stored{path, entry})
+		}
+	}
+	sort.SliceStable(entries, func(i, k int) bool {
+		return entries[i].entry.Timestamp.Before(entries[k].entry.Timestamp)
+	})
+
+	var sb strings.Builder
+	starred := make(map[string]bool)
+	for i, e := range entries {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(FormatJrnlEntry(e.entry, j.starred[e.path]))
+		if j.starred[e.path] {
+			starred[e.path] = true
+		}
+	}
+	j.starred = starred // Forget stars of deleted entries
+	return []byte(sb.String())
+}
+
+// SaveEntry adds an entry to the journal file
+func (j *JrnlStorage) SaveEntry(entry *JournalEntry) error {
+	return j.change(func() error { return j.MemoryStorage.SaveEntry(entry) })
+}
+
+// UpdateEntry replaces the entry at path in the journal file
+func (j *JrnlStorage) UpdateEntry(path string, entry *JournalEntry) error {
+	return j.change(func() error { return j.MemoryStorage.UpdateEntry(path, entry) })
+}
+
+// UpdateEntryIfUnchanged replaces an entry only if it wasn't changed since expected,
+// including by another process writing the journal file
+func (j *JrnlStorage) UpdateEntryIfUnchanged(path string, entry *JournalEntry, expected EntryVersion) error {
+	return j.change(func() error { return j.MemoryStorage.UpdateEntryIfUnchanged(path, entry, expected) })
+}
+
+// DeleteEntry removes the entry at path from the journal file
+func (j *JrnlStorage) DeleteEntry(path string) error {
+	return j.change(func() error { return j.MemoryStorage.DeleteEntry(path) })
+}
+
+// DeleteEntries removes the entries matching filter from the journal file
+func (j *JrnlStorage) DeleteEntries(filter EntryFilter) ([]string, error) {
+	var deleted []string
+	err := j.change(func() (err error) {
+		deleted, err = j.MemoryStorage.DeleteEntries(filter)
+		return err
+	})
+	return deleted, err
+}
+
+// ReplaceTagInEntries replaces oldTag with newTag in all entries
+func (j *JrnlStorage) ReplaceTagInEntries(oldTag, newTag string, dryRun bool) ([]string, error) {
+	var updated []string
+	err := j.change(func() (err error) {
+		updated, err = j.MemoryStorage.ReplaceTagInEntries(oldTag, newTag, dryRun)
+		return err
+	})
+	return updated, err
+}
+
+// ReplaceMentionInEntries replaces oldMention with newMention in all entries
+func (j *JrnlStorage) ReplaceMentionInEntries(oldMention, newMention string, dryRun bool) ([]string, error) {
+	var updated []string
+	err := j.change(func() (err error) {
+		updated, err = j.MemoryStorage.ReplaceMentionInEntries(oldMention, newMention, dryRun)
+		return err
+	})
+	return updated, err
+}
+
+// ParseJrnl parses a jrnl plain-text journal. Returns the entries in file order and
+// which of them are starred.
+func ParseJrnl(content string) ([]*JournalEntry, []bool, error) {
+	var entries []*JournalEntry
+	var starred []bool
+	var body []string
+	headerLine := 0
+
+	finish := func() error {
+		if len(entries) == 0 {
+			return nil
+		}
+		text := strings.TrimSpace(strings.Join(body, "\n"))
+		if text == "" {
+			return fmt.Errorf("line %d: entry has no text", headerLine)
+		}
+		entries[len(entries)-1].Body = text
+		return nil
+	}
+
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		match := jrnlHeader.FindStringSubmatch(line)
+		if match == nil {
+			if len(entries) == 0 && strings.TrimSpace(line) != "" {
+				return nil, nil, fmt.Errorf("line %d: expected an entry starting with [YYYY-MM-DD HH:MM] (encrypted jrnl journals aren't supported)", i+1)
+			}
+			body = append(body, line)
+			continue
+		}
+
+		if err := finish(); err != nil {
+			return nil, nil, err
+		}
+		timestamp, err := time.ParseInLocation(JrnlTimeFormat, match[1], time.Local)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		title := strings.TrimRight(match[2], " ")
+		star := title == "*" || strings.HasSuffix(title, " *")
+		if star {
+			title = strings.TrimRight(strings.TrimSuffix(title, "*"), " ")
+		}
+
+		entries = append(entries, &JournalEntry{Timestamp: timestamp})
+		starred = append(starred, star)
+		body = []string{title}
+		headerLine = i + 1
+	}
+
+	if err := finish(); err != nil {
+		return nil, nil, err
+	}
+	return entries, starred, nil
+}
+
+// FormatJrnlEntry renders an entry in jrnl's format: the first line of the body goes on
+// the header line, the rest follows it
+func FormatJrnlEntry(entry *JournalEntry, starred bool) string {
+	title, rest, _ := strings.Cut(strings.TrimSpace(entry.Body), "\n")
+
+	var sb strings.Builder
+	sb.WriteString("[" + entry.Timestamp.In(time.Local).Format(JrnlTimeFormat) + "] " + title)
+	if starred {
+		sb.WriteString(" *")
+	}
+	sb.WriteString("\n")
+	if rest = strings.TrimRight(rest, "\n "); rest != "" {
+		sb.WriteString(rest + "\n")
+	}
+	return sb.String()
+}
+
+// jrnlTagSymbols are the symbols jrnl starts tags with by default (its tagsymbols
+// setting). Tags with other symbols lose them in tagFromName.
+const jrnlTagSymbols = "#@"
+
+// jrnlExport is a journal exported with jrnl --format json
+type jrnlExport struct {
+	Entries []struct {
+		Title   string   `json:"title"`
+		Body    string   `json:"body"`
+		Date    string   `json:"date"`
+		Time    string   `json:"time"`
+		Tags    []string `json:"tags"`
+		Starred bool     `json:"starred"`
+	} `json:"entries"`
+}
+
+// ParseJrnlExport reads the entries of a jrnl journal file or plain-text export, or of a
+// JSON export (jrnl --format json). Starred entries get a #starred tag, and the tags of
+// JSON exports, @tags included, are added as #tags.
+func ParseJrnlExport(content []byte) ([]ImportItem, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		entries, starred, err := ParseJrnl(string(content))
+		if err != nil {
+			return nil, err
+		}
+		items := make([]ImportItem, len(entries))
+		for i, entry := range entries {
+			body := entry.Body
+			if starred[i] {
+				body = appendTags(body, []string{"#" + StarredTag})
+			}
+			source := "[" + entry.Timestamp.Format(JrnlTimeFormat) + "]"
+			items[i] = importedEntry(source, entry.Timestamp, body)
+		}
+		return items, nil
+	}
+
+	var export jrnlExport
+	if err := json.Unmarshal(content, &export); err != nil {
+		return nil, fmt.Errorf("invalid jrnl JSON export: %w", err)
+	}
+	if export.Entries == nil {
+		return nil, fmt.Errorf("not a jrnl JSON export: no entries")
+	}
+
+	items := make([]ImportItem, len(export.Entries))
+	for i, e := range export.Entries {
+		source := fmt.Sprintf("entry %d", i+1)
+		timestamp, err := time.ParseInLocation(JrnlTimeFormat, e.Date+" "+e.Time, time.Local)
+		if err != nil {
+			timestamp, err = time.ParseInLocation("2006-01-02 15:04:05", e.Date+" "+e.Time, time.Local)
+		}
+		if err != nil {
+			items[i] = failedImport(source, fmt.Errorf("invalid date %q and time %q", e.Date, e.Time))
+			continue
+		}
+
+		// jrnl splits the title off at the end of the first sentence or line
+		body := strings.TrimSpace(e.Title)
+		if rest := strings.TrimSpace(e.Body); rest != "" {
+			separator := "\n"
+			if strings.HasSuffix(body, ".") || strings.HasSuffix(body, "?") || strings.HasSuffix(body, "!") {
+				separator = " "
+			}
+			body += separator + rest
+		}
+
+		// jrnl counts @words as tags too, so every tag of the export becomes a #tag; the
+		// text keeps its @words as mentions
+		tags := make([]string, 0, len(e.Tags)+1)
+		for _, tag := range e.Tags {
+			tags = append(tags, tagFromName(strings.TrimLeft(tag, jrnlTagSymbols)))
+		}
+		if e.Starred {
+			tags = append(tags, "#"+StarredTag)
+		}
+		items[i] = importedEntry(source, timestamp, appendTags(body, tags))
+	}
+	return items, nil
+}
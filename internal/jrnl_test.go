@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testJrnlJournal = `[2024-02-09 14:30] Met with @alice about #work. *
+We agreed on the plan.
+
+Second paragraph.
+
+[2024-02-10 09:00] Quick note
+`
+
+func TestParseJrnl(t *testing.T) {
+	entries, starred, err := ParseJrnl(testJrnlJournal)
+	if err != nil {
+		t.Fatalf("ParseJrnl() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ParseJrnl() = %d entries, want 2", len(entries))
+	}
+
+	want := time.Date(2024, 2, 9, 14, 30, 0, 0, time.Local)
+	if !entries[0].Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", entries[0].Timestamp, want)
+	}
+	if entries[0].Body != "Met with @alice about #work.\nWe agreed on the plan.\n\nSecond paragraph." {
+		t.Errorf("Body = %q", entries[0].Body)
+	}
+	if !starred[0] || starred[1] {
+		t.Errorf("starred = %v, want [true false]", starred)
+	}
+
+	for name, content := range map[string]string{
+		"text before the first entry": "My journal\n[2024-02-09 14:30] Entry\n",
+		"empty entry":                 "[2024-02-09 14:30]\n\n[2024-02-10 09:00] Entry\n",
+		"encrypted":                   "gAAAAABf0xyz\n",
+	} {
+		if _, _, err := ParseJrnl(content); err == nil {
+			t.Errorf("ParseJrnl(%s): expected error", name)
+		}
+	}
+}
+
+func TestFormatJrnlEntry(t *testing.T) {
+	entry := &JournalEntry{Timestamp: time.Date(2024, 2, 9, 14, 30, 0, 0, time.Local), Body: "Title line\nMore text."}
+	if got := FormatJrnlEntry(entry, true); got != "[2024-02-09 14:30] Title line *\nMore text.\n" {
+		t.Errorf("FormatJrnlEntry() = %q", got)
+	}
+}
+
+func TestJrnlStorage_ReadAndRewrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.txt")
+	if err := os.WriteFile(path, []byte(testJrnlJournal), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	storage, err := OpenJrnlStorage(path)
+	if err != nil {
+		t.Fatalf("OpenJrnlStorage() error = %v", err)
+	}
+	found, err := storage.SearchByTags([]string{"work"}, EntryFilter{})
+	if err != nil || len(found) != 1 {
+		t.Fatalf("SearchByTags() = %d entries, %v; want 1", len(found), err)
+	}
+
+	// Edits keep the star, new entries are added in order
+	first, _ := storage.GetEntryPath(time.Date(2024, 2, 9, 14, 30, 0, 0, time.Local))
+	if err := storage.UpdateEntry(first, &JournalEntry{Timestamp: found[0].Timestamp, Body: "Met with @alice about #work.\nPlan agreed."}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2024, 2, 9, 20, 0, 0, 0, time.Local), Body: "Evening."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	want := "[2024-02-09 14:30] Met with @alice about #work. *\nPlan agreed.\n\n[2024-02-09 20:00] Evening.\n\n[2024-02-10 09:00] Quick note\n"
+	if string(content) != want {
+		t.Errorf("journal file =\n%s\nwant\n%s", content, want)
+	}
+	if !storage.IsStarred(first) {
+		t.Error("IsStarred() = false after edit")
+	}
+
+	// Another process sees the same journal
+	reopened, err := OpenJrnlStorage(path)
+	if err != nil {
+		t.Fatalf("OpenJrnlStorage() error = %v", err)
+	}
+	if entries, _ := reopened.ListEntries(EntryFilter{}); len(entries) != 3 {
+		t.Errorf("ListEntries() = %d entries, want 3", len(entries))
+	}
+}
+
+func TestJrnlStorage_DetectsChangesByOthers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.txt")
+	storage, err := OpenJrnlStorage(path)
+	if err != nil {
+		t.Fatalf("OpenJrnlStorage() of a missing file error = %v", err)
+	}
+	ts := time.Date(2024, 2, 9, 14, 30, 0, 0, time.Local)
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Original."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	entryPath, _ := storage.GetEntryPath(ts)
+	version, err := storage.GetEntryVersion(entryPath)
+	if err != nil {
+		t.Fatalf("GetEntryVersion() error = %v", err)
+	}
+
+	// Edited by someone else (e.g. jrnl itself)
+	if err := os.WriteFile(path, []byte("[2024-02-09 14:30] Changed by jrnl.\n"), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	err = storage.UpdateEntryIfUnchanged(entryPath, &JournalEntry{Timestamp: ts, Body: "Mine."}, version)
+	if !errors.Is(err, ErrEntryModified) {
+		t.Errorf("UpdateEntryIfUnchanged() error = %v, want ErrEntryModified", err)
+	}
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "Changed by jrnl.") {
+		t.Errorf("journal file overwritten: %s", content)
+	}
+}
+
+func TestParseJrnlExport(t *testing.T) {
+	items, err := ParseJrnlExport([]byte(testJrnlJournal))
+	if err != nil {
+		t.Fatalf("ParseJrnlExport(text) error = %v", err)
+	}
+	if len(items) != 2 || items[0].Action != ImportNew || items[0].Source != "[2024-02-09 14:30]" {
+		t.Fatalf("ParseJrnlExport(text) = %+v", items)
+	}
+	if !reflect.DeepEqual(items[0].Entry.Tags, []string{StarredTag, "work"}) {
+		t.Errorf("starred entry Tags = %v, want work and starred", items[0].Entry.Tags)
+	}
+
+	export := `{"tags": {"@alice": 1, "#work": 1}, "entries": [
+		{"title": "Met with @alice.", "body": "We agreed on the plan.", "date": "2024-02-09", "time": "14:30", "tags": ["@alice", "+work"], "starred": true},
+		{"title": "Quick note #golang", "body": "", "date": "2024-02-10", "time": "09:00", "tags": ["#golang", "#go", "@Bob"], "starred": false},
+		{"title": "Bad date", "body": "", "date": "2024-02-30", "time": "09:00", "tags": [], "starred": false}
+	]}`
+	items, err = ParseJrnlExport([]byte(export))
+	if err != nil {
+		t.Fatalf("ParseJrnlExport(json) error = %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("ParseJrnlExport(json) = %d items, want 3", len(items))
+	}
+	if body := items[0].Entry.Body; body != "Met with @alice. We agreed on the plan.\n\n#alice #work #starred" {
+		t.Errorf("Body = %q", body)
+	}
+	if !reflect.DeepEqual(items[0].Entry.Tags, []string{"alice", StarredTag, "work"}) || !reflect.DeepEqual(items[0].Entry.Mentions, []string{"alice"}) {
+		t.Errorf("Tags = %v, Mentions = %v; want the @tag as a tag and a mention", items[0].Entry.Tags, items[0].Entry.Mentions)
+	}
+	if want := time.Date(2024, 2, 9, 14, 30, 0, 0, time.Local); !items[0].Entry.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", items[0].Entry.Timestamp, want)
+	}
+	if items[1].Entry.Body != "Quick note #golang\n\n#go #Bob" {
+		t.Errorf("Body = %q", items[1].Entry.Body)
+	}
+	if items[2].Action != ImportFailed || items[2].Source != "entry 3" {
+		t.Errorf("item with bad date = %+v, want failed", items[2])
+	}
+
+	if _, err := ParseJrnlExport([]byte(`{"title": "not an export"}`)); err == nil {
+		t.Error("ParseJrnlExport() of other JSON: expected error")
+	}
+}
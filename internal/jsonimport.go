@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// jrnlgJSONEntry is an entry of the JSON output of jrnlg search --format json
+type jrnlgJSONEntry struct {
+	Timestamp string   `json:"timestamp"`
+	Tags      []string `json:"tags"`
+	Mentions  []string `json:"mentions"`
+	Body      string   `json:"body"`
+}
+
+// ParseJSONExport reads the entries of jrnlg's own JSON output (jrnlg search --format
+// json), so entries can be moved between journals. The journal of each entry is left
+// out: entries go to the journal imported into.
+//
+// Timestamps keep their time zone when it is the local one or UTC. The output only gives
+// the offset of others, and a header can't name a bare offset ("2:30 PM +0200" doesn't
+// read back), so they are moved to local time: the same moment, which ImportEntries
+// compares, so importing again still finds them.
+func ParseJSONExport(content []byte) ([]ImportItem, error) {
+	var entries []jrnlgJSONEntry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("invalid jrnlg JSON (from jrnlg search --format json): %w", err)
+	}
+
+	items := make([]ImportItem, len(entries))
+	for i, e := range entries {
+		source := fmt.Sprintf("entry %d", i+1)
+		timestamp, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			items[i] = failedImport(source, fmt.Errorf("invalid timestamp %q", e.Timestamp))
+			continue
+		}
+		if timestamp.Location() != time.Local && timestamp.Location() != time.UTC {
+			timestamp = timestamp.Local()
+		}
+
+		// Tags and mentions come from the body; any that aren't there are kept
+		tags := make([]string, 0, len(e.Tags)+len(e.Mentions))
+		for _, tag := range e.Tags {
+			tags = append(tags, "#"+tag)
+		}
+		for _, mention := range e.Mentions {
+			tags = append(tags, "@"+mention)
+		}
+		items[i] = importedEntry(source, timestamp, appendTags(e.Body, tags))
+	}
+	return items, nil
+}
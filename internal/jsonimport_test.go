@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseJSONExport(t *testing.T) {
+	content := `[
+  {"timestamp": "2024-02-09T14:30:00Z", "journal": "work", "tags": ["meeting"], "mentions": [], "body": "Standup #meeting"},
+  {"timestamp": "2024-02-09T18:00:00+05:30", "tags": ["travel"], "mentions": ["bob"], "body": "Arrived"},
+  {"timestamp": "Friday", "tags": [], "mentions": [], "body": "No time"},
+  {"timestamp": "2024-07-01T14:30:00+02:00", "tags": [], "mentions": [], "body": "In Paris"}
+]`
+	items, err := ParseJSONExport([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseJSONExport() error = %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("got %d items, want 4", len(items))
+	}
+
+	if items[0].Action != ImportNew || items[0].Entry.Timestamp.Location() != time.UTC || items[0].Entry.Body != "Standup #meeting" {
+		t.Errorf("entry 1 = %+v", items[0].Entry)
+	}
+
+	// An offset without a time zone name is moved to local time; missing tags are added
+	second := items[1].Entry
+	if !second.Timestamp.Equal(time.Date(2024, 2, 9, 12, 30, 0, 0, time.UTC)) || second.Timestamp.Location() != time.Local {
+		t.Errorf("entry 2 timestamp = %v", second.Timestamp)
+	}
+	if second.Body != "Arrived\n\n#travel @bob" {
+		t.Errorf("entry 2 body = %q", second.Body)
+	}
+
+	// Other offsets too, at the same moment, in a header that reads back
+	fourth := items[3].Entry
+	if !fourth.Timestamp.Equal(time.Date(2024, 7, 1, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("entry 4 timestamp = %v", fourth.Timestamp)
+	}
+	header := FormatTimestamp(fourth.Timestamp)
+	if parsed, err := parseTimestamp(header); err != nil || !parsed.Equal(fourth.Timestamp) {
+		t.Errorf("header %q read back as %v, %v", header, parsed, err)
+	}
+
+	if items[2].Action != ImportFailed || !strings.Contains(items[2].Err.Error(), "invalid timestamp") {
+		t.Errorf("entry 3 = %+v", items[2])
+	}
+
+	if _, err := ParseJSONExport([]byte(`{"entries": []}`)); err == nil {
+		t.Error("ParseJSONExport() of an object: expected error")
+	}
+}
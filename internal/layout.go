@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout determines where entry files are placed inside the storage directory
+type Layout string
+
+const (
+	// LayoutYearMonth stores entries as <year>/<month>/YYYY-MM-DD-HH-MM-SS.md (default)
+	LayoutYearMonth Layout = "year-month"
+	// LayoutYearMonthDay stores entries as <year>/<month>/<day>/YYYY-MM-DD-HH-MM-SS.md
+	LayoutYearMonthDay Layout = "year-month-day"
+	// LayoutYearWeek stores entries as <ISO year>/W<ISO week>/YYYY-MM-DD-HH-MM-SS.md
+	LayoutYearWeek Layout = "year-week"
+	// LayoutFlat stores all entries directly in the storage directory
+	LayoutFlat Layout = "flat"
+	// LayoutSlug stores entries as <year>/<month>/YYYY-MM-DD-HH-MM-SS_<title-slug>.md
+	LayoutSlug Layout = "slug"
+)
+
+// Layouts lists all supported layouts
+var Layouts = []Layout{LayoutYearMonth, LayoutYearMonthDay, LayoutYearWeek, LayoutFlat, LayoutSlug}
+
+// ParseLayout validates a layout name
+func ParseLayout(s string) (Layout, error) {
+	for _, layout := range Layouts {
+		if string(layout) == s {
+			return layout, nil
+		}
+	}
+
+	names := make([]string, len(Layouts))
+	for i, layout := range Layouts {
+		names[i] = string(layout)
+	}
+	return "", fmt.Errorf("invalid layout %q: must be one of %s", s, strings.Join(names, ", "))
+}
+
+// orDefault returns the default layout for an unset layout
+func (l Layout) orDefault() Layout {
+	if l == "" {
+		return LayoutYearMonth
+	}
+	return l
+}
+
+// dir returns the directory for an entry with the given timestamp
+// Uses UTC time for consistent naming across timezones
+func (l Layout) dir(basePath string, timestamp time.Time) string {
+	utc := timestamp.UTC()
+	year := fmt.Sprintf("%04d", utc.Year())
+	month := fmt.Sprintf("%02d", int(utc.Month()))
+
+	switch l.orDefault() {
+	case LayoutFlat:
+		return basePath
+	case LayoutYearMonthDay:
+		return filepath.Join(basePath, year, month, fmt.Sprintf("%02d", utc.Day()))
+	case LayoutYearWeek:
+		isoYear, week := utc.ISOWeek()
+		return filepath.Join(basePath, fmt.Sprintf("%04d", isoYear), fmt.Sprintf("W%02d", week))
+	default: // LayoutYearMonth, LayoutSlug
+		return filepath.Join(basePath, year, month)
+	}
+}
+
+// fileName returns the filename for an entry
+// collision > 0 adds a -NN suffix; the slug layout appends a title slug from the body
+func (l Layout) fileName(timestamp time.Time, collision int, body string) string {
+	name := timestamp.UTC().Format(FileNameTimestampLayout)
+	if collision > 0 {
+		name += fmt.Sprintf("-%02d", collision)
+	}
+
+	if l.orDefault() == LayoutSlug {
+		if slug := Slugify(getTitle(body)); slug != "" {
+			name += slugSeparator + slug
+		}
+	}
+
+	return name + MarkdownExt
+}
+
+// slugSeparator separates the timestamp from the title slug in slug layout filenames
+const slugSeparator = "_"
+
+// entryFileName matches entry filenames in any layout: timestamp, optional collision suffix, optional slug
+var entryFileName = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2})(?:-(\d{2}))?(?:_.*)?\.md$`)
+
+// parseEntryFileName extracts the timestamp part and collision number from an entry filename
+func parseEntryFileName(name string) (timestamp string, collision int, ok bool) {
+	match := entryFileName.FindStringSubmatch(name)
+	if match == nil || isConflictCopy(name) {
+		return "", 0, false
+	}
+
+	if match[2] != "" {
+		collision, _ = strconv.Atoi(match[2])
+	}
+	return match[1], collision, true
+}
+
+// Slugify converts text to a lowercase, hyphen-separated filename fragment
+func Slugify(text string) string {
+	var sb strings.Builder
+	lastHyphen := true // Avoid leading hyphen
+
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			lastHyphen = false
+		} else if !lastHyphen {
+			sb.WriteRune('-')
+			lastHyphen = true
+		}
+
+		if sb.Len() >= MaxSlugLength {
+			break
+		}
+	}
+
+	return strings.Trim(sb.String(), "-")
+}
+
+// getTitle returns the first non-empty line of body text
+func getTitle(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
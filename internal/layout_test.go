@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLayoutPaths(t *testing.T) {
+	base := "/journal"
+	ts := time.Date(2026, 1, 1, 9, 5, 3, 0, time.UTC) // ISO week 1 of 2026
+	body := "Morning Pages: day one!\n\nMore text."
+
+	tests := []struct {
+		layout Layout
+		want   string
+	}{
+		{LayoutYearMonth, filepath.Join(base, "2026", "01", "2026-01-01-09-05-03.md")},
+		{LayoutYearMonthDay, filepath.Join(base, "2026", "01", "01", "2026-01-01-09-05-03.md")},
+		{LayoutYearWeek, filepath.Join(base, "2026", "W01", "2026-01-01-09-05-03.md")},
+		{LayoutFlat, filepath.Join(base, "2026-01-01-09-05-03.md")},
+		{LayoutSlug, filepath.Join(base, "2026", "01", "2026-01-01-09-05-03_morning-pages-day-one.md")},
+		{"", filepath.Join(base, "2026", "01", "2026-01-01-09-05-03.md")},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.layout), func(t *testing.T) {
+			got := filepath.Join(tt.layout.dir(base, ts), tt.layout.fileName(ts, 0, body))
+			if got != tt.want {
+				t.Errorf("path = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutYearWeek_ISOYear(t *testing.T) {
+	// Dec 29, 2025 belongs to ISO week 1 of 2026
+	ts := time.Date(2025, 12, 29, 12, 0, 0, 0, time.UTC)
+	want := filepath.Join("/journal", "2026", "W01")
+	if got := LayoutYearWeek.dir("/journal", ts); got != want {
+		t.Errorf("dir() = %q, want %q", got, want)
+	}
+}
+
+func TestLayoutFileName_Collision(t *testing.T) {
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+
+	if got := LayoutYearMonth.fileName(ts, 2, ""); got != "2026-02-09-14-30-00-02.md" {
+		t.Errorf("fileName() = %q", got)
+	}
+	if got := LayoutSlug.fileName(ts, 1, "Hello"); got != "2026-02-09-14-30-00-01_hello.md" {
+		t.Errorf("fileName() = %q", got)
+	}
+	// No title: no slug
+	if got := LayoutSlug.fileName(ts, 0, "  \n"); got != "2026-02-09-14-30-00.md" {
+		t.Errorf("fileName() = %q", got)
+	}
+}
+
+func TestParseEntryFileName(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp string
+		collision int
+		ok        bool
+	}{
+		{"2026-02-09-14-30-00.md", "2026-02-09-14-30-00", 0, true},
+		{"2026-02-09-14-30-00-03.md", "2026-02-09-14-30-00", 3, true},
+		{"2026-02-09-14-30-00_my-title.md", "2026-02-09-14-30-00", 0, true},
+		{"2026-02-09-14-30-00-01_my-title.md", "2026-02-09-14-30-00", 1, true},
+		{"notes.md", "", 0, false},
+		{"2026-02-09-14-30-00.txt", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, collision, ok := parseEntryFileName(tt.name)
+			if ts != tt.timestamp || collision != tt.collision || ok != tt.ok {
+				t.Errorf("parseEntryFileName(%q) = (%q, %d, %v), want (%q, %d, %v)",
+					tt.name, ts, collision, ok, tt.timestamp, tt.collision, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Hello World", "hello-world"},
+		{"  #work: Meeting w/ @alice!  ", "work-meeting-w-alice"},
+		{"Café", "caf"},
+		{"---", ""},
+		{"a very long title that keeps going and going and going past the limit", "a-very-long-title-that-keeps-going-and-going-and-g"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Slugify(tt.input); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	for _, layout := range Layouts {
+		if got, err := ParseLayout(string(layout)); err != nil || got != layout {
+			t.Errorf("ParseLayout(%q) = %q, %v", layout, got, err)
+		}
+	}
+	if _, err := ParseLayout("yearly"); err == nil {
+		t.Error("ParseLayout() expected error for unknown layout")
+	}
+}
@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultNotePattern is the file name layout of Obsidian daily notes
+const DefaultNotePattern = "2006-01-02"
+
+// MarkdownImportOptions controls how ReadMarkdownNotes turns notes into entries
+type MarkdownImportOptions struct {
+	Patterns   []string      // Date layouts (Go time layouts) of note file names without .md
+	Time       time.Duration // Time of day of notes and sections that don't give one
+	SplitLevel int           // Heading level that splits notes into entries; 0 keeps notes whole
+}
+
+// clockTimeLayouts are the times of day a section heading can start with
+var clockTimeLayouts = []string{"15:04", "3:04 PM", "3:04PM", "3:04 pm", "3:04pm", "3 PM", "3PM", "3 pm", "3pm"}
+
+// ParseClockTime parses a time of day like "14:30" or "2:30 PM" into the time since
+// midnight
+func ParseClockTime(s string) (time.Duration, error) {
+	for _, layout := range clockTimeLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q (use e.g. 14:30 or 2:30 PM)", s)
+}
+
+// ReadMarkdownNotes reads the notes of dir and its subdirectories whose file name (without
+// .md) is a date in one of the patterns, like Obsidian daily notes, as entries in local
+// time. Other files, and hidden directories like .obsidian, are left out.
+//
+// Tags of the YAML front matter are added to the note's entries. With a split level, each
+// heading of that level starts an entry, at the time the heading starts with ("## 14:30
+// Standup") or a minute after the previous one.
+func ReadMarkdownNotes(dir string, options MarkdownImportOptions) ([]ImportItem, error) {
+	if len(options.Patterns) == 0 {
+		options.Patterns = []string{DefaultNotePattern}
+	}
+
+	var items []ImportItem
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(d.Name()), ".md") {
+			return nil
+		}
+		date, ok := noteDate(strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())), options.Patterns)
+		if !ok {
+			return nil
+		}
+
+		source, _ := filepath.Rel(dir, path)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			items = append(items, failedImport(source, err))
+			return nil
+		}
+		items = append(items, parseMarkdownNote(source, string(content), date, options)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// noteDate returns the date of a note from its name, in local time
+func noteDate(name string, patterns []string) (time.Time, bool) {
+	for _, pattern := range patterns {
+		if date, err := time.ParseInLocation(pattern, name, time.Local); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// markdownSection is a part of a note that becomes an entry
+type markdownSection struct {
+	heading string // Heading text, without the time it starts with
+	clock   *time.Duration
+	body    []string
+}
+
+// parseMarkdownNote returns the entries of a note of the given date. Empty notes and
+// sections, like unused daily note templates, are left out.
+func parseMarkdownNote(source, content string, date time.Time, options MarkdownImportOptions) []ImportItem {
+	tags, content := splitFrontMatter(strings.ReplaceAll(content, "\r\n", "\n"))
+	var noteTags []string
+	for _, tag := range tags {
+		noteTags = append(noteTags, tagFromName(tag))
+	}
+
+	sections := []*markdownSection{{}}
+	var headingPrefix string
+	if options.SplitLevel > 0 {
+		headingPrefix = strings.Repeat("#", options.SplitLevel) + " "
+	}
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if headingPrefix != "" && !inFence && strings.HasPrefix(line, headingPrefix) {
+			section := &markdownSection{heading: strings.TrimSpace(strings.TrimPrefix(line, headingPrefix))}
+			section.clock, section.heading = headingTime(section.heading)
+			sections = append(sections, section)
+			continue
+		}
+		current := sections[len(sections)-1]
+		current.body = append(current.body, line)
+	}
+
+	var items []ImportItem
+	clock := options.Time
+	for i, section := range sections {
+		body := strings.TrimSpace(strings.Join(section.body, "\n"))
+		if section.heading != "" {
+			body = strings.TrimSpace(section.heading + "\n\n" + body)
+		}
+		if body == "" {
+			continue
+		}
+		if section.clock != nil {
+			clock = *section.clock
+		} else if len(items) > 0 {
+			clock += time.Minute
+		}
+
+		itemSource := source
+		if len(sections) > 1 {
+			itemSource = fmt.Sprintf("%s section %d", source, i)
+		}
+		items = append(items, importedEntry(itemSource, date.Add(clock), appendTags(body, noteTags)))
+	}
+	return items
+}
+
+// headingTime splits the time of day a heading starts with off its text
+func headingTime(heading string) (*time.Duration, string) {
+	match := headingTimePattern.FindStringSubmatch(heading)
+	if match == nil {
+		return nil, heading
+	}
+	clock, err := ParseClockTime(match[1])
+	if err != nil {
+		return nil, heading
+	}
+	return &clock, strings.TrimSpace(strings.TrimLeft(heading[len(match[0]):], "-–—: "))
+}
+
+// headingTimePattern matches a time of day at the start of a heading
+var headingTimePattern = regexp.MustCompile(`(?i)^(\d{1,2}(?::\d{2})?\s?(?:am|pm)|\d{1,2}:\d{2})\b`)
+
+// splitFrontMatter returns the tags of the YAML front matter of a note and the note
+// without it. Tags are given as a list ("tags: [a, b]" or one "- a" per line) or as
+// words separated by commas or spaces.
+func splitFrontMatter(content string) ([]string, string) {
+	lines := strings.Split(content, "\n")
+	if lines[0] != "---" {
+		return nil, content
+	}
+
+	var tags []string
+	inTags := false
+	for i, line := range lines[1:] {
+		if line == "---" || line == "..." {
+			return tags, strings.Join(lines[i+2:], "\n")
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if inTags && strings.HasPrefix(trimmed, "- ") {
+			tags = append(tags, splitNames(strings.TrimPrefix(trimmed, "- "))...)
+			continue
+		}
+		inTags = false
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "tags", "tag":
+			value = strings.Trim(strings.TrimSpace(value), "[]")
+			if value == "" {
+				inTags = true
+				continue
+			}
+			tags = append(tags, splitNames(value)...)
+		}
+	}
+	// No closing ---: not front matter
+	return nil, content
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadMarkdownNotes(t *testing.T) {
+	dir := t.TempDir()
+	notes := map[string]string{
+		"Daily/2024-02-09.md": "---\ntags: [daily, Road Trip]\nmood: good\n---\n\nDrove to the coast.\n",
+		"Daily/2024/2024-02-10.md": "---\ntags:\n  - daily\n  - \"#travel\"\n---\n" +
+			"Before the first heading\n\n## 14:30 Lunch\nFish tacos\n\n## Afternoon\nBeach\n\n## 8:15 pm\n```\n## not a heading\n```\n\n## 9:00 PM Empty\n",
+		"Daily/2024-02-11.md":     "---\ntags: daily\n---\n",
+		"Projects/Alpha.md":       "Not a daily note",
+		".obsidian/2024-02-12.md": "Settings",
+		"Feb 13, 2024.md":         "Other naming",
+	}
+	for name, content := range notes {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), DirPermissions); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), FilePermissions); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	items, err := ReadMarkdownNotes(dir, MarkdownImportOptions{Time: 9 * time.Hour, SplitLevel: 2})
+	if err != nil {
+		t.Fatalf("ReadMarkdownNotes() error = %v", err)
+	}
+	day := func(d, hour, minute int) time.Time { return time.Date(2024, 2, d, hour, minute, 0, 0, time.Local) }
+	want := []struct {
+		source    string
+		timestamp time.Time
+		body      string
+	}{
+		{"Daily/2024/2024-02-10.md section 0", day(10, 9, 0), "Before the first heading\n\n#daily #travel"},
+		{"Daily/2024/2024-02-10.md section 1", day(10, 14, 30), "Lunch\n\nFish tacos\n\n#daily #travel"},
+		{"Daily/2024/2024-02-10.md section 2", day(10, 14, 31), "Afternoon\n\nBeach\n\n#daily #travel"},
+		{"Daily/2024/2024-02-10.md section 3", day(10, 20, 15), "```\n## not a heading\n```\n\n#daily #travel"},
+		{"Daily/2024/2024-02-10.md section 4", day(10, 21, 0), "Empty\n\n#daily #travel"},
+		{"Daily/2024-02-09.md", day(9, 9, 0), "Drove to the coast.\n\n#daily #Road-Trip"},
+	}
+	if len(items) != len(want) {
+		for _, item := range items {
+			t.Logf("%s: %+v", item.Source, item.Entry)
+		}
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, w := range want {
+		item := items[i]
+		if item.Action != ImportNew {
+			t.Errorf("%s: %v", item.Source, item.Err)
+			continue
+		}
+		if item.Source != filepath.FromSlash(w.source) || !item.Entry.Timestamp.Equal(w.timestamp) || item.Entry.Body != w.body {
+			t.Errorf("item %d = %s at %v: %q, want %s at %v: %q", i, item.Source, item.Entry.Timestamp,
+				item.Entry.Body, w.source, w.timestamp, w.body)
+		}
+	}
+
+	// Other file name patterns, without splitting
+	items, err = ReadMarkdownNotes(dir, MarkdownImportOptions{Patterns: []string{"Jan 2, 2006"}, Time: 12 * time.Hour})
+	if err != nil {
+		t.Fatalf("ReadMarkdownNotes(pattern) error = %v", err)
+	}
+	if len(items) != 1 || !items[0].Entry.Timestamp.Equal(day(13, 12, 0)) || items[0].Entry.Body != "Other naming" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		content  string
+		wantTags []string
+		wantRest string
+	}{
+		{"---\ntags: a, b c\n---\nText", []string{"a", "b c"}, "Text"},
+		{"---\ntags: a b\n---\nText", []string{"a", "b"}, "Text"},
+		{"---\ntitle: x\n...\nText", nil, "Text"},
+		{"---\ntags: a\nNo closing line", nil, "---\ntags: a\nNo closing line"},
+		{"Text\n---\n", nil, "Text\n---\n"},
+	}
+	for _, tt := range tests {
+		tags, rest := splitFrontMatter(tt.content)
+		if !reflect.DeepEqual(tags, tt.wantTags) || rest != tt.wantRest {
+			t.Errorf("splitFrontMatter(%q) = %v, %q; want %v, %q", tt.content, tags, rest, tt.wantTags, tt.wantRest)
+		}
+	}
+}
+
+func TestParseClockTime(t *testing.T) {
+	tests := map[string]time.Duration{
+		"14:30":   14*time.Hour + 30*time.Minute,
+		"9:05":    9*time.Hour + 5*time.Minute,
+		"2:30 PM": 14*time.Hour + 30*time.Minute,
+		"12am":    0,
+	}
+	for input, want := range tests {
+		if got, err := ParseClockTime(input); err != nil || got != want {
+			t.Errorf("ParseClockTime(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseClockTime("noon"); err == nil {
+		t.Error("ParseClockTime(noon): expected error")
+	}
+}
@@ -0,0 +1,306 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStorage keeps journal entries in memory. Nothing is persisted, which makes it
+// useful for tests and as a reference for new backends. Entries are stored as serialized
+// markdown, so they read back exactly as they would from a file.
+type MemoryStorage struct {
+	entries map[string]*memoryEntry // By path: the entry filename in the flat layout
+	mu      sync.RWMutex
+}
+
+// memoryEntry is an entry held by MemoryStorage
+type memoryEntry struct {
+	text    string
+	modTime time.Time
+}
+
+// NewMemoryStorage creates an empty in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{entries: make(map[string]*memoryEntry)}
+}
+
+// SaveEntry stores a new entry, adding a collision suffix to its path if needed
+func (m *MemoryStorage) SaveEntry(entry *JournalEntry) error {
+	_, err := m.saveEntry(entry)
+	return err
+}
+
+// saveEntry stores a new entry and returns its path
+func (m *MemoryStorage) saveEntry(entry *JournalEntry) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for collision := 0; collision < MaxCollisionAttempts; collision++ {
+		path := LayoutFlat.fileName(entry.Timestamp, collision, "")
+		if _, taken := m.entries[path]; !taken {
+			m.entries[path] = &memoryEntry{text: SerializeEntry(entry), modTime: time.Now()}
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("too many entries with same timestamp")
+}
+
+// put stores serialized entry text at path, replacing any entry there
+func (m *MemoryStorage) put(path, text string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[path] = &memoryEntry{text: text, modTime: time.Now()}
+}
+
+// GetEntry returns the first entry with the given timestamp
+func (m *MemoryStorage) GetEntry(timestamp time.Time) (*JournalEntry, error) {
+	path, err := m.GetEntryPath(timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return m.GetEntryByPath(path)
+}
+
+// GetEntryPath returns the path of the first entry with the given timestamp
+func (m *MemoryStorage) GetEntryPath(timestamp time.Time) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	want := timestamp.UTC().Format(FileNameTimestampLayout)
+	first, firstCollision := "", MaxCollisionAttempts
+	for path := range m.entries {
+		ts, collision, ok := parseEntryFileName(path)
+		if ok && ts == want && collision < firstCollision {
+			first, firstCollision = path, collision
+		}
+	}
+
+	if first == "" {
+		return "", fmt.Errorf("entry not found: %s", timestamp.Format(FileTimestampFormat))
+	}
+	return first, nil
+}
+
+// GetEntryByPath returns the entry stored at path
+func (m *MemoryStorage) GetEntryByPath(path string) (*JournalEntry, error) {
+	m.mu.RLock()
+	stored, ok := m.entries[path]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("entry not found: %s", path)
+	}
+	return ParseEntry(stored.text)
+}
+
+// ListEntries returns the entries matching filter, oldest first
+func (m *MemoryStorage) ListEntries(filter EntryFilter) ([]*JournalEntry, error) {
+	var entries []*JournalEntry
+	for _, path := range m.paths() {
+		entry, err := m.GetEntryByPath(path)
+		if err != nil || !filter.Matches(entry.Timestamp) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return sortAndPage(entries, filter), nil
+}
+
+// paths returns the paths of all entries, sorted
+func (m *MemoryStorage) paths() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paths := make([]string, 0, len(m.entries))
+	for path := range m.entries {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// UpdateEntry replaces the entry stored at path
+func (m *MemoryStorage) UpdateEntry(path string, entry *JournalEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries[path]; !ok {
+		return fmt.Errorf("entry not found: %s", path)
+	}
+	m.entries[path] = &memoryEntry{text: SerializeEntry(entry), modTime: time.Now()}
+	return nil
+}
+
+// GetEntryVersion returns the content hash and modification time of an entry
+func (m *MemoryStorage) GetEntryVersion(path string) (EntryVersion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stored, ok := m.entries[path]
+	if !ok {
+		return EntryVersion{}, fmt.Errorf("entry not found: %s", path)
+	}
+	return EntryVersion{Hash: contentHash([]byte(stored.text)), ModTime: stored.modTime}, nil
+}
+
+// UpdateEntryIfUnchanged replaces an entry only if it still matches the expected version
+// Returns ErrEntryModified if the entry was changed since the version was recorded
+func (m *MemoryStorage) UpdateEntryIfUnchanged(path string, entry *JournalEntry, expected EntryVersion) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.entries[path]
+	if !ok {
+		return fmt.Errorf("entry not found: %s", path)
+	}
+	if contentHash([]byte(stored.text)) != expected.Hash {
+		return ErrEntryModified
+	}
+	m.entries[path] = &memoryEntry{text: SerializeEntry(entry), modTime: time.Now()}
+	return nil
+}
+
+// DeleteEntry removes the entry stored at path
+func (m *MemoryStorage) DeleteEntry(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries[path]; !ok {
+		return fmt.Errorf("entry not found: %s", path)
+	}
+	delete(m.entries, path)
+	return nil
+}
+
+// DeleteEntries removes the entries matching filter (ignoring its limit and offset)
+// Returns the paths of the deleted entries
+func (m *MemoryStorage) DeleteEntries(filter EntryFilter) ([]string, error) {
+	deleted := []string{}
+	for _, path := range m.paths() {
+		entry, err := m.GetEntryByPath(path)
+		if err != nil || !filter.Matches(entry.Timestamp) {
+			continue
+		}
+		if err := m.DeleteEntry(path); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, path)
+	}
+	return deleted, nil
+}
+
+// SearchByTags returns the entries that have ALL the specified tags (AND logic)
+func (m *MemoryStorage) SearchByTags(tags []string, filter EntryFilter) ([]*JournalEntry, error) {
+	index, err := m.GetIndex(filter)
+	if err != nil {
+		return nil, err
+	}
+	return m.indexedEntriesToFull(index.SearchByTags(tags), filter), nil
+}
+
+// SearchByMentions returns the entries that have ALL the specified mentions (AND logic)
+func (m *MemoryStorage) SearchByMentions(mentions []string, filter EntryFilter) ([]*JournalEntry, error) {
+	index, err := m.GetIndex(filter)
+	if err != nil {
+		return nil, err
+	}
+	return m.indexedEntriesToFull(index.SearchByMentions(mentions), filter), nil
+}
+
+// SearchByKeyword returns the entries whose body contains the keyword (case-insensitive)
+func (m *MemoryStorage) SearchByKeyword(keyword string, filter EntryFilter) ([]*JournalEntry, error) {
+	index, err := m.GetIndex(filter)
+	if err != nil {
+		return nil, err
+	}
+	return m.indexedEntriesToFull(index.SearchByKeyword(keyword), filter), nil
+}
+
+// indexedEntriesToFull converts index results to entries, applying the filter
+func (m *MemoryStorage) indexedEntriesToFull(indexed []*IndexedEntry, filter EntryFilter) []*JournalEntry {
+	var entries []*JournalEntry
+	for _, ie := range indexed {
+		if !filter.Matches(ie.Timestamp) {
+			continue
+		}
+		if entry, err := m.GetEntryByPath(ie.FilePath); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return sortAndPage(entries, filter)
+}
+
+// GetIndex builds a search index of all entries
+// Entries change in place, so the index is built on every call
+func (m *MemoryStorage) GetIndex(filter EntryFilter) (*Index, error) {
+	index := NewIndex()
+	if err := index.Build(m.paths(), 1, m.GetEntryByPath); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// GetTagStatistics returns tag usage counts across all entries
+func (m *MemoryStorage) GetTagStatistics() (map[string]int, error) {
+	index, err := m.GetIndex(EntryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return index.TagStatistics(), nil
+}
+
+// GetMentionStatistics returns mention usage counts across all entries
+func (m *MemoryStorage) GetMentionStatistics() (map[string]int, error) {
+	index, err := m.GetIndex(EntryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return index.MentionStatistics(), nil
+}
+
+// GetEntriesWithTag returns the paths of all entries with the specified tag
+func (m *MemoryStorage) GetEntriesWithTag(tag string) ([]string, error) {
+	index, err := m.GetIndex(EntryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return indexedPaths(index.GetEntriesForTag(tag)), nil
+}
+
+// GetEntriesWithMention returns the paths of all entries with the specified mention
+func (m *MemoryStorage) GetEntriesWithMention(mention string) ([]string, error) {
+	index, err := m.GetIndex(EntryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return indexedPaths(index.GetEntriesForMention(mention)), nil
+}
+
+// indexedPaths returns the paths of indexed entries
+func indexedPaths(entries []*IndexedEntry) []string {
+	paths := make([]string, len(entries))
+	for i, entry := range entries {
+		paths[i] = entry.FilePath
+	}
+	return paths
+}
+
+// ReplaceTagInEntries replaces oldTag with newTag in all entries
+func (m *MemoryStorage) ReplaceTagInEntries(oldTag, newTag string, dryRun bool) ([]string, error) {
+	paths, err := m.GetEntriesWithTag(oldTag)
+	if err != nil {
+		return nil, err
+	}
+	return replaceMetadataInEntries(m, oldTag, newTag, "#", paths, dryRun)
+}
+
+// ReplaceMentionInEntries replaces oldMention with newMention in all entries
+func (m *MemoryStorage) ReplaceMentionInEntries(oldMention, newMention string, dryRun bool) ([]string, error) {
+	paths, err := m.GetEntriesWithMention(oldMention)
+	if err != nil {
+		return nil, err
+	}
+	return replaceMetadataInEntries(m, oldMention, newMention, "@", paths, dryRun)
+}
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStorage_SaveAndGet(t *testing.T) {
+	storage := NewMemoryStorage()
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+
+	for _, body := range []string{"First #work entry.", "Second entry, same minute."} {
+		if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: body}); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+
+	entry, err := storage.GetEntry(ts)
+	if err != nil {
+		t.Fatalf("GetEntry() error = %v", err)
+	}
+	if entry.Body != "First #work entry." || len(entry.Tags) != 1 || entry.Tags[0] != "work" {
+		t.Errorf("GetEntry() = %+v", entry)
+	}
+
+	path, err := storage.GetEntryPath(ts)
+	if err != nil || path != "2026-02-09-14-30-00.md" {
+		t.Errorf("GetEntryPath() = %q, %v", path, err)
+	}
+	if _, err := storage.GetEntryByPath("2026-02-09-14-30-00-01.md"); err != nil {
+		t.Errorf("GetEntryByPath(collision) error = %v", err)
+	}
+	if _, err := storage.GetEntry(ts.Add(time.Hour)); err == nil {
+		t.Error("GetEntry() of a missing entry: expected error")
+	}
+}
+
+func TestMemoryStorage_ListAndDelete(t *testing.T) {
+	storage := NewMemoryStorage()
+	for day := 1; day <= 5; day++ {
+		if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, day, 9, 0, 0, 0, time.UTC), Body: "Entry."}); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+
+	start := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 4, 23, 59, 0, 0, time.UTC)
+	entries, err := storage.ListEntries(EntryFilter{StartDate: &start, EndDate: &end, Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Timestamp.Day() != 3 {
+		t.Errorf("ListEntries() = %v, want the entry of Feb 3", entries)
+	}
+
+	deleted, err := storage.DeleteEntries(EntryFilter{StartDate: &start, EndDate: &end})
+	if err != nil || len(deleted) != 3 {
+		t.Fatalf("DeleteEntries() = %v, %v; want 3 paths", deleted, err)
+	}
+	if entries, _ := storage.ListEntries(EntryFilter{}); len(entries) != 2 {
+		t.Errorf("ListEntries() after delete = %d entries, want 2", len(entries))
+	}
+}
+
+func TestMemoryStorage_UpdateEntryIfUnchanged(t *testing.T) {
+	storage := NewMemoryStorage()
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Original."}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	path, _ := storage.GetEntryPath(ts)
+
+	version, err := storage.GetEntryVersion(path)
+	if err != nil {
+		t.Fatalf("GetEntryVersion() error = %v", err)
+	}
+	if err := storage.UpdateEntry(path, &JournalEntry{Timestamp: ts, Body: "Changed elsewhere."}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	if err := storage.UpdateEntryIfUnchanged(path, &JournalEntry{Timestamp: ts, Body: "Mine."}, version); !errors.Is(err, ErrEntryModified) {
+		t.Errorf("UpdateEntryIfUnchanged() error = %v, want ErrEntryModified", err)
+	}
+
+	version, _ = storage.GetEntryVersion(path)
+	if err := storage.UpdateEntryIfUnchanged(path, &JournalEntry{Timestamp: ts, Body: "Mine."}, version); err != nil {
+		t.Errorf("UpdateEntryIfUnchanged() error = %v", err)
+	}
+	if entry, _ := storage.GetEntryByPath(path); entry.Body != "Mine." {
+		t.Errorf("entry body = %q, want %q", entry.Body, "Mine.")
+	}
+}
+
+func TestMemoryStorage_SearchAndRename(t *testing.T) {
+	storage := NewMemoryStorage()
+	for i, body := range []string{"Planning #work with @alice.", "More #work.", "Weekend #hiking with @Alice."} {
+		if err := storage.SaveEntry(&JournalEntry{Timestamp: time.Date(2026, 2, 9, 9, i, 0, 0, time.UTC), Body: body}); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+
+	if found, err := storage.SearchByTags([]string{"work"}, EntryFilter{}); err != nil || len(found) != 2 {
+		t.Errorf("SearchByTags() = %d entries, %v; want 2", len(found), err)
+	}
+	if found, err := storage.SearchByMentions([]string{"alice"}, EntryFilter{}); err != nil || len(found) != 2 {
+		t.Errorf("SearchByMentions() = %d entries, %v; want 2", len(found), err)
+	}
+	if found, err := storage.SearchByKeyword("weekend", EntryFilter{}); err != nil || len(found) != 1 {
+		t.Errorf("SearchByKeyword() = %d entries, %v; want 1", len(found), err)
+	}
+
+	updated, err := storage.ReplaceTagInEntries("work", "job", false)
+	if err != nil || len(updated) != 2 {
+		t.Fatalf("ReplaceTagInEntries() = %v, %v", updated, err)
+	}
+	stats, err := storage.GetTagStatistics()
+	if err != nil {
+		t.Fatalf("GetTagStatistics() error = %v", err)
+	}
+	if stats["job"] != 2 || stats["work"] != 0 || stats["hiking"] != 1 {
+		t.Errorf("GetTagStatistics() = %v", stats)
+	}
+}
@@ -0,0 +1,220 @@
+package internal
+
+import (
+	"strings"
+)
+
+// Conflict markers written into merged text when both sides changed the same lines
+const (
+	ConflictMarkerMine   = "<<<<<<< mine"
+	ConflictMarkerBase   = "======="
+	ConflictMarkerTheirs = ">>>>>>> theirs"
+)
+
+// MergeText performs a line-based three-way merge of two edited versions of base.
+// Changes made on only one side are applied automatically. When both sides changed
+// the same region differently, the region is emitted between conflict markers.
+// Returns the merged text and whether any conflicts were found.
+func MergeText(base, mine, theirs string) (string, bool) {
+	baseLines := splitLines(base)
+	mineLines := splitLines(mine)
+	theirsLines := splitLines(theirs)
+
+	// Map each base line to its matching line in each edited version (-1 = no match)
+	toMine := matchLines(baseLines, mineLines)
+	toTheirs := matchLines(baseLines, theirsLines)
+
+	var merged []string
+	conflicts := false
+
+	i, j, k := 0, 0, 0 // base, mine, theirs positions
+	for i < len(baseLines) || j < len(mineLines) || k < len(theirsLines) {
+		// Stable line: unchanged on both sides
+		if i < len(baseLines) && toMine[i] == j && toTheirs[i] == k {
+			merged = append(merged, baseLines[i])
+			i++
+			j++
+			k++
+			continue
+		}
+
+		// Find the next stable line to bound the changed region
+		nextI, nextJ, nextK := len(baseLines), len(mineLines), len(theirsLines)
+		for n := i; n < len(baseLines); n++ {
+			if toMine[n] >= j && toTheirs[n] >= k {
+				nextI, nextJ, nextK = n, toMine[n], toTheirs[n]
+				break
+			}
+		}
+
+		baseChunk := baseLines[i:nextI]
+		mineChunk := mineLines[j:nextJ]
+		theirsChunk := theirsLines[k:nextK]
+
+		switch {
+		case equalLines(mineChunk, baseChunk):
+			// Only theirs changed
+			merged = append(merged, theirsChunk...)
+		case equalLines(theirsChunk, baseChunk), equalLines(mineChunk, theirsChunk):
+			// Only mine changed, or both made the same change
+			merged = append(merged, mineChunk...)
+		default:
+			conflicts = true
+			merged = append(merged, ConflictMarkerMine)
+			merged = append(merged, mineChunk...)
+			merged = append(merged, ConflictMarkerBase)
+			merged = append(merged, theirsChunk...)
+			merged = append(merged, ConflictMarkerTheirs)
+		}
+
+		i, j, k = nextI, nextJ, nextK
+	}
+
+	return strings.Join(merged, "\n"), conflicts
+}
+
+// HasConflictMarkers reports whether text still contains unresolved merge conflict markers
+func HasConflictMarkers(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if line == ConflictMarkerMine || line == ConflictMarkerTheirs {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffOp identifies how a line differs between two texts
+type DiffOp byte
+
+const (
+	DiffEqual  DiffOp = ' ' // Line is in both texts
+	DiffDelete DiffOp = '-' // Line is only in the first text
+	DiffInsert DiffOp = '+' // Line is only in the second text
+)
+
+// DiffLine is a line of a line-based diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// DiffLines computes a line-based diff that turns a into b
+func DiffLines(a, b string) []DiffLine {
+	aLines := splitLines(a)
+	bLines := splitLines(b)
+	matches := matchLines(aLines, bLines)
+
+	var diff []DiffLine
+	y := 0
+	for x, line := range aLines {
+		if matches[x] == -1 {
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: line})
+			continue
+		}
+		for ; y < matches[x]; y++ {
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: bLines[y]})
+		}
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+		y++
+	}
+	for ; y < len(bLines); y++ {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: bLines[y]})
+	}
+
+	return diff
+}
+
+// MarkConflicts combines two versions of a text without a common base: lines in both are
+// kept, and each region where they differ is emitted between conflict markers
+func MarkConflicts(mine, theirs string) string {
+	var out, mineHunk, theirsHunk []string
+
+	flush := func() {
+		if len(mineHunk) == 0 && len(theirsHunk) == 0 {
+			return
+		}
+		out = append(out, ConflictMarkerMine)
+		out = append(out, mineHunk...)
+		out = append(out, ConflictMarkerBase)
+		out = append(out, theirsHunk...)
+		out = append(out, ConflictMarkerTheirs)
+		mineHunk, theirsHunk = nil, nil
+	}
+
+	for _, line := range DiffLines(mine, theirs) {
+		switch line.Op {
+		case DiffDelete:
+			mineHunk = append(mineHunk, line.Text)
+		case DiffInsert:
+			theirsHunk = append(theirsHunk, line.Text)
+		default:
+			flush()
+			out = append(out, line.Text)
+		}
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// splitLines splits text into lines, ignoring a single trailing newline
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// matchLines computes the longest common subsequence of a and b
+// Returns, for each line in a, the index of its matching line in b (or -1)
+func matchLines(a, b []string) []int {
+	// lengths[x][y] = LCS length of a[x:] and b[y:]
+	lengths := make([][]int, len(a)+1)
+	for x := range lengths {
+		lengths[x] = make([]int, len(b)+1)
+	}
+	for x := len(a) - 1; x >= 0; x-- {
+		for y := len(b) - 1; y >= 0; y-- {
+			if a[x] == b[y] {
+				lengths[x][y] = lengths[x+1][y+1] + 1
+			} else {
+				lengths[x][y] = max(lengths[x+1][y], lengths[x][y+1])
+			}
+		}
+	}
+
+	matches := make([]int, len(a))
+	for x := range matches {
+		matches[x] = -1
+	}
+
+	x, y := 0, 0
+	for x < len(a) && y < len(b) {
+		switch {
+		case a[x] == b[y]:
+			matches[x] = y
+			x++
+			y++
+		case lengths[x+1][y] >= lengths[x][y+1]:
+			x++
+		default:
+			y++
+		}
+	}
+
+	return matches
+}
+
+// equalLines reports whether two line slices are identical
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeText(t *testing.T) {
+	base := "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline two\nline three\n"
+
+	tests := []struct {
+		name          string
+		mine          string
+		theirs        string
+		expected      string
+		wantConflicts bool
+	}{
+		{
+			name:     "no changes",
+			mine:     base,
+			theirs:   base,
+			expected: strings.TrimSuffix(base, "\n"),
+		},
+		{
+			name:     "only mine changed",
+			mine:     "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline TWO\nline three\n",
+			theirs:   base,
+			expected: "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline TWO\nline three",
+		},
+		{
+			name:     "only theirs changed",
+			mine:     base,
+			theirs:   "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline two\nline three\nline four\n",
+			expected: "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline two\nline three\nline four",
+		},
+		{
+			name:     "non-overlapping changes",
+			mine:     "## Monday 2026-02-09 2:30 PM UTC\n\nline ONE\nline two\nline three\n",
+			theirs:   "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline two\nline THREE\n",
+			expected: "## Monday 2026-02-09 2:30 PM UTC\n\nline ONE\nline two\nline THREE",
+		},
+		{
+			name:     "identical changes on both sides",
+			mine:     "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline 2\nline three\n",
+			theirs:   "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline 2\nline three\n",
+			expected: "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nline 2\nline three",
+		},
+		{
+			name:   "overlapping changes conflict",
+			mine:   "## Monday 2026-02-09 2:30 PM UTC\n\nline one\nmine\nline three\n",
+			theirs: "## Monday 2026-02-09 2:30 PM UTC\n\nline one\ntheirs\nline three\n",
+			expected: "## Monday 2026-02-09 2:30 PM UTC\n\nline one\n" +
+				ConflictMarkerMine + "\nmine\n" + ConflictMarkerBase + "\ntheirs\n" + ConflictMarkerTheirs +
+				"\nline three",
+			wantConflicts: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := MergeText(base, tt.mine, tt.theirs)
+			if conflicts != tt.wantConflicts {
+				t.Errorf("conflicts = %v, want %v", conflicts, tt.wantConflicts)
+			}
+			if merged != tt.expected {
+				t.Errorf("MergeText() =\n%s\nwant:\n%s", merged, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasConflictMarkers(t *testing.T) {
+	if HasConflictMarkers("plain text\nno markers") {
+		t.Error("HasConflictMarkers() = true for text without markers")
+	}
+
+	merged, _ := MergeText("a\n", "b\n", "c\n")
+	if !HasConflictMarkers(merged) {
+		t.Error("HasConflictMarkers() = false for conflicting merge")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	diff := DiffLines("a\nb\nc\n", "a\nB\nc\nd\n")
+
+	var got []string
+	for _, line := range diff {
+		got = append(got, string(line.Op)+line.Text)
+	}
+	want := []string{" a", "-b", "+B", " c", "+d"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("DiffLines() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkConflicts(t *testing.T) {
+	got := MarkConflicts("## Header\n\nsame\nmine only\n", "## Header\n\nsame\ntheirs only\n")
+	want := "## Header\n\nsame\n" + ConflictMarkerMine + "\nmine only\n" + ConflictMarkerBase + "\ntheirs only\n" + ConflictMarkerTheirs
+
+	if got != want {
+		t.Errorf("MarkConflicts() = %q, want %q", got, want)
+	}
+	if !HasConflictMarkers(got) {
+		t.Error("MarkConflicts() output should contain conflict markers")
+	}
+	if same := MarkConflicts("a\nb\n", "a\nb\n"); same != "a\nb" {
+		t.Errorf("MarkConflicts() of identical texts = %q", same)
+	}
+}
@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// layoutState is stored in the storage root to record which layout the journal uses
+type layoutState struct {
+	Layout      Layout `json:"layout"`
+	MigratingTo Layout `json:"migrating_to,omitempty"` // Set while a migration is in progress
+}
+
+// readLayoutState loads the layout state file, returning nil if there is none
+func readLayoutState(basePath string) (*layoutState, error) {
+	data, err := os.ReadFile(filepath.Join(basePath, LayoutStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var state layoutState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", LayoutStateFile, err)
+	}
+	return &state, nil
+}
+
+// writeLayoutState records the layout state in the storage root
+func (fs *FileSystemStorage) writeLayoutState(state *layoutState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(fs.basePath, DirPermissions); err != nil {
+		return err
+	}
+	if err := fs.writeAtomic(filepath.Join(fs.basePath, LayoutStateFile), data); err != nil {
+		return fmt.Errorf("failed to record layout: %w", err)
+	}
+
+	fs.layout = state.Layout
+	fs.migratingTo = state.MigratingTo
+	return nil
+}
+
+// ensureLayoutState records the current layout if the journal doesn't have a state file yet,
+// so a later change of the configured default doesn't hide existing entries
+func (fs *FileSystemStorage) ensureLayoutState() error {
+	if _, err := os.Stat(filepath.Join(fs.basePath, LayoutStateFile)); !os.IsNotExist(err) {
+		return err
+	}
+	return fs.writeLayoutState(&layoutState{Layout: fs.layout})
+}
+
+// LayoutMove describes an entry file moved (or to be moved) by a layout migration
+type LayoutMove struct {
+	From string
+	To   string
+}
+
+// MigrationResult summarizes a layout migration
+type MigrationResult struct {
+	From       Layout
+	To         Layout
+	Moves      []LayoutMove // Files moved (or planned, for a dry run)
+	InPlace    int          // Files already in the target layout
+	Unparsable []string     // Files left alone because they can't be parsed
+}
+
+// MigrateLayout moves every entry file to the target layout.
+// The migration is recorded in the layout state file before any file is moved, so an
+// interrupted migration can be resumed by running it again; until it completes, entries
+// are found in either layout. Moves never overwrite existing files.
+// With dryRun, nothing is changed and the planned moves are returned.
+func (fs *FileSystemStorage) MigrateLayout(target Layout, dryRun bool) (*MigrationResult, error) {
+	if fs.migratingTo != "" && fs.migratingTo != target {
+		return nil, fmt.Errorf("a migration to %q is in progress; run it again to finish before migrating to %q", fs.migratingTo, target)
+	}
+
+	result := &MigrationResult{From: fs.layout, To: target}
+	if fs.layout == target && fs.migratingTo == "" {
+		return result, nil
+	}
+	if err := fs.ensureUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := fs.checkNoArchives("changing the layout"); err != nil {
+		return nil, err
+	}
+
+	files, err := fs.findAllFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find files: %w", err)
+	}
+	sort.Strings(files)
+
+	// Record the migration before touching any file
+	if !dryRun {
+		if err := fs.writeLayoutState(&layoutState{Layout: result.From, MigratingTo: target}); err != nil {
+			return nil, err
+		}
+	}
+
+	reserved := make(map[string]bool) // Targets claimed during a dry run
+	for _, path := range files {
+		entry, err := fs.parseFile(path)
+		if err != nil {
+			result.Unparsable = append(result.Unparsable, path)
+			continue
+		}
+
+		if fs.inLayout(path, entry, target) {
+			result.InPlace++
+			continue
+		}
+
+		destination := fs.freeEntryPath(target, entry, path, reserved)
+		if destination == "" {
+			return result, fmt.Errorf("too many entries with the same timestamp as %s", path)
+		}
+
+		if dryRun {
+			reserved[destination] = true
+		} else {
+			if err := fs.ensureDirectories(destination); err != nil {
+				return result, fmt.Errorf("failed to create directories: %w", err)
+			}
+			if err := fs.renameFile(path, destination); err != nil {
+				return result, fmt.Errorf("failed to move %s: %w", path, err)
+			}
+		}
+
+		result.Moves = append(result.Moves, LayoutMove{From: path, To: destination})
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	fs.removeEmptyDirs()
+	fs.InvalidateIndex()
+
+	if err := fs.writeLayoutState(&layoutState{Layout: target}); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// inLayout reports whether path is where the layout would put the entry
+// The existing collision suffix is kept, so entries sharing a timestamp stay distinct
+func (fs *FileSystemStorage) inLayout(path string, entry *JournalEntry, layout Layout) bool {
+	ts, collision, ok := parseEntryFileName(filepath.Base(path))
+	if !ok || ts != entry.Timestamp.UTC().Format(FileNameTimestampLayout) {
+		return false
+	}
+
+	expected := filepath.Join(layout.dir(fs.basePath, entry.Timestamp), layout.fileName(entry.Timestamp, collision, entry.Body))
+	return path == expected
+}
+
+// removeEmptyDirs deletes empty directories left behind in the storage tree
+// Hidden directories and the storage root are kept
+func (fs *FileSystemStorage) removeEmptyDirs() {
+	var dirs []string
+	_ = filepath.WalkDir(fs.basePath, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == fs.basePath {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+
+	// Deepest first, so parents become empty before they are checked
+	sort.Slice(dirs, func(i, j int) bool {
+		return len(dirs[i]) > len(dirs[j])
+	})
+	for _, dir := range dirs {
+		_ = os.Remove(dir) // Fails (and is ignored) for non-empty directories
+	}
+}
@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// saveTestEntries saves entries for migration tests, including two with the same timestamp
+func saveTestEntries(t *testing.T, storage *FileSystemStorage) []*JournalEntry {
+	t.Helper()
+	entries := []*JournalEntry{
+		{Timestamp: time.Date(2025, 12, 29, 8, 0, 0, 0, time.UTC), Body: "Year end review"},
+		{Timestamp: time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC), Body: "First"},
+		{Timestamp: time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC), Body: "Second"},
+	}
+	for _, entry := range entries {
+		if err := storage.SaveEntry(entry); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+	return entries
+}
+
+func assertEntryBodies(t *testing.T, storage *FileSystemStorage, want ...string) {
+	t.Helper()
+	entries, err := storage.ListEntries(EntryFilter{})
+	if err != nil {
+		t.Fatalf("ListEntries() error = %v", err)
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("ListEntries() returned %d entries, want %d", len(entries), len(want))
+	}
+	// Entries sharing a timestamp have no defined order
+	got := make([]string, len(entries))
+	for i, entry := range entries {
+		got[i] = entry.Body
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entry bodies = %q, want %q", got, want)
+	}
+}
+
+func TestMigrateLayout_RoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+	saveTestEntries(t, storage)
+
+	for _, layout := range []Layout{LayoutSlug, LayoutYearWeek, LayoutYearMonthDay, LayoutFlat, LayoutYearMonth} {
+		result, err := storage.MigrateLayout(layout, false)
+		if err != nil {
+			t.Fatalf("MigrateLayout(%s) error = %v", layout, err)
+		}
+		if len(result.Moves)+result.InPlace != 3 {
+			t.Errorf("MigrateLayout(%s) moved %d, in place %d; want 3 total", layout, len(result.Moves), result.InPlace)
+		}
+		if storage.Layout() != layout {
+			t.Errorf("Layout() = %s, want %s", storage.Layout(), layout)
+		}
+
+		// A fresh storage picks the layout up from the state file
+		reopened := NewFileSystemStorage(tmpDir, nil)
+		if reopened.Layout() != layout {
+			t.Errorf("reopened Layout() = %s, want %s", reopened.Layout(), layout)
+		}
+		assertEntryBodies(t, reopened, "Year end review", "First", "Second")
+
+		report, err := reopened.CheckIntegrity()
+		if err != nil {
+			t.Fatalf("CheckIntegrity() error = %v", err)
+		}
+		if len(report.Issues) != 0 {
+			t.Errorf("CheckIntegrity() after migrating to %s: %+v", layout, report.Issues)
+		}
+	}
+
+	// Back to the default layout: the original paths, and no empty directories left behind
+	for _, path := range []string{
+		filepath.Join(tmpDir, "2025", "12", "2025-12-29-08-00-00.md"),
+		filepath.Join(tmpDir, "2026", "02", "2026-02-09-14-30-00.md"),
+		filepath.Join(tmpDir, "2026", "02", "2026-02-09-14-30-00-01.md"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "2026", "W01")); !os.IsNotExist(err) {
+		t.Errorf("expected empty week directory to be removed")
+	}
+}
+
+func TestMigrateLayout_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+	saveTestEntries(t, storage)
+
+	result, err := storage.MigrateLayout(LayoutFlat, true)
+	if err != nil {
+		t.Fatalf("MigrateLayout() error = %v", err)
+	}
+	if len(result.Moves) != 3 {
+		t.Fatalf("Moves = %d, want 3", len(result.Moves))
+	}
+
+	// Same-timestamp entries are planned to distinct paths
+	if result.Moves[1].To == result.Moves[2].To {
+		t.Errorf("dry run planned two moves to %s", result.Moves[1].To)
+	}
+
+	for _, move := range result.Moves {
+		if _, err := os.Stat(move.From); err != nil {
+			t.Errorf("dry run moved %s", move.From)
+		}
+	}
+	if storage.Layout() != LayoutYearMonth {
+		t.Errorf("dry run changed layout to %s", storage.Layout())
+	}
+}
+
+func TestMigrateLayout_Resume(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+	saveTestEntries(t, storage)
+
+	// Simulate an interrupted migration: state recorded, one file moved
+	if err := storage.writeLayoutState(&layoutState{Layout: LayoutYearMonth, MigratingTo: LayoutFlat}); err != nil {
+		t.Fatalf("writeLayoutState() error = %v", err)
+	}
+	moved := filepath.Join(tmpDir, "2025-12-29-08-00-00.md")
+	if err := os.Rename(filepath.Join(tmpDir, "2025", "12", "2025-12-29-08-00-00.md"), moved); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+
+	// Entries are found in either layout while the migration is unfinished
+	interrupted := NewFileSystemStorage(tmpDir, nil)
+	assertEntryBodies(t, interrupted, "Year end review", "First", "Second")
+	if _, err := interrupted.GetEntry(time.Date(2025, 12, 29, 8, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("GetEntry() error = %v", err)
+	}
+
+	// A different target is refused until the migration is finished
+	if _, err := interrupted.MigrateLayout(LayoutSlug, false); err == nil {
+		t.Error("MigrateLayout() expected error for a different target mid-migration")
+	}
+
+	result, err := interrupted.MigrateLayout(LayoutFlat, false)
+	if err != nil {
+		t.Fatalf("MigrateLayout() error = %v", err)
+	}
+	if len(result.Moves) != 2 || result.InPlace != 1 {
+		t.Errorf("resume moved %d, in place %d; want 2 and 1", len(result.Moves), result.InPlace)
+	}
+	assertEntryBodies(t, NewFileSystemStorage(tmpDir, nil), "Year end review", "First", "Second")
+}
+
+func TestMigrateLayout_KeepsUnparsable(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage := NewFileSystemStorage(tmpDir, nil)
+	saveTestEntries(t, storage)
+	broken := writeTestFile(t, tmpDir, filepath.Join("2026", "02", "notes.md"), "no header")
+
+	result, err := storage.MigrateLayout(LayoutFlat, false)
+	if err != nil {
+		t.Fatalf("MigrateLayout() error = %v", err)
+	}
+	if len(result.Unparsable) != 1 || result.Unparsable[0] != broken {
+		t.Errorf("Unparsable = %v, want [%s]", result.Unparsable, broken)
+	}
+	if _, err := os.Stat(broken); err != nil {
+		t.Errorf("unparsable file was moved: %v", err)
+	}
+}
+
+func TestNewFileSystemStorage_ConfiguredLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := DefaultConfig()
+	config.Layout = LayoutFlat
+
+	storage := NewFileSystemStorage(tmpDir, config)
+	entry := &JournalEntry{Timestamp: time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC), Body: "Flat"}
+	if err := storage.SaveEntry(entry); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "2026-02-09-14-30-00.md")); err != nil {
+		t.Errorf("expected entry in storage root: %v", err)
+	}
+
+	// The journal keeps its layout when the configured default changes
+	reopened := NewFileSystemStorage(tmpDir, DefaultConfig())
+	if reopened.Layout() != LayoutFlat {
+		t.Errorf("Layout() = %s, want %s", reopened.Layout(), LayoutFlat)
+	}
+	assertEntryBodies(t, reopened, "Flat")
+}
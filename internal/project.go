@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// projectConfigTemplate is the config file of a new project journal
+const projectConfigTemplate = `# Settings of this project's journal. They override your own config file
+# (jrnlg config path) and are overridden by environment variables.
+#
+# layout = "flat"
+# git_autocommit = true
+#
+# [templates]
+# default = "Changed:\n\nWhy:\n"
+`
+
+// FindProjectJournal walks up from dir looking for a project journal, like git looks for
+// .git, and returns its directory ("" if there is none). The .jrnlg directory in the home
+// directory holds jrnlg's own data, so it isn't a project journal.
+func FindProjectJournal(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	home, _ := os.UserHomeDir()
+
+	for {
+		path := filepath.Join(dir, ProjectDirName)
+		if info, err := os.Stat(path); err == nil && info.IsDir() && dir != home {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// InitProjectJournal creates a project journal in dir, with a commented config file
+// Returns the directory of the new journal
+func InitProjectJournal(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if home, _ := os.UserHomeDir(); dir == home {
+		return "", fmt.Errorf("%s holds jrnlg's own data and can't be a project journal", filepath.Join(home, ProjectDirName))
+	}
+
+	path := filepath.Join(dir, ProjectDirName)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+	if err := os.Mkdir(path, DirPermissions); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(path, ProjectConfigFile), []byte(projectConfigTemplate), FilePermissions); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// JournalLabel names the journal in use: the named journal, or the project of a project
+// journal. It's "" for the journal of the top-level settings.
+func (c *Config) JournalLabel() string {
+	if c.Project != "" {
+		return "project " + filepath.Dir(c.Project)
+	}
+	return c.Journal
+}
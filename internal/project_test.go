@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindProjectJournal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// jrnlg's own data directory isn't a project journal
+	if err := os.Mkdir(filepath.Join(home, ProjectDirName), DirPermissions); err != nil {
+		t.Fatalf("Mkdir() error = %v", err)
+	}
+	repo := filepath.Join(home, "src", "app")
+	deep := filepath.Join(repo, "cmd", "server")
+	if err := os.MkdirAll(deep, DirPermissions); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if got := FindProjectJournal(deep); got != "" {
+		t.Errorf("FindProjectJournal() without a project = %q, want none", got)
+	}
+
+	path, err := InitProjectJournal(repo)
+	if err != nil {
+		t.Fatalf("InitProjectJournal() error = %v", err)
+	}
+	if want := filepath.Join(repo, ProjectDirName); path != want {
+		t.Errorf("InitProjectJournal() = %q, want %q", path, want)
+	}
+	for _, dir := range []string{repo, deep} {
+		if got := FindProjectJournal(dir); got != path {
+			t.Errorf("FindProjectJournal(%s) = %q, want %q", dir, got, path)
+		}
+	}
+
+	if _, err := InitProjectJournal(repo); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("InitProjectJournal() again error = %v, want already exists", err)
+	}
+	if _, err := InitProjectJournal(home); err == nil {
+		t.Error("InitProjectJournal() in the home directory: expected error")
+	}
+}
+
+func TestLoadConfig_Project(t *testing.T) {
+	writeConfigFile(t, `
+format = "summary"
+layout = "slug"
+default_journal = "work"
+
+[journals.work]
+storage_path = "/srv/work"
+`)
+	t.Setenv("JRNLG_JOURNAL", "")
+	t.Setenv("JRNLG_STORAGE_PATH", "")
+	t.Setenv("JRNLG_LAYOUT", "")
+
+	repo := t.TempDir()
+	project, err := InitProjectJournal(repo)
+	if err != nil {
+		t.Fatalf("InitProjectJournal() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(project, ProjectConfigFile), []byte("layout = \"flat\"\n"), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	subdir := filepath.Join(repo, "docs")
+	if err := os.Mkdir(subdir, DirPermissions); err != nil {
+		t.Fatalf("Mkdir() error = %v", err)
+	}
+	t.Chdir(subdir)
+
+	// The project journal takes over from the default journal, with its own settings
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Project != project || config.StoragePath != project || config.Journal != "" {
+		t.Errorf("Project = %q, StoragePath = %q, Journal = %q", config.Project, config.StoragePath, config.Journal)
+	}
+	if config.Layout != LayoutFlat || config.Source("layout") != "project config" || config.Format != "summary" {
+		t.Errorf("Layout = %q from %s, Format = %q", config.Layout, config.Source("layout"), config.Format)
+	}
+	if label := config.JournalLabel(); label != "project "+repo {
+		t.Errorf("JournalLabel() = %q", label)
+	}
+
+	// A named journal is still used when asked for
+	config, err = LoadJournalConfig("work")
+	if err != nil {
+		t.Fatalf("LoadJournalConfig(work) error = %v", err)
+	}
+	if config.Project != "" || config.StoragePath != "/srv/work" {
+		t.Errorf("Project = %q, StoragePath = %q; want the work journal", config.Project, config.StoragePath)
+	}
+
+	// Journals are only defined in the user's config file
+	if err := os.WriteFile(filepath.Join(project, ProjectConfigFile), []byte("default_journal = \"work\"\n"), FilePermissions); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "can only be set in") {
+		t.Errorf("LoadConfig() error = %v, want default_journal refused", err)
+	}
+}
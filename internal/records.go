@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordImportOptions maps the fields of CSV rows or JSON Lines records to entries
+type RecordImportOptions struct {
+	TimestampField string   // Field of the entry time
+	TimezoneField  string   // Field of the time zone name (e.g. Europe/Paris); optional
+	BodyField      string   // Field of the entry text
+	TagsField      string   // Field of tags, separated by commas or else spaces; optional
+	TimeFormat     string   // Go time layout of the timestamps; "" accepts common formats
+	Tags           []string // Tags added to every entry
+}
+
+// recordTimeFormats are the timestamps accepted without a time format, besides Unix times
+// in seconds
+var recordTimeFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseCSVRecords reads entries from CSV rows. The first row names the columns.
+func ParseCSVRecords(content []byte, options RecordImportOptions) ([]ImportItem, error) {
+	// Spreadsheets often start the file with a byte order mark
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty CSV file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, field := range []string{options.TimestampField, options.TimezoneField, options.BodyField, options.TagsField} {
+		if _, ok := columns[field]; field != "" && !ok {
+			return nil, fmt.Errorf("no %q column (columns: %s)", field, strings.Join(header, ", "))
+		}
+	}
+
+	var items []ImportItem
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		source := fmt.Sprintf("line %d", line)
+		items = append(items, importRecord(source, options, func(field string) (any, bool) {
+			i, ok := columns[field]
+			if !ok || i >= len(row) {
+				return nil, false
+			}
+			return row[i], true
+		}))
+	}
+	return items, nil
+}
+
+// ParseJSONLRecords reads entries from JSON Lines: a JSON object per line. Tags can be
+// a string or an array of strings.
+func ParseJSONLRecords(content []byte, options RecordImportOptions) ([]ImportItem, error) {
+	var items []ImportItem
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		source := fmt.Sprintf("line %d", line)
+		var record map[string]any
+		if err := json.Unmarshal(text, &record); err != nil {
+			items = append(items, failedImport(source, fmt.Errorf("invalid JSON: %w", err)))
+			continue
+		}
+		items = append(items, importRecord(source, options, func(field string) (any, bool) {
+			value, ok := record[field]
+			return value, ok && value != nil
+		}))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// importRecord returns the item of a record whose fields are looked up with field
+func importRecord(source string, options RecordImportOptions, field func(name string) (any, bool)) ImportItem {
+	text := func(name string) (string, error) {
+		value, ok := field(name)
+		if !ok {
+			return "", fmt.Errorf("missing %s", name)
+		}
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		default:
+			return "", fmt.Errorf("%s is not text", name)
+		}
+	}
+
+	location := time.Local
+	if options.TimezoneField != "" {
+		if name, err := text(options.TimezoneField); err == nil && strings.TrimSpace(name) != "" {
+			if location, err = time.LoadLocation(strings.TrimSpace(name)); err != nil {
+				return failedImport(source, fmt.Errorf("unknown time zone %q", name))
+			}
+		}
+	}
+	value, err := text(options.TimestampField)
+	if err != nil {
+		return failedImport(source, err)
+	}
+	timestamp, err := parseRecordTime(strings.TrimSpace(value), options.TimeFormat, location)
+	if err != nil {
+		return failedImport(source, err)
+	}
+	body, err := text(options.BodyField)
+	if err != nil {
+		return failedImport(source, err)
+	}
+	if strings.TrimSpace(body) == "" {
+		return failedImport(source, fmt.Errorf("empty %s", options.BodyField))
+	}
+
+	var names []string
+	if options.TagsField != "" {
+		if value, ok := field(options.TagsField); ok {
+			switch v := value.(type) {
+			case string:
+				names = splitNames(v)
+			case []any:
+				for _, tag := range v {
+					if tag, ok := tag.(string); ok {
+						names = append(names, tag)
+					}
+				}
+			default:
+				return failedImport(source, fmt.Errorf("%s is not text or a list", options.TagsField))
+			}
+		}
+	}
+	names = append(names, options.Tags...)
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, "@") {
+			tags = append(tags, name)
+		} else {
+			tags = append(tags, tagFromName(name))
+		}
+	}
+
+	return importedEntry(source, timestamp, appendTags(body, tags))
+}
+
+// parseRecordTime parses a timestamp in the given layout, or in one of the common
+// formats. Times without a zone are in location, and the others are moved into it.
+func parseRecordTime(value, layout string, location *time.Location) (time.Time, error) {
+	layouts := recordTimeFormats
+	if layout != "" {
+		layouts = []string{layout}
+	} else if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).In(location), nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t.In(location), nil
+		}
+	}
+	if value == "" {
+		return time.Time{}, errors.New("empty timestamp")
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSVRecords(t *testing.T) {
+	content := "\xef\xbb\xbfwhen,zone,text,labels\n" +
+		"2024-02-09 14:30,Europe/Paris,\"Lunch with @bob\nat the market\",\"food, Road Trip\"\n" +
+		"2024-02-10T08:00:00Z,America/New_York,Early flight,\n" +
+		"yesterday,,Bad date,\n" +
+		"2024-02-11 09:00,Mars/Olympus,Bad zone,\n" +
+		"2024-02-12 09:00,,,\n" +
+		"2024-02-13 09:00\n"
+	options := RecordImportOptions{
+		TimestampField: "when", TimezoneField: "zone", BodyField: "text", TagsField: "labels",
+		Tags: []string{"imported"},
+	}
+	items, err := ParseCSVRecords([]byte(content), options)
+	if err != nil {
+		t.Fatalf("ParseCSVRecords() error = %v", err)
+	}
+	if len(items) != 6 {
+		t.Fatalf("got %d items, want 6", len(items))
+	}
+
+	first := items[0]
+	if first.Action != ImportNew || first.Source != "line 2" {
+		t.Fatalf("line 2 = %+v", first)
+	}
+	if got := FormatTimestamp(first.Entry.Timestamp); got != "Friday 2024-02-09 2:30 PM CET" {
+		t.Errorf("line 2 header = %q", got)
+	}
+	if want := "Lunch with @bob\nat the market\n\n#food #Road-Trip #imported"; first.Entry.Body != want {
+		t.Errorf("line 2 body = %q, want %q", first.Entry.Body, want)
+	}
+	if got := FormatTimestamp(items[1].Entry.Timestamp); got != "Saturday 2024-02-10 3:00 AM EST" || items[1].Source != "line 4" {
+		t.Errorf("%s header = %q", items[1].Source, got)
+	}
+
+	// Rows that can't be read are reported, without stopping the import
+	wantErrors := []string{"invalid timestamp", "unknown time zone", "empty text", "missing text"}
+	for i, want := range wantErrors {
+		item := items[i+2]
+		if item.Action != ImportFailed || item.Err == nil || !strings.Contains(item.Err.Error(), want) {
+			t.Errorf("%s = %v, want %q error", item.Source, item.Err, want)
+		}
+	}
+
+	if _, err := ParseCSVRecords([]byte("date,text\n"), options); err == nil || !strings.Contains(err.Error(), `no "when" column`) {
+		t.Errorf("ParseCSVRecords() without column error = %v", err)
+	}
+}
+
+func TestParseJSONLRecords(t *testing.T) {
+	content := `{"ts": 1707489000, "message": "From a script", "tags": ["build", "@ci-bot"]}
+
+{"ts": "09/02/2024 10:15", "message": "Custom format", "tags": "ops"}
+not json
+{"ts": "2024-02-09", "message": 42}
+`
+	options := RecordImportOptions{TimestampField: "ts", BodyField: "message", TagsField: "tags"}
+	items, err := ParseJSONLRecords([]byte(content), options)
+	if err != nil {
+		t.Fatalf("ParseJSONLRecords() error = %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("got %d items, want 4", len(items))
+	}
+	if items[0].Action != ImportNew || !items[0].Entry.Timestamp.Equal(time.Unix(1707489000, 0)) ||
+		items[0].Entry.Body != "From a script\n\n#build @ci-bot" {
+		t.Errorf("line 1 = %+v", items[0].Entry)
+	}
+	if items[1].Action != ImportFailed || items[1].Source != "line 3" {
+		t.Errorf("line 3 = %+v, want failed without a time format", items[1])
+	}
+	if items[2].Action != ImportFailed || !strings.Contains(items[2].Err.Error(), "invalid JSON") {
+		t.Errorf("line 4 = %+v", items[2])
+	}
+	if items[3].Action != ImportNew || items[3].Entry.Body != "42" {
+		t.Errorf("line 5 = %+v", items[3])
+	}
+
+	options.TimeFormat = "02/01/2006 15:04"
+	items, err = ParseJSONLRecords([]byte(content), options)
+	if err != nil {
+		t.Fatalf("ParseJSONLRecords() error = %v", err)
+	}
+	want := time.Date(2024, 2, 9, 10, 15, 0, 0, time.Local)
+	if items[1].Action != ImportNew || !items[1].Entry.Timestamp.Equal(want) || items[1].Entry.Body != "Custom format\n\n#ops" {
+		t.Errorf("line 3 with time format = %+v", items[1].Entry)
+	}
+}
@@ -0,0 +1,317 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConflictResolver resolves an entry edited on both sides of a sync whose changes overlap.
+// It receives the entry's path and the merged text with conflict markers and returns the
+// resolved text (for example, after the user edited it).
+type ConflictResolver func(path, merged string) (string, error)
+
+// SyncResult summarizes a sync with a git remote
+type SyncResult struct {
+	Committed bool         // Local changes were committed before syncing
+	Pulled    []string     // Files changed by the pull
+	Renamed   []LayoutMove // New entries from the remote moved to a collision-suffixed path
+	Merged    []string     // Entries edited on both sides, merged automatically
+	Resolved  []string     // Entries edited on both sides, resolved by the ConflictResolver
+	Kept      []string     // Entries deleted on one side and edited on the other (kept)
+	Pushed    bool
+}
+
+// syncExcludes are storage paths that never take part in a sync
+var syncExcludes = []string{":(exclude)" + DraftsDirName, ":(exclude,glob)**/" + tempFilePrefix + "*"}
+
+// SyncGit commits local changes, merges the remote branch and pushes the result.
+// Conflicts are resolved per entry: new entries with the same timestamp on both sides
+// are kept as collision-suffixed files, entries edited on both sides are merged (calling
+// resolve for overlapping changes), and an entry deleted on one side but edited on the
+// other is kept. If a conflict can't be resolved, or a file that isn't an entry (like the
+// signatures) changed differently on both sides, the merge is aborted and nothing is pushed.
+func (fs *FileSystemStorage) SyncGit(remote string, resolve ConflictResolver) (*SyncResult, error) {
+	repo := NewGitRepo(fs.basePath)
+	if !repo.IsRepo() {
+		return nil, ErrNotGitRepository
+	}
+	if _, err := repo.run("rev-parse", "--verify", "--quiet", "MERGE_HEAD"); err == nil {
+		return nil, fmt.Errorf("a git merge is in progress in the storage directory; finish or abort it first")
+	}
+
+	result := &SyncResult{}
+
+	// 1. Commit local changes so the merge works on a clean tree
+	committed, err := repo.CommitAll("sync: local changes", syncExcludes...)
+	if err != nil {
+		return nil, err
+	}
+	result.Committed = committed
+
+	branch, err := repo.run("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("cannot sync a detached HEAD: %w", err)
+	}
+	branch = strings.TrimSpace(branch)
+
+	// 2. Fetch and merge the remote branch, if it exists yet
+	if _, err := repo.run("fetch", "--quiet", remote); err != nil {
+		return nil, err
+	}
+	remoteRef := "refs/remotes/" + remote + "/" + branch
+	if _, err := repo.run("rev-parse", "--verify", "--quiet", remoteRef); err == nil {
+		pulled, err := fs.mergeRemote(repo, remoteRef, resolve, result)
+		if err != nil {
+			return result, err
+		}
+		result.Pulled = pulled
+	}
+
+	// 3. Update the layout and index from the pulled files
+	if len(result.Pulled) > 0 {
+		if state, err := readLayoutState(fs.basePath); err == nil && state != nil {
+			fs.layout = state.Layout.orDefault()
+			fs.migratingTo = state.MigratingTo
+		}
+		fs.reloadEncryptionState()
+		fs.RefreshIndex(result.Pulled)
+	}
+
+	// 4. Push, unless there is nothing to push yet
+	if _, err := repo.run("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return result, nil
+	}
+	if _, err := repo.run("push", "--quiet", remote, "HEAD:refs/heads/"+branch); err != nil {
+		return result, err
+	}
+	result.Pushed = true
+
+	return result, nil
+}
+
+// mergeRemote merges remoteRef into the current branch, resolving conflicts per entry
+// Returns the absolute paths of the files the merge changed
+func (fs *FileSystemStorage) mergeRemote(repo *GitRepo, remoteRef string, resolve ConflictResolver, result *SyncResult) ([]string, error) {
+	oldHead, headErr := repo.run("rev-parse", "--verify", "--quiet", "HEAD")
+	oldHead = strings.TrimSpace(oldHead)
+
+	_, mergeErr := repo.run("merge", "--quiet", "--no-edit", "--allow-unrelated-histories",
+		"-m", "sync: merge "+strings.TrimPrefix(remoteRef, "refs/remotes/"), remoteRef)
+	if mergeErr != nil {
+		unmerged, err := repo.unmergedFiles()
+		if err != nil || len(unmerged) == 0 {
+			_, _ = repo.run("merge", "--abort")
+			return nil, errors.Join(mergeErr, err)
+		}
+
+		if err := fs.resolveSyncConflicts(repo, unmerged, resolve, result); err != nil {
+			_, _ = repo.run("merge", "--abort")
+			return nil, fmt.Errorf("sync aborted: %w", err)
+		}
+
+		if _, err := repo.run("commit", "--quiet", "--no-edit"); err != nil {
+			_, _ = repo.run("merge", "--abort")
+			return nil, err
+		}
+	}
+
+	// Files changed by the pull
+	var out string
+	var err error
+	if headErr != nil {
+		out, err = repo.run("ls-files", "-z", "--", ".") // Nothing local before: everything is new
+	} else {
+		out, err = repo.run("diff", "--name-only", "-z", "--relative", oldHead, "HEAD", "--", ".")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pulled []string
+	for _, rel := range splitNull(out) {
+		pulled = append(pulled, filepath.Join(fs.basePath, rel))
+	}
+	return pulled, nil
+}
+
+// resolveSyncConflicts resolves each unmerged file and stages the result. Files that
+// aren't entries (signatures, encryption parameters, the layout state) are only taken
+// when both sides agree: picking one side would drop the other's signed links or leave
+// entries encrypted with a key this side doesn't know, so they fail the sync before any
+// entry is resolved.
+func (fs *FileSystemStorage) resolveSyncConflicts(repo *GitRepo, unmerged map[string]map[int]string, resolve ConflictResolver, result *SyncResult) error {
+	var unresolved []string
+	for rel, stages := range unmerged {
+		if isMarkdownFile(rel) {
+			continue
+		}
+		ours, hasOurs := stages[2]
+		theirs, hasTheirs := stages[3]
+		if !hasOurs || !hasTheirs || ours != theirs {
+			unresolved = append(unresolved, rel)
+		}
+	}
+	if len(unresolved) > 0 {
+		sort.Strings(unresolved)
+		return fmt.Errorf("changed differently on both sides, merge with git in %s: %s", fs.basePath, strings.Join(unresolved, ", "))
+	}
+
+	reserved := make(map[string]bool)
+	for rel, stages := range unmerged {
+		path := filepath.Join(fs.basePath, rel)
+
+		version := func(stage int) (string, error) {
+			return repo.run("cat-file", "blob", stages[stage])
+		}
+		_, hasBase := stages[1]
+		_, hasOurs := stages[2]
+		_, hasTheirs := stages[3]
+
+		var staged []string
+		switch {
+		case !isMarkdownFile(rel):
+			// Not an entry, the same on both sides
+			content, err := version(2)
+			if err != nil {
+				return err
+			}
+			if err := fs.writeAtomic(path, []byte(content)); err != nil {
+				return err
+			}
+			staged = append(staged, rel)
+
+		case hasOurs && hasTheirs && !hasBase:
+			// New entries with the same path on both sides: keep both
+			moved, err := fs.keepBothEntries(path, version, reserved)
+			if err != nil {
+				return fmt.Errorf("%s: %w", rel, err)
+			}
+			result.Renamed = append(result.Renamed, LayoutMove{From: path, To: moved})
+			movedRel, err := repo.relative(moved)
+			if err != nil {
+				return err
+			}
+			staged = append(staged, rel, movedRel)
+
+		case hasOurs && hasTheirs:
+			// Edited on both sides
+			resolved, err := fs.mergeEntryVersions(path, version, resolve)
+			if err != nil {
+				return fmt.Errorf("%s: %w", rel, err)
+			}
+			if resolved {
+				result.Resolved = append(result.Resolved, path)
+			} else {
+				result.Merged = append(result.Merged, path)
+			}
+			staged = append(staged, rel)
+
+		default:
+			// Deleted on one side, edited on the other: keep the edit
+			keep := 2
+			if !hasOurs {
+				keep = 3
+			}
+			content, err := version(keep)
+			if err != nil {
+				return err
+			}
+			if err := fs.ensureDirectories(path); err != nil {
+				return err
+			}
+			if err := fs.writeAtomic(path, []byte(content)); err != nil {
+				return err
+			}
+			result.Kept = append(result.Kept, path)
+			staged = append(staged, rel)
+		}
+
+		if _, err := repo.run(append([]string{"add", "--"}, staged...)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// keepBothEntries writes the local version of an entry at path and the remote version at
+// the next free collision-suffixed path. Returns the path of the remote version.
+func (fs *FileSystemStorage) keepBothEntries(path string, version func(int) (string, error), reserved map[string]bool) (string, error) {
+	ours, err := version(2)
+	if err != nil {
+		return "", err
+	}
+	theirs, err := version(3)
+	if err != nil {
+		return "", err
+	}
+
+	theirsText, err := fs.decode([]byte(theirs))
+	if err != nil {
+		return "", err
+	}
+	theirsEntry, err := ParseEntry(string(theirsText))
+	if err != nil {
+		return "", fmt.Errorf("remote version is not a valid entry: %w", err)
+	}
+
+	if err := fs.writeAtomic(path, []byte(ours)); err != nil {
+		return "", err
+	}
+
+	target := fs.freeEntryPath(fs.layout, theirsEntry, "", reserved)
+	if target == "" {
+		return "", fmt.Errorf("too many entries with same timestamp")
+	}
+	reserved[target] = true
+
+	if err := fs.ensureDirectories(target); err != nil {
+		return "", err
+	}
+	if err := fs.writeAtomic(target, []byte(theirs)); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// mergeEntryVersions three-way merges an entry edited on both sides and writes the result
+// Returns true if the merge needed the resolver
+func (fs *FileSystemStorage) mergeEntryVersions(path string, version func(int) (string, error), resolve ConflictResolver) (bool, error) {
+	var texts [3]string
+	for i := range texts {
+		text, err := version(i + 1)
+		if err != nil {
+			return false, err
+		}
+		plaintext, err := fs.decode([]byte(text))
+		if err != nil {
+			return false, err
+		}
+		texts[i] = string(plaintext)
+	}
+
+	merged, conflicts := MergeText(texts[0], texts[1], texts[2])
+	if conflicts {
+		if resolve == nil {
+			return false, fmt.Errorf("edited on both sides")
+		}
+		var err error
+		if merged, err = resolve(path, merged); err != nil {
+			return false, err
+		}
+		if HasConflictMarkers(merged) {
+			return false, fmt.Errorf("unresolved conflict markers remain")
+		}
+	}
+
+	if _, err := ParseEntry(merged); err != nil {
+		return false, fmt.Errorf("merged entry is invalid: %w", err)
+	}
+	if err := fs.writeEntryFile(path, []byte(merged)); err != nil {
+		return false, err
+	}
+	return conflicts, nil
+}
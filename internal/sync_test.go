@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupSyncClones creates a bare remote and two journal clones using it as "origin"
+func setupSyncClones(t *testing.T) (*FileSystemStorage, *FileSystemStorage) {
+	t.Helper()
+	_, remote := setupGitRepo(t)
+	if _, err := remote.run("init", "--quiet", "--bare"); err != nil {
+		t.Fatalf("git init --bare error = %v", err)
+	}
+
+	clone := func() *FileSystemStorage {
+		dir, repo := setupGitRepo(t)
+		if _, err := repo.run("remote", "add", "origin", remote.dir); err != nil {
+			t.Fatalf("git remote add error = %v", err)
+		}
+		// Same branch name regardless of the git default
+		if _, err := repo.run("symbolic-ref", "HEAD", "refs/heads/main"); err != nil {
+			t.Fatalf("git symbolic-ref error = %v", err)
+		}
+		return NewFileSystemStorage(dir, nil)
+	}
+	return clone(), clone()
+}
+
+func syncGit(t *testing.T, storage *FileSystemStorage, resolve ConflictResolver) *SyncResult {
+	t.Helper()
+	result, err := storage.SyncGit("origin", resolve)
+	if err != nil {
+		t.Fatalf("SyncGit() error = %v", err)
+	}
+	return result
+}
+
+func TestSyncGit_NewEntries(t *testing.T) {
+	a, b := setupSyncClones(t)
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+
+	// Build b's index before the sync to check it is updated from the pulled files
+	if err := b.SaveEntry(&JournalEntry{Timestamp: ts, Body: "From B #b"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	if _, err := b.SearchByTags([]string{"b"}, EntryFilter{}); err != nil {
+		t.Fatalf("SearchByTags() error = %v", err)
+	}
+
+	// Same timestamp on both sides, plus an entry only A has
+	if err := a.SaveEntry(&JournalEntry{Timestamp: ts, Body: "From A #a"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	if err := a.SaveEntry(&JournalEntry{Timestamp: ts.Add(time.Hour), Body: "Later from A #a"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	if result := syncGit(t, a, nil); !result.Committed || !result.Pushed {
+		t.Errorf("first sync = %+v, want committed and pushed", result)
+	}
+
+	result := syncGit(t, b, nil)
+	if len(result.Renamed) != 1 {
+		t.Fatalf("Renamed = %v, want one collision-suffixed entry", result.Renamed)
+	}
+	if !strings.HasSuffix(result.Renamed[0].To, "2026-02-09-14-30-00-01.md") {
+		t.Errorf("Renamed to %s, want collision suffix", result.Renamed[0].To)
+	}
+
+	// The index was updated from the pulled files
+	found, err := b.SearchByTags([]string{"a"}, EntryFilter{})
+	if err != nil {
+		t.Fatalf("SearchByTags() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("SearchByTags(a) after sync = %d entries, want 2", len(found))
+	}
+
+	// A gets B's entry back
+	syncGit(t, a, nil)
+	for _, storage := range []*FileSystemStorage{a, b} {
+		entries, err := NewFileSystemStorage(storage.basePath, nil).ListEntries(EntryFilter{})
+		if err != nil {
+			t.Fatalf("ListEntries() error = %v", err)
+		}
+		if len(entries) != 3 {
+			t.Errorf("%s has %d entries after sync, want 3", storage.basePath, len(entries))
+		}
+	}
+}
+
+func TestSyncGit_ConcurrentEdits(t *testing.T) {
+	a, b := setupSyncClones(t)
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+
+	if err := a.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Line one\nLine two\nLine three"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	syncGit(t, a, nil)
+	syncGit(t, b, nil)
+
+	path, err := a.GetEntryPath(ts)
+	if err != nil {
+		t.Fatalf("GetEntryPath() error = %v", err)
+	}
+	pathB := filepath.Join(b.basePath, strings.TrimPrefix(path, a.basePath))
+
+	// Both sides change the same line
+	if err := a.UpdateEntry(path, &JournalEntry{Timestamp: ts, Body: "Line one\nLine two (A)\nLine three"}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	if err := b.UpdateEntry(pathB, &JournalEntry{Timestamp: ts, Body: "Line one\nLine two (B)\nLine three"}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	syncGit(t, a, nil)
+
+	// Without a resolver the sync is aborted and nothing is lost
+	if _, err := b.SyncGit("origin", nil); err == nil {
+		t.Fatal("SyncGit() without resolver should fail on overlapping edits")
+	}
+	if content, _ := os.ReadFile(pathB); !strings.Contains(string(content), "Line two (B)") {
+		t.Errorf("local edit lost after aborted sync: %q", content)
+	}
+
+	// The resolver sees both versions and its result is committed
+	var seen string
+	result := syncGit(t, b, func(path, merged string) (string, error) {
+		seen = merged
+		return strings.NewReplacer(
+			ConflictMarkerMine+"\n", "", ConflictMarkerBase+"\n", "", ConflictMarkerTheirs+"\n", "",
+			"Line two (A)\n", "",
+		).Replace(merged), nil
+	})
+	if !strings.Contains(seen, "Line two (A)") || !strings.Contains(seen, "Line two (B)") {
+		t.Errorf("resolver got %q, want both versions", seen)
+	}
+	if len(result.Resolved) != 1 {
+		t.Errorf("Resolved = %v, want 1 entry", result.Resolved)
+	}
+
+	syncGit(t, a, nil)
+	entry, err := NewFileSystemStorage(a.basePath, nil).GetEntry(ts)
+	if err != nil {
+		t.Fatalf("GetEntry() error = %v", err)
+	}
+	if entry.Body != "Line one\nLine two (B)\nLine three" {
+		t.Errorf("resolved body = %q", entry.Body)
+	}
+}
+
+func TestSyncGit_ConflictingStateFile(t *testing.T) {
+	a, b := setupSyncClones(t)
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+	if err := a.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Shared"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	syncGit(t, a, nil)
+	syncGit(t, b, nil)
+
+	// Both sides sign (or re-key) the journal differently
+	writeTestFile(t, a.basePath, ChainFile, "{\"seq\":1,\"signer\":\"a\"}\n")
+	writeTestFile(t, b.basePath, ChainFile, "{\"seq\":1,\"signer\":\"b\"}\n")
+	syncGit(t, a, nil)
+
+	_, err := b.SyncGit("origin", nil)
+	if err == nil || !strings.Contains(err.Error(), ChainFile) {
+		t.Fatalf("SyncGit() error = %v, want the conflicting %s reported", err, ChainFile)
+	}
+	if content, _ := os.ReadFile(filepath.Join(b.basePath, ChainFile)); !strings.Contains(string(content), "\"b\"") {
+		t.Errorf("local %s after aborted sync = %q", ChainFile, content)
+	}
+	if _, err := NewGitRepo(b.basePath).run("rev-parse", "--verify", "--quiet", "MERGE_HEAD"); err == nil {
+		t.Error("merge left in progress after aborted sync")
+	}
+}
+
+func TestSyncGit_NotRepository(t *testing.T) {
+	storage := NewFileSystemStorage(t.TempDir(), nil)
+	if NewGitRepo(storage.basePath).IsRepo() {
+		t.Skip("temp directory is inside a git repository")
+	}
+	if _, err := storage.SyncGit("origin", nil); err != ErrNotGitRepository {
+		t.Errorf("SyncGit() error = %v, want ErrNotGitRepository", err)
+	}
+}
@@ -0,0 +1,362 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Tombstone records an entry file deleted from a storage tree, so the deletion can be
+// propagated to trees synced later
+type Tombstone struct {
+	Hash      string    `json:"hash"` // Content hash of the deleted file
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// syncState is stored in the storage root to reconcile directory syncs
+type syncState struct {
+	ID         string                       `json:"id"`         // Identifies this tree to its peers
+	Peers      map[string]map[string]string `json:"peers"`      // Peer ID -> path -> content hash at the last sync
+	Tombstones map[string]Tombstone         `json:"tombstones"` // Path -> deleted file
+}
+
+// DirSyncResult summarizes a directory sync; paths are relative to the storage roots
+type DirSyncResult struct {
+	ToPeer       []string // New or edited entries copied to the other tree
+	FromPeer     []string // New or edited entries copied from the other tree
+	DeletedHere  []string // Entries deleted here because they were deleted in the other tree
+	DeletedThere []string // Entries deleted in the other tree because they were deleted here
+	Restored     []string // Entries deleted on one side but edited on the other (edit kept)
+	Conflicts    []string // Entries edited on both sides (left untouched)
+}
+
+// Changes returns the number of files copied or deleted
+func (r *DirSyncResult) Changes() int {
+	return len(r.ToPeer) + len(r.FromPeer) + len(r.DeletedHere) + len(r.DeletedThere) + len(r.Restored)
+}
+
+// SyncDir reconciles this storage tree with another one (e.g. a copy on a USB stick).
+// Entries are compared by content hash with the state recorded at the last sync between
+// the two trees: new and edited entries are copied, deletions are propagated (and recorded
+// as tombstones, so trees that never synced with each other directly also learn about them),
+// and entries edited on both sides are reported as conflicts without overwriting either copy.
+func (fs *FileSystemStorage) SyncDir(peer *FileSystemStorage) (*DirSyncResult, error) {
+	if err := fs.checkPeerLayout(peer); err != nil {
+		return nil, err
+	}
+	if err := fs.checkPeerEncryption(peer); err != nil {
+		return nil, err
+	}
+	if sameDirectory(fs.basePath, peer.basePath) {
+		return nil, fmt.Errorf("cannot sync a journal with itself")
+	}
+	if err := fs.checkNoArchives("syncing"); err != nil {
+		return nil, err
+	}
+	if err := peer.checkNoArchives("syncing"); err != nil {
+		return nil, fmt.Errorf("%s: %w", peer.basePath, err)
+	}
+
+	localState, err := readSyncState(fs.basePath)
+	if err != nil {
+		return nil, err
+	}
+	peerState, err := readSyncState(peer.basePath)
+	if err != nil {
+		return nil, err
+	}
+	if localState.ID == peerState.ID {
+		return nil, fmt.Errorf("cannot sync a journal with itself")
+	}
+
+	local, err := fs.fileHashes()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := peer.fileHashes()
+	if err != nil {
+		return nil, err
+	}
+
+	// The common state after the last sync; either side's record will do
+	base := localState.Peers[peerState.ID]
+	if base == nil {
+		base = peerState.Peers[localState.ID]
+	}
+
+	paths := make(map[string]bool)
+	for _, hashes := range []map[string]string{local, remote, base} {
+		for path := range hashes {
+			paths[path] = true
+		}
+	}
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	result := &DirSyncResult{}
+	synced := make(map[string]string) // New common state
+	now := time.Now()
+
+	for _, path := range sorted {
+		l, r, b := local[path], remote[path], base[path]
+
+		switch {
+		case l == r:
+			if l != "" {
+				synced[path] = l
+			}
+
+		case r == "":
+			// Only here: new, deleted in the peer, or edited here and deleted there
+			switch {
+			case b == l || (b == "" && peerState.Tombstones[path].Hash == l):
+				if err := fs.removeFile(filepath.Join(fs.basePath, path)); err != nil {
+					return result, err
+				}
+				localState.Tombstones[path] = Tombstone{Hash: l, DeletedAt: now}
+				peerState.Tombstones[path] = Tombstone{Hash: l, DeletedAt: now}
+				result.DeletedHere = append(result.DeletedHere, path)
+			default:
+				if err := copyEntryFile(fs, peer, path); err != nil {
+					return result, err
+				}
+				synced[path] = l
+				if b != "" {
+					result.Restored = append(result.Restored, path)
+				} else {
+					result.ToPeer = append(result.ToPeer, path)
+				}
+			}
+
+		case l == "":
+			// Only in the peer: the mirror image of the case above
+			switch {
+			case b == r || (b == "" && localState.Tombstones[path].Hash == r):
+				if err := peer.removeFile(filepath.Join(peer.basePath, path)); err != nil {
+					return result, err
+				}
+				localState.Tombstones[path] = Tombstone{Hash: r, DeletedAt: now}
+				peerState.Tombstones[path] = Tombstone{Hash: r, DeletedAt: now}
+				result.DeletedThere = append(result.DeletedThere, path)
+			default:
+				if err := copyEntryFile(peer, fs, path); err != nil {
+					return result, err
+				}
+				synced[path] = r
+				if b != "" {
+					result.Restored = append(result.Restored, path)
+				} else {
+					result.FromPeer = append(result.FromPeer, path)
+				}
+			}
+
+		case b == l:
+			// Edited in the peer only
+			if err := copyEntryFile(peer, fs, path); err != nil {
+				return result, err
+			}
+			synced[path] = r
+			result.FromPeer = append(result.FromPeer, path)
+
+		case b == r:
+			// Edited here only
+			if err := copyEntryFile(fs, peer, path); err != nil {
+				return result, err
+			}
+			synced[path] = l
+			result.ToPeer = append(result.ToPeer, path)
+
+		default:
+			// Edited on both sides (or added on both sides with different content)
+			if b != "" {
+				synced[path] = b // Keep reporting until resolved
+			}
+			result.Conflicts = append(result.Conflicts, path)
+		}
+
+		// Deleted on both sides since the last sync
+		if l == "" && r == "" && b != "" {
+			localState.Tombstones[path] = Tombstone{Hash: b, DeletedAt: now}
+		}
+	}
+
+	// Share tombstones, so deletions travel on to the next tree
+	for path, tombstone := range localState.Tombstones {
+		if _, ok := peerState.Tombstones[path]; !ok {
+			peerState.Tombstones[path] = tombstone
+		}
+	}
+	for path, tombstone := range peerState.Tombstones {
+		if _, ok := localState.Tombstones[path]; !ok {
+			localState.Tombstones[path] = tombstone
+		}
+	}
+
+	localState.Peers[peerState.ID] = synced
+	peerState.Peers[localState.ID] = synced
+	if err := fs.writeSyncState(localState); err != nil {
+		return result, err
+	}
+	if err := peer.writeSyncState(peerState); err != nil {
+		return result, err
+	}
+
+	if result.Changes() > 0 {
+		fs.removeEmptyDirs()
+		peer.removeEmptyDirs()
+		fs.InvalidateIndex()
+		peer.InvalidateIndex()
+	}
+
+	return result, nil
+}
+
+// checkPeerLayout makes sure both trees store entries at the same paths
+// A tree without a recorded layout (e.g. a new, empty copy) adopts this one's
+func (fs *FileSystemStorage) checkPeerLayout(peer *FileSystemStorage) error {
+	if err := os.MkdirAll(peer.basePath, DirPermissions); err != nil {
+		return err
+	}
+
+	peerState, err := readLayoutState(peer.basePath)
+	if err != nil {
+		return err
+	}
+	if peerState == nil {
+		peer.layout = fs.layout
+		if err := peer.ensureLayoutState(); err != nil {
+			return err
+		}
+		return fs.ensureLayoutState()
+	}
+
+	if peer.layout != fs.layout || peer.migratingTo != "" || fs.migratingTo != "" {
+		return fmt.Errorf("storage layouts differ (%s here, %s in %s); run 'jrnlg migrate-layout' on one of them first",
+			fs.layout, peer.layout, peer.basePath)
+	}
+	return nil
+}
+
+// checkPeerEncryption makes sure entry files can be copied between both trees as they are:
+// either both are unencrypted or both use the same key. An empty tree adopts the other's key.
+func (fs *FileSystemStorage) checkPeerEncryption(peer *FileSystemStorage) error {
+	if fs.encryptionErr != nil {
+		return fs.encryptionErr
+	}
+	if peer.encryptionErr != nil {
+		return peer.encryptionErr
+	}
+
+	switch {
+	case fs.encryption == nil && peer.encryption == nil:
+		return nil
+	case fs.encryption != nil && peer.encryption != nil:
+		if fs.encryption.sameKey(peer.encryption) {
+			return nil
+		}
+	case peer.encryption == nil && peer.isEmpty():
+		return peer.writeEncryptionState(fs.encryption)
+	case fs.encryption == nil && fs.isEmpty():
+		return fs.writeEncryptionState(peer.encryption)
+	}
+
+	return fmt.Errorf("encryption differs between this journal and %s; encrypt or decrypt one of them first (copies must share the same key)", peer.basePath)
+}
+
+// isEmpty reports whether the tree has no entry files
+func (fs *FileSystemStorage) isEmpty() bool {
+	files, err := fs.findAllFiles()
+	return err == nil && len(files) == 0
+}
+
+// sameDirectory reports whether two paths refer to the same directory
+func sameDirectory(a, b string) bool {
+	aInfo, errA := os.Stat(a)
+	bInfo, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(aInfo, bInfo)
+}
+
+// fileHashes returns the content hash of every entry file, keyed by path relative to the storage root
+func (fs *FileSystemStorage) fileHashes() (map[string]string, error) {
+	files, err := fs.findAllFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", fs.basePath, err)
+	}
+
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		hashes[fs.relativePath(file)] = contentHash(content)
+	}
+	return hashes, nil
+}
+
+// copyEntryFile copies the file at path (relative to the storage roots) from one tree to another
+func copyEntryFile(from, to *FileSystemStorage, path string) error {
+	content, err := os.ReadFile(filepath.Join(from.basePath, path))
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(to.basePath, path)
+	if err := to.ensureDirectories(target); err != nil {
+		return err
+	}
+	if err := to.writeAtomic(target, content); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", path, err)
+	}
+	return nil
+}
+
+// readSyncState loads the directory sync state, creating a new tree ID if there is none
+func readSyncState(basePath string) (*syncState, error) {
+	state := &syncState{}
+
+	data, err := os.ReadFile(filepath.Join(basePath, SyncStateFile))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("invalid %s in %s: %w", SyncStateFile, basePath, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	if state.ID == "" {
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return nil, err
+		}
+		state.ID = hex.EncodeToString(id)
+	}
+	if state.Peers == nil {
+		state.Peers = make(map[string]map[string]string)
+	}
+	if state.Tombstones == nil {
+		state.Tombstones = make(map[string]Tombstone)
+	}
+	return state, nil
+}
+
+// writeSyncState records the directory sync state in the storage root
+func (fs *FileSystemStorage) writeSyncState(state *syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fs.writeAtomic(filepath.Join(fs.basePath, SyncStateFile), data); err != nil {
+		return fmt.Errorf("failed to record sync state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func syncDir(t *testing.T, local, peer *FileSystemStorage) *DirSyncResult {
+	t.Helper()
+	result, err := local.SyncDir(peer)
+	if err != nil {
+		t.Fatalf("SyncDir() error = %v", err)
+	}
+	return result
+}
+
+func saveEntryAt(t *testing.T, storage *FileSystemStorage, ts time.Time, body string) string {
+	t.Helper()
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: body}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	path, err := storage.GetEntryPath(ts)
+	if err != nil {
+		t.Fatalf("GetEntryPath() error = %v", err)
+	}
+	return storage.relativePath(path)
+}
+
+func TestSyncDir_CopiesEditsAndDeletions(t *testing.T) {
+	home := NewFileSystemStorage(t.TempDir(), nil)
+	usb := NewFileSystemStorage(filepath.Join(t.TempDir(), "journal"), nil) // Doesn't exist yet
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+
+	kept := saveEntryAt(t, home, ts, "Kept")
+	edited := saveEntryAt(t, home, ts.Add(time.Hour), "Original")
+	deleted := saveEntryAt(t, home, ts.Add(2*time.Hour), "Deleted later")
+
+	// First sync copies everything to the new tree
+	result := syncDir(t, home, usb)
+	if !reflect.DeepEqual(result.ToPeer, []string{kept, edited, deleted}) {
+		t.Errorf("ToPeer = %v", result.ToPeer)
+	}
+
+	// Edit on the USB copy, delete at home, add on both
+	if err := usb.UpdateEntry(filepath.Join(usb.basePath, edited), &JournalEntry{Timestamp: ts.Add(time.Hour), Body: "Edited on the go"}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	if err := home.DeleteEntry(filepath.Join(home.basePath, deleted)); err != nil {
+		t.Fatalf("DeleteEntry() error = %v", err)
+	}
+	fromHome := saveEntryAt(t, home, ts.Add(3*time.Hour), "New at home")
+	fromUSB := saveEntryAt(t, usb, ts.Add(4*time.Hour), "New on the go")
+
+	result = syncDir(t, home, usb)
+	if !reflect.DeepEqual(result.ToPeer, []string{fromHome}) {
+		t.Errorf("ToPeer = %v, want [%s]", result.ToPeer, fromHome)
+	}
+	if !reflect.DeepEqual(result.FromPeer, []string{edited, fromUSB}) {
+		t.Errorf("FromPeer = %v, want [%s %s]", result.FromPeer, edited, fromUSB)
+	}
+	if !reflect.DeepEqual(result.DeletedThere, []string{deleted}) {
+		t.Errorf("DeletedThere = %v, want [%s]", result.DeletedThere, deleted)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Errorf("Conflicts = %v, want none", result.Conflicts)
+	}
+
+	// Both trees now match, and another sync changes nothing
+	for _, storage := range []*FileSystemStorage{home, usb} {
+		hashes, err := storage.fileHashes()
+		if err != nil {
+			t.Fatalf("fileHashes() error = %v", err)
+		}
+		if len(hashes) != 4 {
+			t.Errorf("%s has %d entries, want 4", storage.basePath, len(hashes))
+		}
+	}
+	if result := syncDir(t, usb, home); result.Changes() != 0 || len(result.Conflicts) != 0 {
+		t.Errorf("repeated sync = %+v, want no changes", result)
+	}
+}
+
+func TestSyncDir_ConflictingEdits(t *testing.T) {
+	home := NewFileSystemStorage(t.TempDir(), nil)
+	usb := NewFileSystemStorage(t.TempDir(), nil)
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+
+	path := saveEntryAt(t, home, ts, "Original")
+	syncDir(t, home, usb)
+
+	for storage, body := range map[*FileSystemStorage]string{home: "Home edit", usb: "USB edit"} {
+		if err := storage.UpdateEntry(filepath.Join(storage.basePath, path), &JournalEntry{Timestamp: ts, Body: body}); err != nil {
+			t.Fatalf("UpdateEntry() error = %v", err)
+		}
+	}
+
+	// Reported on every sync until resolved, and neither copy is overwritten
+	for range 2 {
+		result := syncDir(t, home, usb)
+		if !reflect.DeepEqual(result.Conflicts, []string{path}) {
+			t.Errorf("Conflicts = %v, want [%s]", result.Conflicts, path)
+		}
+	}
+	for storage, body := range map[*FileSystemStorage]string{home: "Home edit", usb: "USB edit"} {
+		entry, err := storage.GetEntry(ts)
+		if err != nil || entry.Body != body {
+			t.Errorf("entry in %s = %+v, %v; want body %q", storage.basePath, entry, err, body)
+		}
+	}
+
+	// Resolving on one side (making them equal) clears the conflict
+	if err := usb.UpdateEntry(filepath.Join(usb.basePath, path), &JournalEntry{Timestamp: ts, Body: "Home edit"}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	if result := syncDir(t, home, usb); len(result.Conflicts) != 0 {
+		t.Errorf("Conflicts after resolving = %v", result.Conflicts)
+	}
+}
+
+func TestSyncDir_TombstonesReachOtherTrees(t *testing.T) {
+	home := NewFileSystemStorage(t.TempDir(), nil)
+	usb := NewFileSystemStorage(t.TempDir(), nil)
+	office := NewFileSystemStorage(t.TempDir(), nil)
+	ts := time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC)
+
+	// The office got a copy of the entry some other way (never synced with the USB stick)
+	path := saveEntryAt(t, home, ts, "Shared")
+	content, err := os.ReadFile(filepath.Join(home.basePath, path))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	writeTestFile(t, office.basePath, path, string(content))
+
+	syncDir(t, home, usb)
+	if err := home.DeleteEntry(filepath.Join(home.basePath, path)); err != nil {
+		t.Fatalf("DeleteEntry() error = %v", err)
+	}
+	syncDir(t, home, usb)
+
+	result := syncDir(t, office, usb)
+	if !reflect.DeepEqual(result.DeletedHere, []string{path}) {
+		t.Errorf("DeletedHere = %v, want [%s]", result.DeletedHere, path)
+	}
+	if len(result.ToPeer) != 0 {
+		t.Errorf("deleted entry was copied back: %v", result.ToPeer)
+	}
+}
+
+func TestSyncDir_LayoutMismatch(t *testing.T) {
+	home := NewFileSystemStorage(t.TempDir(), nil)
+	flat := DefaultConfig()
+	flat.Layout = LayoutFlat
+	usb := NewFileSystemStorage(t.TempDir(), flat)
+
+	saveEntryAt(t, home, time.Date(2026, 2, 9, 14, 30, 0, 0, time.UTC), "Home")
+	saveEntryAt(t, usb, time.Date(2026, 2, 9, 15, 30, 0, 0, time.UTC), "USB")
+
+	if _, err := home.SyncDir(usb); err == nil {
+		t.Error("SyncDir() should refuse trees with different layouts")
+	}
+	if _, err := home.SyncDir(home); err == nil {
+		t.Error("SyncDir() should refuse to sync a tree with itself")
+	}
+}
@@ -0,0 +1,623 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// webdavTimeout limits each request to the WebDAV server
+	webdavTimeout = 30 * time.Second
+	// webdavFetchWorkers is the number of entries downloaded in parallel
+	webdavFetchWorkers = 8
+	// webdavCacheState is the file in the cache directory recording what it holds
+	webdavCacheState = "cache.json"
+)
+
+// monthDirName matches month collection names
+var monthDirName = regexp.MustCompile(`^\d{2}$`)
+
+// errPreconditionFailed is returned for a write rejected because of an If-Match or
+// If-None-Match header
+var errPreconditionFailed = errors.New("precondition failed")
+
+// errNotOnServer is returned for an entry file that doesn't exist on the server
+var errNotOnServer = errors.New("not found on the WebDAV server")
+
+// WebDAVStorage stores entries on a WebDAV server, one file per entry in year and month
+// collections (<year>/<month>/YYYY-MM-DD-HH-MM-SS.md) below the journal's URL.
+//
+// The server is listed with PROPFIND when the storage is opened, and new or changed
+// entries are downloaded into a local cache directory, one per journal URL; entries are
+// then served from memory. When the server can't be reached, the cached entries are served instead.
+// Writes go straight to the server: new entries are created with If-None-Match, and
+// updates and deletions are made with If-Match on the last ETag seen, so changes made
+// by someone else in the meantime are never overwritten.
+type WebDAVStorage struct {
+	*MemoryStorage
+	client   *http.Client
+	baseURL  *url.URL // Journal collection, with a trailing slash
+	user     string
+	password string
+	cacheDir string
+	files    map[string]webdavFile // Server file of each entry path
+	offline  bool
+	mu       sync.Mutex
+}
+
+// webdavFile is an entry file on the WebDAV server
+type webdavFile struct {
+	Path string `json:"path"` // Relative to the journal collection
+	ETag string `json:"etag"` // As last seen on the server
+}
+
+// webdavCache records the entry files held in the cache directory
+type webdavCache struct {
+	URL   string                `json:"url"`
+	Files map[string]webdavFile `json:"files"`
+}
+
+// OpenWebDAVStorage opens the journal stored in the WebDAV collection at rawURL, using
+// the credentials and cache directory from config
+func OpenWebDAVStorage(rawURL string, config *Config) (*WebDAVStorage, error) {
+	baseURL, err := url.Parse(rawURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid WebDAV URL %q", rawURL)
+	}
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		baseURL.Path += "/"
+	}
+
+	// Each journal URL has its own cache below the cache directory
+	sum := sha256.Sum256([]byte(baseURL.String()))
+	cacheDir := filepath.Join(config.WebDAVCache, hex.EncodeToString(sum[:8]))
+	if err := os.MkdirAll(cacheDir, DirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	w := &WebDAVStorage{
+		MemoryStorage: NewMemoryStorage(),
+		client:        &http.Client{Timeout: webdavTimeout},
+		baseURL:       baseURL,
+		user:          config.WebDAVUser,
+		password:      config.WebDAVPassword,
+		cacheDir:      cacheDir,
+		files:         make(map[string]webdavFile),
+	}
+	if err := w.sync(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Offline reports whether the server couldn't be reached when the storage was opened,
+// so entries are served from the cache
+func (w *WebDAVStorage) Offline() bool {
+	return w.offline
+}
+
+// sync lists the entries on the server and loads them, downloading only those that
+// aren't cached with the same ETag
+func (w *WebDAVStorage) sync() error {
+	cached := w.readCache()
+
+	remote, err := w.listRemote()
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// Server unreachable: serve what was cached, if the journal was ever opened here
+		if _, statErr := os.Stat(filepath.Join(w.cacheDir, webdavCacheState)); statErr != nil {
+			return fmt.Errorf("WebDAV server unreachable and %s isn't cached here yet: %w", w.baseURL.Redacted(), err)
+		}
+		w.offline = true
+		for name, file := range cached {
+			if text, err := os.ReadFile(w.cachePath(name)); err == nil {
+				w.files[name] = file
+				w.MemoryStorage.put(name, string(text))
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var fetch []string
+	for name, file := range remote {
+		text, err := os.ReadFile(w.cachePath(name))
+		if err != nil || file.ETag == "" || cached[name].ETag != file.ETag {
+			fetch = append(fetch, name)
+			continue
+		}
+		w.files[name] = file
+		w.MemoryStorage.put(name, string(text))
+	}
+	if err := w.fetchAll(remote, fetch); err != nil {
+		return err
+	}
+
+	// Drop entries deleted on the server
+	for name := range cached {
+		if _, ok := remote[name]; !ok {
+			_ = os.Remove(w.cachePath(name))
+		}
+	}
+	return w.saveCache()
+}
+
+// fetchAll downloads the named entries in parallel and stores them
+func (w *WebDAVStorage) fetchAll(remote map[string]webdavFile, names []string) error {
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	var errs []error
+	work := make(chan string)
+
+	for i := 0; i < webdavFetchWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range work {
+				file := remote[name]
+				text, etag, err := w.get(file.Path)
+				if errors.Is(err, errNotOnServer) {
+					continue // Deleted since the listing
+				}
+				if err != nil {
+					errMu.Lock()
+					errs = append(errs, err)
+					errMu.Unlock()
+					continue
+				}
+				if etag != "" {
+					file.ETag = etag
+				}
+
+				w.mu.Lock()
+				w.stored(name, file, text)
+				w.mu.Unlock()
+			}
+		}()
+	}
+
+	for _, name := range names {
+		work <- name
+	}
+	close(work)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// listRemote walks the year and month collections of the journal and returns its entry
+// files by entry path
+func (w *WebDAVStorage) listRemote() (map[string]webdavFile, error) {
+	files := make(map[string]webdavFile)
+
+	years, err := w.propfind("")
+	if err != nil {
+		return nil, err
+	}
+	for _, year := range years {
+		if !year.collection || !yearDirName.MatchString(year.name) {
+			continue
+		}
+		months, err := w.propfind(year.name + "/")
+		if err != nil {
+			return nil, err
+		}
+		for _, month := range months {
+			if !month.collection || !monthDirName.MatchString(month.name) {
+				continue
+			}
+			dir := year.name + "/" + month.name + "/"
+			members, err := w.propfind(dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				if _, _, ok := parseEntryFileName(member.name); ok && !member.collection {
+					files[member.name] = webdavFile{Path: dir + member.name, ETag: member.etag}
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// propfindBody requests the properties needed to list a collection
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:"><D:prop><D:resourcetype/><D:getetag/></D:prop></D:propfind>`
+
+// davMultistatus is the body of a PROPFIND response
+type davMultistatus struct {
+	Responses []struct {
+		Href      string `xml:"DAV: href"`
+		Propstats []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"DAV: collection"`
+				} `xml:"DAV: resourcetype"`
+				ETag string `xml:"DAV: getetag"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// davResource is a member of a WebDAV collection
+type davResource struct {
+	name       string
+	collection bool
+	etag       string
+}
+
+// propfind lists the members of the collection at rel
+func (w *WebDAVStorage) propfind(rel string) ([]davResource, error) {
+	resp, err := w.do("PROPFIND", rel, []byte(propfindBody), http.Header{
+		"Depth":        {"1"},
+		"Content-Type": {"application/xml; charset=utf-8"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound && rel == "" {
+		return nil, fmt.Errorf("WebDAV collection not found: %s", w.baseURL)
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, webdavStatusError("PROPFIND", rel, resp)
+	}
+
+	var multistatus davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&multistatus); err != nil {
+		return nil, fmt.Errorf("WebDAV PROPFIND %s: invalid response: %w", rel, err)
+	}
+
+	self := strings.TrimSuffix(w.resolve(rel).Path, "/")
+	var members []davResource
+	for _, response := range multistatus.Responses {
+		href, err := url.Parse(response.Href)
+		if err != nil {
+			continue
+		}
+		hrefPath := strings.TrimSuffix(href.Path, "/")
+		if hrefPath == self {
+			continue
+		}
+
+		member := davResource{name: path.Base(hrefPath)}
+		for _, propstat := range response.Propstats {
+			member.collection = member.collection || propstat.Prop.ResourceType.Collection != nil
+			if propstat.Prop.ETag != "" {
+				member.etag = propstat.Prop.ETag
+			}
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// get downloads the file at rel, returning its content and ETag
+func (w *WebDAVStorage) get(rel string) (string, string, error) {
+	resp, err := w.do(http.MethodGet, rel, nil, nil)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", "", fmt.Errorf("%s: %w", rel, errNotOnServer)
+	case resp.StatusCode != http.StatusOK:
+		return "", "", webdavStatusError("GET", rel, resp)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("WebDAV GET %s: %w", rel, err)
+	}
+	return string(content), resp.Header.Get("ETag"), nil
+}
+
+// put uploads the file at rel, creating its year and month collections if needed
+// Returns the new ETag, or errPreconditionFailed if a condition in header failed
+func (w *WebDAVStorage) put(rel, text string, header http.Header) (string, error) {
+	resp, err := w.do(http.MethodPut, rel, []byte(text), header)
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		// Parent collection missing
+		if err := w.mkcol(path.Dir(rel)); err != nil {
+			return "", err
+		}
+		if resp, err = w.do(http.MethodPut, rel, []byte(text), header); err != nil {
+			return "", err
+		}
+		_ = resp.Body.Close()
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return "", errPreconditionFailed
+	case resp.StatusCode/100 != 2:
+		return "", webdavStatusError("PUT", rel, resp)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// mkcol creates the collection dir and its parents below the journal collection
+func (w *WebDAVStorage) mkcol(dir string) error {
+	var rel string
+	for _, name := range strings.Split(dir, "/") {
+		rel += name + "/"
+		resp, err := w.do("MKCOL", rel, nil, nil)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+
+		// 405 Method Not Allowed: the collection already exists
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMethodNotAllowed {
+			return webdavStatusError("MKCOL", rel, resp)
+		}
+	}
+	return nil
+}
+
+// do sends a request for rel, relative to the journal collection
+func (w *WebDAVStorage) do(method, rel string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, w.resolve(rel).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if w.user != "" {
+		req.SetBasicAuth(w.user, w.password)
+	}
+	return w.client.Do(req)
+}
+
+// resolve returns the URL of rel, relative to the journal collection
+func (w *WebDAVStorage) resolve(rel string) *url.URL {
+	return w.baseURL.ResolveReference(&url.URL{Path: rel})
+}
+
+// webdavStatusError describes an unexpected response from the server
+func webdavStatusError(method, rel string, resp *http.Response) error {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("WebDAV %s %s: %s (check JRNLG_WEBDAV_USER and JRNLG_WEBDAV_PASSWORD)", method, rel, resp.Status)
+	}
+	return fmt.Errorf("WebDAV %s %s: %s", method, rel, resp.Status)
+}
+
+// ifMatch returns the header making a write conditional on the file being unchanged
+func ifMatch(etag string) http.Header {
+	if etag == "" {
+		return nil // The server didn't report an ETag
+	}
+	return http.Header{"If-Match": {etag}}
+}
+
+// cachePath returns the path of an entry in the cache directory
+func (w *WebDAVStorage) cachePath(name string) string {
+	return filepath.Join(w.cacheDir, name)
+}
+
+// readCache returns the entry files recorded in the cache for this journal
+func (w *WebDAVStorage) readCache() map[string]webdavFile {
+	var cache webdavCache
+	content, err := os.ReadFile(filepath.Join(w.cacheDir, webdavCacheState))
+	if err != nil || json.Unmarshal(content, &cache) != nil || cache.URL != w.baseURL.String() {
+		return map[string]webdavFile{}
+	}
+	return cache.Files
+}
+
+// saveCache records the entry files in the cache
+func (w *WebDAVStorage) saveCache() error {
+	content, err := json.MarshalIndent(webdavCache{URL: w.baseURL.String(), Files: w.files}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return replaceFile(filepath.Join(w.cacheDir, webdavCacheState), content)
+}
+
+// stored records an entry as stored on the server, in memory and in the cache
+// Must be called with w.mu held
+func (w *WebDAVStorage) stored(name string, file webdavFile, text string) {
+	w.files[name] = file
+	w.MemoryStorage.put(name, text)
+	if err := replaceFile(w.cachePath(name), []byte(text)); err != nil {
+		_ = os.Remove(w.cachePath(name)) // Downloaded again on the next sync
+	}
+}
+
+// forget removes an entry deleted from the server
+// Must be called with w.mu held
+func (w *WebDAVStorage) forget(name string) {
+	delete(w.files, name)
+	_ = w.MemoryStorage.DeleteEntry(name)
+	_ = os.Remove(w.cachePath(name))
+}
+
+// refresh downloads the current version of an entry after a write found it changed
+// Must be called with w.mu held
+func (w *WebDAVStorage) refresh(name string) error {
+	file := w.files[name]
+	text, etag, err := w.get(file.Path)
+	switch {
+	case errors.Is(err, errNotOnServer):
+		w.forget(name)
+	case err != nil:
+		return err
+	default:
+		w.stored(name, webdavFile{Path: file.Path, ETag: etag}, text)
+	}
+	return w.saveCache()
+}
+
+// webdavEntryPath returns the server path of a new entry file
+func webdavEntryPath(timestamp time.Time, name string) string {
+	utc := timestamp.UTC()
+	return fmt.Sprintf("%04d/%02d/%s", utc.Year(), int(utc.Month()), name)
+}
+
+// SaveEntry uploads a new entry, adding a collision suffix to its path if needed
+func (w *WebDAVStorage) SaveEntry(entry *JournalEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	text := SerializeEntry(entry)
+	for collision := 0; collision < MaxCollisionAttempts; collision++ {
+		name := LayoutFlat.fileName(entry.Timestamp, collision, "")
+		if _, taken := w.files[name]; taken {
+			continue
+		}
+
+		rel := webdavEntryPath(entry.Timestamp, name)
+		etag, err := w.put(rel, text, http.Header{"If-None-Match": {"*"}})
+		if errors.Is(err, errPreconditionFailed) {
+			continue // Created by someone else since the journal was listed
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save entry: %w", err)
+		}
+
+		w.stored(name, webdavFile{Path: rel, ETag: etag}, text)
+		return w.saveCache()
+	}
+	return fmt.Errorf("too many entries with same timestamp")
+}
+
+// UpdateEntry replaces the entry at path, unless it was changed on the server since it
+// was read (ErrEntryModified)
+func (w *WebDAVStorage) UpdateEntry(path string, entry *JournalEntry) error {
+	return w.update(path, entry, nil)
+}
+
+// UpdateEntryIfUnchanged replaces an entry only if it still matches the expected version,
+// both locally and on the server
+func (w *WebDAVStorage) UpdateEntryIfUnchanged(path string, entry *JournalEntry, expected EntryVersion) error {
+	return w.update(path, entry, &expected)
+}
+
+// update uploads a changed entry with If-Match on the ETag it was read with
+func (w *WebDAVStorage) update(name string, entry *JournalEntry, expected *EntryVersion) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	file, ok := w.files[name]
+	if !ok {
+		return fmt.Errorf("entry not found: %s", name)
+	}
+	if expected != nil {
+		version, err := w.MemoryStorage.GetEntryVersion(name)
+		if err != nil {
+			return err
+		}
+		if version.Hash != expected.Hash {
+			return ErrEntryModified
+		}
+	}
+
+	text := SerializeEntry(entry)
+	etag, err := w.put(file.Path, text, ifMatch(file.ETag))
+	if errors.Is(err, errPreconditionFailed) {
+		// Load the other version, so it can be compared with this one
+		if err := w.refresh(name); err != nil {
+			return err
+		}
+		return ErrEntryModified
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update entry: %w", err)
+	}
+
+	w.stored(name, webdavFile{Path: file.Path, ETag: etag}, text)
+	return w.saveCache()
+}
+
+// DeleteEntry removes the entry at path, unless it was changed on the server since it
+// was read (ErrEntryModified)
+func (w *WebDAVStorage) DeleteEntry(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	file, ok := w.files[path]
+	if !ok {
+		return fmt.Errorf("entry not found: %s", path)
+	}
+
+	resp, err := w.do(http.MethodDelete, file.Path, nil, ifMatch(file.ETag))
+	if err != nil {
+		return fmt.Errorf("failed to delete entry: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		if err := w.refresh(path); err != nil {
+			return err
+		}
+		return ErrEntryModified
+	case resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound:
+		return fmt.Errorf("failed to delete entry: %w", webdavStatusError("DELETE", file.Path, resp))
+	}
+
+	w.forget(path)
+	return w.saveCache()
+}
+
+// DeleteEntries removes the entries matching filter (ignoring its limit and offset)
+// Returns the paths of the deleted entries
+func (w *WebDAVStorage) DeleteEntries(filter EntryFilter) ([]string, error) {
+	deleted := []string{}
+	for _, path := range w.paths() {
+		entry, err := w.GetEntryByPath(path)
+		if err != nil || !filter.Matches(entry.Timestamp) {
+			continue
+		}
+		if err := w.DeleteEntry(path); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, path)
+	}
+	return deleted, nil
+}
+
+// ReplaceTagInEntries replaces oldTag with newTag in all entries
+func (w *WebDAVStorage) ReplaceTagInEntries(oldTag, newTag string, dryRun bool) ([]string, error) {
+	paths, err := w.GetEntriesWithTag(oldTag)
+	if err != nil {
+		return nil, err
+	}
+	return replaceMetadataInEntries(w, oldTag, newTag, "#", paths, dryRun)
+}
+
+// ReplaceMentionInEntries replaces oldMention with newMention in all entries
+func (w *WebDAVStorage) ReplaceMentionInEntries(oldMention, newMention string, dryRun bool) ([]string, error) {
+	paths, err := w.GetEntriesWithMention(oldMention)
+	if err != nil {
+		return nil, err
+	}
+	return replaceMetadataInEntries(w, oldMention, newMention, "@", paths, dryRun)
+}
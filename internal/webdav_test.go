@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/webdav"
+)
+
+// newWebDAVServer starts a WebDAV server on a temporary directory, returning the server
+// and its directory. Requests need basic auth as user "journal", password "secret".
+func newWebDAVServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	handler := &webdav.Handler{FileSystem: webdav.Dir(dir), LockSystem: webdav.NewMemLS()}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "journal" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// x/net/webdav ignores If-Match and If-None-Match; check them like other servers
+		if r.Method == http.MethodPut || r.Method == http.MethodDelete {
+			head := httptest.NewRecorder()
+			handler.ServeHTTP(head, httptest.NewRequest(http.MethodHead, r.URL.Path, nil))
+			exists := head.Code == http.StatusOK
+			ifMatch := r.Header.Get("If-Match")
+			if (ifMatch != "" && (!exists || ifMatch != head.Header().Get("ETag"))) ||
+				(r.Header.Get("If-None-Match") == "*" && exists) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, dir
+}
+
+// openTestWebDAV opens the journal at the server's /journal/ collection with its own cache
+func openTestWebDAV(t *testing.T, server *httptest.Server, dir string) *WebDAVStorage {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "journal"), DirPermissions); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	config := DefaultConfig()
+	config.WebDAVUser, config.WebDAVPassword = "journal", "secret"
+	config.WebDAVCache = t.TempDir()
+	storage, err := OpenWebDAVStorage(server.URL+"/journal", config)
+	if err != nil {
+		t.Fatalf("OpenWebDAVStorage() error = %v", err)
+	}
+	return storage
+}
+
+func TestWebDAVStorage_SaveAndList(t *testing.T) {
+	server, dir := newWebDAVServer(t)
+	storage := openTestWebDAV(t, server, dir)
+
+	ts := time.Date(2024, 2, 9, 14, 30, 0, 0, time.UTC)
+	for _, body := range []string{"First #work entry", "Same second"} {
+		if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: body}); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+
+	// Stored in year and month collections, with a collision suffix
+	for _, name := range []string{"2024-02-09-14-30-00.md", "2024-02-09-14-30-00-01.md"} {
+		if _, err := os.Stat(filepath.Join(dir, "journal", "2024", "02", name)); err != nil {
+			t.Errorf("%s not on server: %v", name, err)
+		}
+	}
+
+	// Another client lists them
+	other := openTestWebDAV(t, server, dir)
+	entries, err := other.ListEntries(EntryFilter{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ListEntries() = %d entries, %v; want 2", len(entries), err)
+	}
+	found, err := other.SearchByTags([]string{"work"}, EntryFilter{})
+	if err != nil || len(found) != 1 {
+		t.Errorf("SearchByTags() = %d entries, %v; want 1", len(found), err)
+	}
+}
+
+func TestWebDAVStorage_ConcurrentChanges(t *testing.T) {
+	server, dir := newWebDAVServer(t)
+	alice := openTestWebDAV(t, server, dir)
+	ts := time.Date(2024, 2, 9, 14, 30, 0, 0, time.UTC)
+	if err := alice.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Original"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	bob := openTestWebDAV(t, server, dir)
+	path, _ := bob.GetEntryPath(ts)
+	version, _ := bob.GetEntryVersion(path)
+
+	// Alice's update makes Bob's copy stale
+	if err := alice.UpdateEntry(path, &JournalEntry{Timestamp: ts, Body: "Alice's edit"}); err != nil {
+		t.Fatalf("UpdateEntry() error = %v", err)
+	}
+	err := bob.UpdateEntryIfUnchanged(path, &JournalEntry{Timestamp: ts, Body: "Bob's edit"}, version)
+	if !errors.Is(err, ErrEntryModified) {
+		t.Fatalf("UpdateEntryIfUnchanged() error = %v, want ErrEntryModified", err)
+	}
+
+	// Bob now sees Alice's version and can update on top of it
+	theirs, err := bob.GetEntryByPath(path)
+	if err != nil || theirs.Body != "Alice's edit" {
+		t.Fatalf("GetEntryByPath() = %v, %v; want Alice's edit", theirs, err)
+	}
+	version, _ = bob.GetEntryVersion(path)
+	if err := bob.UpdateEntryIfUnchanged(path, &JournalEntry{Timestamp: ts, Body: "Bob's edit"}, version); err != nil {
+		t.Fatalf("UpdateEntryIfUnchanged() after refresh error = %v", err)
+	}
+
+	// Alice's copy is stale now, so her delete is refused
+	if err := alice.DeleteEntry(path); !errors.Is(err, ErrEntryModified) {
+		t.Errorf("DeleteEntry() error = %v, want ErrEntryModified", err)
+	}
+	if err := alice.DeleteEntry(path); err != nil {
+		t.Errorf("DeleteEntry() after refresh error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "journal", "2024", "02", path)); !os.IsNotExist(err) {
+		t.Errorf("entry still on server: %v", err)
+	}
+}
+
+func TestWebDAVStorage_OfflineReads(t *testing.T) {
+	server, dir := newWebDAVServer(t)
+	storage := openTestWebDAV(t, server, dir)
+	ts := time.Date(2024, 2, 9, 14, 30, 0, 0, time.UTC)
+	if err := storage.SaveEntry(&JournalEntry{Timestamp: ts, Body: "Cached entry"}); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	config := DefaultConfig()
+	config.WebDAVUser, config.WebDAVPassword = "journal", "secret"
+	config.WebDAVCache = filepath.Dir(storage.cacheDir)
+
+	// Another journal using the same cache directory keeps its own cache
+	if err := os.MkdirAll(filepath.Join(dir, "other"), DirPermissions); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	other, err := OpenWebDAVStorage(server.URL+"/other", config)
+	if err != nil {
+		t.Fatalf("OpenWebDAVStorage(other) error = %v", err)
+	}
+	if other.cacheDir == storage.cacheDir {
+		t.Errorf("both journals cached in %s", other.cacheDir)
+	}
+	server.Close()
+
+	offline, err := OpenWebDAVStorage(server.URL+"/journal", config)
+	if err != nil {
+		t.Fatalf("OpenWebDAVStorage() while offline error = %v", err)
+	}
+	if !offline.Offline() {
+		t.Error("Offline() = false, want true")
+	}
+	entry, err := offline.GetEntry(ts)
+	if err != nil || entry.Body != "Cached entry" {
+		t.Errorf("GetEntry() = %v, %v; want cached entry", entry, err)
+	}
+	if err := offline.SaveEntry(&JournalEntry{Timestamp: ts, Body: "New"}); err == nil {
+		t.Error("SaveEntry() while offline: expected error")
+	}
+
+	// A journal never opened here has nothing to serve
+	if _, err := OpenWebDAVStorage(server.URL+"/never-opened", config); err == nil {
+		t.Error("OpenWebDAVStorage() of an uncached journal while offline: expected error")
+	}
+}
+
+func TestWebDAVStorage_Errors(t *testing.T) {
+	server, _ := newWebDAVServer(t)
+
+	config := DefaultConfig()
+	config.WebDAVCache = t.TempDir()
+	if _, err := OpenWebDAVStorage(server.URL+"/journal", config); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("OpenWebDAVStorage() without credentials error = %v, want 401", err)
+	}
+
+	config.WebDAVUser, config.WebDAVPassword = "journal", "secret"
+	if _, err := OpenWebDAVStorage(server.URL+"/missing", config); err == nil {
+		t.Error("OpenWebDAVStorage() of a missing collection: expected error")
+	}
+	if _, err := OpenWebDAVStorage("ftp://example.com/journal", config); err == nil {
+		t.Error("OpenWebDAVStorage() with an ftp URL: expected error")
+	}
+}
@@ -4,27 +4,16 @@ import (
 	"fmt"
 	"os"
 	"runtime/debug"
+	"time"
 
 	"github.com/alecthomas/kong"
+	"golang.org/x/term"
 
 	"github.com/jashort/jrnlg/internal"
 	"github.com/jashort/jrnlg/internal/cli"
 )
 
 func main() {
-	// Load configuration
-	config, err := internal.LoadConfig()
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Initialize storage
-	storage := internal.NewFileSystemStorage(config.StoragePath, config)
-
-	// Create CLI app
-	app := cli.NewApp(storage, config)
-
 	// Handle no-args case: default to add command
 	args := os.Args[1:]
 	if len(args) == 0 {
@@ -55,6 +44,46 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Load configuration of the selected journal
+	config, err := internal.LoadJournalConfig(cliStruct.SelectedJournal())
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Write and show entries in the configured time zone, unless TZ says otherwise
+	if location := config.Location(); location != nil && os.Getenv("TZ") == "" {
+		time.Local = location
+	}
+
+	// Initialize storage
+	storage, err := internal.NewStorage(config)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error opening journal: %v\n", err)
+		os.Exit(1)
+	}
+	if webdav, ok := storage.(*internal.WebDAVStorage); ok && webdav.Offline() {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: WebDAV server unreachable, showing cached entries\n")
+	}
+
+	// Commands that work on the storage directory need a journal stored in one
+	if err := cli.CheckStorage(ctx, config, storage); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Say which journal is in use, unless it's the usual one
+	if label := config.JournalLabel(); label != "" && term.IsTerminal(int(os.Stderr.Fd())) {
+		_, _ = fmt.Fprintf(os.Stderr, "Journal: %s\n", label)
+	}
+
+	// Create CLI app
+	app := cli.NewApp(storage, config)
+	if err := app.SetColor(cliStruct.Color); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Run the command
 	cmdCtx := &cli.Context{
 		CLI: &cliStruct,